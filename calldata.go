@@ -14,8 +14,12 @@ import (
 	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/erc20"
 	negriskadapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-adapter"
 	neg_risk_ctf_collateral_adapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-ctf-collateral-adapter"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
 )
 
+// SafeMultiSendCallOnly is the Safe v1.3.0 MultiSendCallOnly, delegate-called by a Safe to execute a batch of calls in one transaction
+var SafeMultiSendCallOnly = common.HexToAddress("0x40A2aCCbd92BCA938b02010E17A5b8929b49130D")
+
 // V1 calldata builders — regular markets (CTF direct)
 
 func buildSplitPositionCall(ctf, collateral common.Address, conditionId [32]byte, partition []*big.Int, amount *big.Int) (contractCall, error) {
@@ -54,7 +58,7 @@ func buildRedeemPositionsCall(ctf, collateral common.Address, conditionId [32]by
 	if err != nil {
 		return contractCall{}, fmt.Errorf("failed to pack redeemPositions calldata: %w", err)
 	}
-	return contractCall{Target: ctf, Calldata: calldata, Value: big.NewInt(0)}, nil
+	return contractCall{Target: ctf, Calldata: calldata, Value: big.NewInt(0), Urgency: sender.UrgencyLow}, nil
 }
 
 // V1 calldata builders — NegRisk markets (NegRiskAdapter)
@@ -92,12 +96,30 @@ func buildRedeemNegRiskCall(adapter common.Address, conditionId [32]byte, amount
 	if err != nil {
 		return contractCall{}, fmt.Errorf("failed to pack redeemPositions calldata: %w", err)
 	}
+	return contractCall{Target: adapter, Calldata: calldata, Value: big.NewInt(0), Urgency: sender.UrgencyLow}, nil
+}
+
+// buildConvertPositionsCall converts NO positions of a neg-risk market. The NegRiskAdapter and the
+// NegRiskCtfCollateralAdapter share the convertPositions signature.
+func buildConvertPositionsCall(adapter common.Address, marketId [32]byte, indexSet *big.Int, amount *big.Int) (contractCall, error) {
+	parsedABI, err := negriskadapter.NegRiskAdapterMetaData.GetAbi()
+	if err != nil {
+		return contractCall{}, fmt.Errorf("failed to parse NegRiskAdapter ABI: %w", err)
+	}
+	calldata, err := parsedABI.Pack("convertPositions", marketId, indexSet, amount)
+	if err != nil {
+		return contractCall{}, fmt.Errorf("failed to pack convertPositions calldata: %w", err)
+	}
 	return contractCall{Target: adapter, Calldata: calldata, Value: big.NewInt(0)}, nil
 }
 
-// V2 calldata builders — pUSD via CtfCollateralAdapter (regular markets)
+// V2 calldata builders — pUSD via CtfCollateralAdapter (regular markets).
+// The adapter always splits, merges and redeems [1, 2], so other partitions are rejected.
 
 func buildAdapterSplitCall(adapter common.Address, conditionId [32]byte, partition []*big.Int, amount *big.Int) (contractCall, error) {
+	if err := validateAdapterPartition(partition, true); err != nil {
+		return contractCall{}, err
+	}
 	parsedABI, err := ctf_collateral_adapter.CtfCollateralAdapterMetaData.GetAbi()
 	if err != nil {
 		return contractCall{}, fmt.Errorf("failed to parse CtfCollateralAdapter ABI: %w", err)
@@ -110,6 +132,9 @@ func buildAdapterSplitCall(adapter common.Address, conditionId [32]byte, partiti
 }
 
 func buildAdapterMergeCall(adapter common.Address, conditionId [32]byte, partition []*big.Int, amount *big.Int) (contractCall, error) {
+	if err := validateAdapterPartition(partition, true); err != nil {
+		return contractCall{}, err
+	}
 	parsedABI, err := ctf_collateral_adapter.CtfCollateralAdapterMetaData.GetAbi()
 	if err != nil {
 		return contractCall{}, fmt.Errorf("failed to parse CtfCollateralAdapter ABI: %w", err)
@@ -122,6 +147,9 @@ func buildAdapterMergeCall(adapter common.Address, conditionId [32]byte, partiti
 }
 
 func buildAdapterRedeemCall(adapter common.Address, conditionId [32]byte, indexSets []*big.Int) (contractCall, error) {
+	if err := validateAdapterPartition(indexSets, false); err != nil {
+		return contractCall{}, err
+	}
 	parsedABI, err := ctf_collateral_adapter.CtfCollateralAdapterMetaData.GetAbi()
 	if err != nil {
 		return contractCall{}, fmt.Errorf("failed to parse CtfCollateralAdapter ABI: %w", err)
@@ -130,7 +158,7 @@ func buildAdapterRedeemCall(adapter common.Address, conditionId [32]byte, indexS
 	if err != nil {
 		return contractCall{}, fmt.Errorf("failed to pack adapter redeemPositions calldata: %w", err)
 	}
-	return contractCall{Target: adapter, Calldata: calldata, Value: big.NewInt(0)}, nil
+	return contractCall{Target: adapter, Calldata: calldata, Value: big.NewInt(0), Urgency: sender.UrgencyLow}, nil
 }
 
 // V2 calldata builders — pUSD via NegRiskCtfCollateralAdapter (neg-risk markets)
@@ -168,7 +196,7 @@ func buildNegRiskAdapterRedeemCall(adapter common.Address, conditionId [32]byte,
 	if err != nil {
 		return contractCall{}, fmt.Errorf("failed to pack neg-risk adapter redeemPositions calldata: %w", err)
 	}
-	return contractCall{Target: adapter, Calldata: calldata, Value: big.NewInt(0)}, nil
+	return contractCall{Target: adapter, Calldata: calldata, Value: big.NewInt(0), Urgency: sender.UrgencyLow}, nil
 }
 
 // Wrap/Unwrap calldata builders
@@ -222,3 +250,61 @@ func buildSetApprovalForAllCall(ctf, operator common.Address, approved bool) (co
 	}
 	return contractCall{Target: ctf, Calldata: calldata, Value: big.NewInt(0)}, nil
 }
+
+// buildPositionTransferCall transfers positions with safeTransferFrom for a single ID, safeBatchTransferFrom otherwise
+func buildPositionTransferCall(ctf, from, to common.Address, ids, amounts []*big.Int) (contractCall, error) {
+	if len(ids) == 0 || len(ids) != len(amounts) {
+		return contractCall{}, fmt.Errorf("got %d amounts for %d positions", len(amounts), len(ids))
+	}
+	parsedABI, err := conditional_tokens.ConditionalTokensMetaData.GetAbi()
+	if err != nil {
+		return contractCall{}, fmt.Errorf("failed to parse ConditionalTokens ABI: %w", err)
+	}
+	var calldata []byte
+	if len(ids) == 1 {
+		calldata, err = parsedABI.Pack("safeTransferFrom", from, to, ids[0], amounts[0], []byte{})
+	} else {
+		calldata, err = parsedABI.Pack("safeBatchTransferFrom", from, to, ids, amounts, []byte{})
+	}
+	if err != nil {
+		return contractCall{}, fmt.Errorf("failed to pack position transfer calldata: %w", err)
+	}
+	return contractCall{Target: ctf, Calldata: calldata, Value: big.NewInt(0)}, nil
+}
+
+// Batch calldata builders
+
+// buildMultiSendCall batches calls into a delegate call of multiSend(bytes) on a MultiSendCallOnly.
+// The batch is as urgent as its most urgent call.
+func buildMultiSendCall(multiSend common.Address, calls []contractCall) (contractCall, error) {
+	var packed []byte
+	urgency := sender.UrgencyLow
+	for i, call := range calls {
+		if call.Operation != SafeOperationCall {
+			return contractCall{}, fmt.Errorf("failed to batch call %d: MultiSendCallOnly only makes calls", i)
+		}
+		value := call.Value
+		if value == nil {
+			value = big.NewInt(0)
+		}
+		packed = append(packed, byte(SafeOperationCall))
+		packed = append(packed, call.Target.Bytes()...)
+		packed = append(packed, common.LeftPadBytes(value.Bytes(), 32)...)
+		packed = append(packed, common.LeftPadBytes(big.NewInt(int64(len(call.Calldata))).Bytes(), 32)...)
+		packed = append(packed, call.Calldata...)
+		if call.Urgency == sender.UrgencyHigh || call.Urgency == sender.UrgencyNormal && urgency == sender.UrgencyLow {
+			urgency = call.Urgency
+		}
+	}
+	calldata, err := multiSendArguments.Pack(packed)
+	if err != nil {
+		return contractCall{}, fmt.Errorf("failed to pack multiSend calldata: %w", err)
+	}
+	return contractCall{
+		Target:    multiSend,
+		Calldata:  append(append([]byte{}, multiSendSelector...), calldata...),
+		Value:     big.NewInt(0),
+		Urgency:   urgency,
+		Operation: SafeOperationDelegateCall,
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
 )
 
 // ContractConfig holds all contract addresses for Polymarket
@@ -19,6 +20,10 @@ type ContractConfig struct {
 	NegRiskExchange   common.Address // V1 NegRisk exchange
 	SafeProxyFactory  common.Address
 
+	// NegRiskWrappedCollateral is the NegRisk adapter's wrapped USDC.e, the collateral of neg-risk positions.
+	// It is deployed by the adapter's constructor.
+	NegRiskWrappedCollateral common.Address
+
 	// V2 fields (zero-value = V2 not configured)
 	USDC                        common.Address // Native USDC (0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359) — NOT USDC.e
 	ExchangeV2                  common.Address // CTF Exchange V2
@@ -38,6 +43,8 @@ var AMOY_CONTRACTS = &ContractConfig{
 	Collateral:        common.HexToAddress("0x9c4e1703476e875070ee25b56a58b008cfb8fa78"),
 	ConditionalTokens: common.HexToAddress("0x69308FB512518e39F9b16112fA8d994F4e2Bf8bB"),
 	SafeProxyFactory:  common.HexToAddress("0xaacFeEa03eb1561C4e67d661e40682Bd20E3541b"),
+
+	NegRiskWrappedCollateral: common.HexToAddress("0x3A3BD7bb9528E159577F7C2e685CC81A765002E2"),
 }
 
 var MATIC_CONTRACTS = &ContractConfig{
@@ -48,6 +55,8 @@ var MATIC_CONTRACTS = &ContractConfig{
 	Collateral:        common.HexToAddress("0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"), // USDC.e
 	ConditionalTokens: common.HexToAddress("0x4D97DCd97eC945f40cF65F87097ACe5EA0476045"),
 	SafeProxyFactory:  common.HexToAddress("0xaacFeEa03eb1561C4e67d661e40682Bd20E3541b"),
+
+	NegRiskWrappedCollateral: common.HexToAddress("0x3A3BD7bb9528E159577F7C2e685CC81A765002E2"),
 	// V2
 	USDC:                        common.HexToAddress("0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359"),
 	ExchangeV2:                  common.HexToAddress("0xE111180000d2663C0091e4f400237545B87B996B"),
@@ -69,3 +78,23 @@ func GetContractConfig(chainID *big.Int) *ContractConfig {
 		panic("Invalid network")
 	}
 }
+
+// registerRevertAddresses names the configured contracts in the default revert decoder,
+// so errors shared by several contracts (e.g. Paused()) are attributed to the one that was called.
+func registerRevertAddresses(config *ContractConfig) {
+	if config == nil {
+		return
+	}
+	d := revert.Default()
+	d.RegisterAddress(config.Exchange, "Exchange")
+	d.RegisterAddress(config.NegRiskExchange, "NegRiskExchange")
+	d.RegisterAddress(config.NegRiskAdapter, "NegRiskAdapter")
+	d.RegisterAddress(config.ExchangeV2, "ExchangeV2")
+	d.RegisterAddress(config.NegRiskExchangeV2, "NegRiskExchangeV2")
+	d.RegisterAddress(config.CollateralToken, "CollateralToken")
+	d.RegisterAddress(config.CollateralOnramp, "CollateralOnramp")
+	d.RegisterAddress(config.CollateralOfframp, "CollateralOfframp")
+	d.RegisterAddress(config.CtfCollateralAdapter, "CtfCollateralAdapter")
+	d.RegisterAddress(config.NegRiskCtfCollateralAdapter, "NegRiskCtfCollateralAdapter")
+	d.RegisterAddress(config.PermissionedRamp, "PermissionedRamp")
+}
@@ -0,0 +1,210 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+// ErrInvalidConversion is returned for a conversion NegRiskAdapter.convertPositions would reject
+var ErrInvalidConversion = errors.New("invalid conversion")
+
+// negRiskFeeDenominator is NegRiskAdapter.FEE_DENOMINATOR: fees are in basis points
+var negRiskFeeDenominator = big.NewInt(10_000)
+
+// NegRiskQuestionId returns the ID of the question at index of a neg-risk market, like NegRiskIdLib.getQuestionId
+func NegRiskQuestionId(marketId [32]byte, index uint8) [32]byte {
+	questionId := marketId
+	questionId[31] += index
+	return questionId
+}
+
+// ConvertIndexSet returns the index set of convertPositions selecting the NO positions of the questions at questionIndices
+func ConvertIndexSet(questionIndices ...int) *big.Int {
+	return IndexSet(questionIndices...)
+}
+
+// ConversionPreview is the outcome of converting NO positions of a neg-risk market
+type ConversionPreview struct {
+	MarketId [32]byte
+	IndexSet *big.Int
+	// Amount is burnt of each NO position of NoPositionIds
+	Amount        *big.Int
+	NoPositionIds []*big.Int
+	FeeBips       *big.Int
+	// AmountOut is Amount net of the fee, received of each YES position of YesPositionIds
+	AmountOut      *big.Int
+	YesPositionIds []*big.Int
+	// Collateral is received for the NO positions beyond the first: AmountOut each
+	Collateral *big.Int
+}
+
+// previewConversion validates a conversion against the market and computes its outcome net of the market's fee
+func (r *portfolioReader) previewConversion(ctx context.Context, marketId [32]byte, indexSet, amount *big.Int) (*ConversionPreview, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidConversion)
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	oracle, err := r.negRiskAdapter.GetOracle(opts, marketId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oracle of market %x: %w", marketId, err)
+	}
+	if oracle == (common.Address{}) {
+		return nil, fmt.Errorf("%w: market %x is not prepared", ErrInvalidConversion, marketId)
+	}
+	count, err := r.negRiskAdapter.GetQuestionCount(opts, marketId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question count of market %x: %w", marketId, err)
+	}
+	questionCount := int(count.Int64())
+	if questionCount <= 1 {
+		return nil, fmt.Errorf("%w: market %x has no convertible positions", ErrInvalidConversion, marketId)
+	}
+	if indexSet == nil || indexSet.Sign() <= 0 || indexSet.BitLen() > questionCount {
+		return nil, fmt.Errorf("%w: index set %v is not a non-empty subset of %d questions", ErrInvalidConversion, indexSet, questionCount)
+	}
+	feeBips, err := r.negRiskAdapter.GetFeeBips(opts, marketId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee of market %x: %w", marketId, err)
+	}
+
+	fee := new(big.Int).Div(new(big.Int).Mul(amount, feeBips), negRiskFeeDenominator)
+	preview := &ConversionPreview{
+		MarketId:   marketId,
+		IndexSet:   indexSet,
+		Amount:     amount,
+		FeeBips:    feeBips,
+		AmountOut:  new(big.Int).Sub(amount, fee),
+		Collateral: new(big.Int),
+	}
+	for i := 0; i < questionCount; i++ {
+		questionId := NegRiskQuestionId(marketId, uint8(i))
+		if indexSet.Bit(i) == 1 {
+			preview.NoPositionIds = append(preview.NoPositionIds, ComputeNegRiskPositionId(r.config.NegRiskAdapter, r.config.NegRiskWrappedCollateral, questionId, false))
+		} else {
+			preview.YesPositionIds = append(preview.YesPositionIds, ComputeNegRiskPositionId(r.config.NegRiskAdapter, r.config.NegRiskWrappedCollateral, questionId, true))
+		}
+	}
+	preview.Collateral.Mul(preview.AmountOut, big.NewInt(int64(len(preview.NoPositionIds)-1)))
+	return preview, nil
+}
+
+// convertPositions converts NO positions of account through adapter, approving it on the ConditionalTokens
+// first if needed. A Safe (safeSigner set) sends the approval and conversion in one MultiSend transaction,
+// an EOA sends the conversion once the approval is mined.
+func (r *portfolioReader) convertPositions(ctx context.Context, e *txExecutor, safeSigner signer.SafeTradingSigner, chainID *big.Int, account, adapter common.Address, marketId [32]byte, indexSet, amount *big.Int) ([]common.Hash, error) {
+	if _, err := r.previewConversion(ctx, marketId, indexSet, amount); err != nil {
+		return nil, err
+	}
+	approved, err := r.ctf.IsApprovedForAll(&bind.CallOpts{Context: ctx}, account, adapter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ConditionalTokens approval: %w", err)
+	}
+
+	var calls []contractCall
+	if !approved {
+		call, err := buildSetApprovalForAllCall(r.config.ConditionalTokens, adapter, true)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	call, err := buildConvertPositionsCall(adapter, marketId, indexSet, amount)
+	if err != nil {
+		return nil, err
+	}
+	calls = append(calls, call)
+
+	var txHashes []common.Hash
+	if safeSigner == nil && len(calls) > 1 {
+		// The conversion's gas cannot be estimated until the approval is mined
+		txHashes, err = e.executeAfterApproval(ctx, calls[0], calls[1])
+	} else {
+		txHashes, err = e.executeMultiSend(ctx, safeSigner, chainID, calls)
+	}
+	if err != nil {
+		return txHashes, fmt.Errorf("failed to convert positions: %w", err)
+	}
+	return txHashes, nil
+}
+
+// PreviewConvertPositions returns the positions and USDC.e received for converting amount of each NO position of
+// indexSet, net of the market's fee
+func (b *ContractInterface) PreviewConvertPositions(ctx context.Context, marketId [32]byte, indexSet, amount *big.Int) (*ConversionPreview, error) {
+	return b.portfolio.previewConversion(ctx, marketId, indexSet, amount)
+}
+
+// ConvertPositions converts amount of each NO position of indexSet into YES positions of the other questions and USDC.e
+func (b *ContractInterface) ConvertPositions(ctx context.Context, marketId [32]byte, indexSet, amount *big.Int) ([]common.Hash, error) {
+	switch b.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		return b.ConvertPositionsForSafe(ctx, b.getSafeTradingSigner(), b.chainID, marketId, indexSet, amount)
+	case SignatureTypeEOA:
+		return b.ConvertPositionsForEOA(ctx, b.getEOATradingSigner(), marketId, indexSet, amount)
+	default:
+		return nil, fmt.Errorf("unsupported signature type: %v", b.signatureType)
+	}
+}
+
+// ConvertPositionsForEOA converts NO positions of an EOA through the NegRiskAdapter
+func (b *ContractInterface) ConvertPositionsForEOA(ctx context.Context, eoaSigner signer.EOATradingSigner, marketId [32]byte, indexSet, amount *big.Int) ([]common.Hash, error) {
+	owner, err := b.executor.eoaAddress(eoaSigner)
+	if err != nil {
+		return nil, err
+	}
+	return b.portfolio.convertPositions(ctx, b.executor, nil, nil, owner, b.contractConfig.NegRiskAdapter, marketId, indexSet, amount)
+}
+
+// ConvertPositionsForSafe converts NO positions of a Safe through the NegRiskAdapter in one transaction
+func (b *ContractInterface) ConvertPositionsForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, marketId [32]byte, indexSet, amount *big.Int) ([]common.Hash, error) {
+	safeAddr, err := b.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return b.portfolio.convertPositions(ctx, b.executor, safeSigner, chainID, safeAddr, b.contractConfig.NegRiskAdapter, marketId, indexSet, amount)
+}
+
+// PreviewConvertPositions returns the positions and pUSD received for converting amount of each NO position of
+// indexSet, net of the market's fee
+func (v *ContractInterfaceV2) PreviewConvertPositions(ctx context.Context, marketId [32]byte, indexSet, amount *big.Int) (*ConversionPreview, error) {
+	return v.portfolio.previewConversion(ctx, marketId, indexSet, amount)
+}
+
+// ConvertPositions converts amount of each NO position of indexSet into YES positions of the other questions and pUSD
+func (v *ContractInterfaceV2) ConvertPositions(ctx context.Context, marketId [32]byte, indexSet, amount *big.Int) ([]common.Hash, error) {
+	switch v.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		s, err := v.getSafeTradingSignerOrErr()
+		if err != nil {
+			return nil, err
+		}
+		return v.ConvertPositionsForSafe(ctx, s, v.chainID, marketId, indexSet, amount)
+	case SignatureTypeEOA:
+		return v.ConvertPositionsForEOA(ctx, marketId, indexSet, amount)
+	default:
+		return nil, fmt.Errorf("unsupported signature type: %v", v.signatureType)
+	}
+}
+
+// ConvertPositionsForEOA converts NO positions of the EOA through the NegRiskCtfCollateralAdapter
+func (v *ContractInterfaceV2) ConvertPositionsForEOA(ctx context.Context, marketId [32]byte, indexSet, amount *big.Int) ([]common.Hash, error) {
+	eoa, err := v.getEOAAddress()
+	if err != nil {
+		return nil, err
+	}
+	return v.portfolio.convertPositions(ctx, v.executor, nil, nil, eoa, v.config.NegRiskCtfCollateralAdapter, marketId, indexSet, amount)
+}
+
+// ConvertPositionsForSafe converts NO positions of a Safe through the NegRiskCtfCollateralAdapter in one transaction
+func (v *ContractInterfaceV2) ConvertPositionsForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, marketId [32]byte, indexSet, amount *big.Int) ([]common.Hash, error) {
+	safeAddr, err := v.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return v.portfolio.convertPositions(ctx, v.executor, safeSigner, chainID, safeAddr, v.config.NegRiskCtfCollateralAdapter, marketId, indexSet, amount)
+}
@@ -0,0 +1,159 @@
+package polymarketcontracts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+var testMarketId = [32]byte{0xAB}
+
+func newConvertChain(t *testing.T) (*portfolioChain, *portfolioReader) {
+	chain := newPortfolioChain(t)
+	chain.markets[testMarketId] = &testNegRiskMarket{oracle: common.HexToAddress("0x0A"), questions: 3, feeBips: 100}
+	chain.markets[[32]byte{0xCD}] = &testNegRiskMarket{oracle: common.HexToAddress("0x0A"), questions: 1}
+	reader, err := newPortfolioReader(MATIC_CONTRACTS, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	return chain, reader
+}
+
+func TestPreviewConversion(t *testing.T) {
+	c := MATIC_CONTRACTS
+	_, reader := newConvertChain(t)
+
+	preview, err := reader.previewConversion(context.Background(), testMarketId, ConvertIndexSet(0, 1), big.NewInt(1e6))
+	if err != nil {
+		t.Fatalf("previewConversion: %v", err)
+	}
+	no0 := ComputeNegRiskPositionId(c.NegRiskAdapter, c.NegRiskWrappedCollateral, NegRiskQuestionId(testMarketId, 0), false)
+	yes2 := ComputeNegRiskPositionId(c.NegRiskAdapter, c.NegRiskWrappedCollateral, NegRiskQuestionId(testMarketId, 2), true)
+	if len(preview.NoPositionIds) != 2 || preview.NoPositionIds[0].Cmp(no0) != 0 || len(preview.YesPositionIds) != 1 || preview.YesPositionIds[0].Cmp(yes2) != 0 {
+		t.Errorf("positions: NO %v, YES %v", preview.NoPositionIds, preview.YesPositionIds)
+	}
+	// 1% fee: 990000 YES of question 2 and 990000 collateral for the second NO position
+	if preview.AmountOut.Int64() != 990_000 || preview.Collateral.Int64() != 990_000 {
+		t.Errorf("amount out %s, collateral %s", preview.AmountOut, preview.Collateral)
+	}
+
+	for name, tt := range map[string]struct {
+		marketId [32]byte
+		indexSet *big.Int
+	}{
+		"empty index set":       {testMarketId, ConvertIndexSet()},
+		"question out of range": {testMarketId, ConvertIndexSet(0, 3)},
+		"unprepared market":     {[32]byte{0xEF}, ConvertIndexSet(0)},
+		"single question":       {[32]byte{0xCD}, ConvertIndexSet(0)},
+	} {
+		if _, err := reader.previewConversion(context.Background(), tt.marketId, tt.indexSet, big.NewInt(1)); !errors.Is(err, ErrInvalidConversion) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+}
+
+func TestConvertPositionsForSafe_ApprovesAdapter(t *testing.T) {
+	chain, reader := newConvertChain(t)
+	var sent []contractCall
+	b := &ContractInterface{
+		contractConfig: MATIC_CONTRACTS,
+		portfolio:      reader,
+		executor: &txExecutor{
+			getSafeAddr: func(common.Address) (common.Address, error) { return testSafe, nil },
+			execSafeTx: func(_ context.Context, _ signer.SafeTradingSigner, _ *big.Int, _, to common.Address, value *big.Int, data []byte, op SafeOperation, _ *big.Int, _ ...sender.SendOption) (common.Hash, error) {
+				sent = append(sent, contractCall{Target: to, Calldata: data, Value: value, Operation: op})
+				return common.HexToHash("0x01"), nil
+			},
+		},
+	}
+	indexSet, amount := ConvertIndexSet(1), big.NewInt(5e6)
+	approve, _ := buildSetApprovalForAllCall(MATIC_CONTRACTS.ConditionalTokens, MATIC_CONTRACTS.NegRiskAdapter, true)
+	convert, _ := buildConvertPositionsCall(MATIC_CONTRACTS.NegRiskAdapter, testMarketId, indexSet, amount)
+
+	if _, err := b.ConvertPositionsForSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), testMarketId, indexSet, amount); err != nil {
+		t.Fatalf("ConvertPositionsForSafe: %v", err)
+	}
+	if len(sent) != 1 || sent[0].Target != SafeMultiSendCallOnly || !bytes.Equal(sent[0].Calldata, packMultiSend(t, approve, convert)) {
+		t.Errorf("sent %+v, want the approval and conversion batched", sent)
+	}
+
+	chain.approvals[MATIC_CONTRACTS.NegRiskAdapter] = true
+	if _, err := b.ConvertPositionsForSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), testMarketId, indexSet, amount); err != nil {
+		t.Fatalf("ConvertPositionsForSafe: %v", err)
+	}
+	if len(sent) != 2 || sent[1].Target != MATIC_CONTRACTS.NegRiskAdapter || !bytes.Equal(sent[1].Calldata, convert.Calldata) {
+		t.Errorf("sent %+v, want the conversion alone once approved", sent[1:])
+	}
+}
+
+// approvalCheckingSender records whether the adapter was approved when each transaction was sent
+type approvalCheckingSender struct {
+	addrSender
+	chain    *portfolioChain
+	approved []bool
+}
+
+func (s *approvalCheckingSender) SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error) {
+	s.approved = append(s.approved, s.chain.approvals[MATIC_CONTRACTS.NegRiskAdapter])
+	return s.addrSender.SendEthereumTransaction(to, data, value)
+}
+
+// approvalMiningClient mines the approval once its receipt is asked for
+type approvalMiningClient struct {
+	minedCallClient
+	chain *portfolioChain
+}
+
+func (c *approvalMiningClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.chain.approvals[MATIC_CONTRACTS.NegRiskAdapter] = true
+	return c.minedCallClient.TransactionReceipt(ctx, txHash)
+}
+
+func TestConvertPositionsForEOA_WaitsForApproval(t *testing.T) {
+	chain, reader := newConvertChain(t)
+	eoa := &approvalCheckingSender{addrSender: addrSender{addr: testSafe, mockTransactionSender: mockTransactionSender{retHash: common.HexToHash("0x01")}}, chain: chain}
+	b := &ContractInterface{
+		contractConfig: MATIC_CONTRACTS,
+		portfolio:      reader,
+		executor:       &txExecutor{client: &approvalMiningClient{chain: chain}, txSender: eoa},
+	}
+	indexSet, amount := ConvertIndexSet(1), big.NewInt(5e6)
+	convert, _ := buildConvertPositionsCall(MATIC_CONTRACTS.NegRiskAdapter, testMarketId, indexSet, amount)
+
+	txHashes, err := b.ConvertPositionsForEOA(context.Background(), &mockEOASigner{addr: testSafe}, testMarketId, indexSet, amount)
+	if err != nil {
+		t.Fatalf("ConvertPositionsForEOA: %v", err)
+	}
+	if len(txHashes) != 2 || eoa.calls != 2 || eoa.lastTo != MATIC_CONTRACTS.NegRiskAdapter || !bytes.Equal(eoa.lastData, convert.Calldata) {
+		t.Fatalf("%d transactions, last to %s", eoa.calls, eoa.lastTo.Hex())
+	}
+	if eoa.approved[0] || !eoa.approved[1] {
+		t.Errorf("expected the conversion sent once the approval was mined, approved at each send: %v", eoa.approved)
+	}
+}
+
+func TestV2ConvertPositionsForEOA_UsesCollateralAdapter(t *testing.T) {
+	chain, reader := newConvertChain(t)
+	chain.approvals[MATIC_CONTRACTS.NegRiskCtfCollateralAdapter] = true
+	eoa := &addrSender{addr: testSafe}
+	v := newV2TestInstance(nil)
+	v.executor.txSender = eoa
+	v.portfolio = reader
+
+	if _, err := v.ConvertPositionsForEOA(context.Background(), testMarketId, ConvertIndexSet(0, 2), big.NewInt(1e6)); err != nil {
+		t.Fatalf("ConvertPositionsForEOA: %v", err)
+	}
+	if eoa.calls != 1 || eoa.lastTo != MATIC_CONTRACTS.NegRiskCtfCollateralAdapter {
+		t.Errorf("%d transactions, last to %s", eoa.calls, eoa.lastTo.Hex())
+	}
+	if _, err := v.ConvertPositionsForEOA(context.Background(), testMarketId, ConvertIndexSet(3), big.NewInt(1e6)); !errors.Is(err, ErrInvalidConversion) || eoa.calls != 1 {
+		t.Errorf("got %v after %d transactions", err, eoa.calls)
+	}
+}
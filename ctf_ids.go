@@ -0,0 +1,132 @@
+package polymarketcontracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+)
+
+// Offline derivation of Conditional Tokens IDs, matching CTHelpers of the deployed ConditionalTokens.
+// Collection IDs are compressed alt_bn128 points: the hash of (conditionId, indexSet) is mapped to a
+// curve point and added to the parent collection's point, so nested collections commute.
+
+// ErrInvalidCollectionId is returned when a parent collection ID is not a compressed alt_bn128 point
+var ErrInvalidCollectionId = errors.New("invalid parent collection ID")
+
+var (
+	// altBN128P is the alt_bn128 field modulus
+	altBN128P = common.HexToHash("0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47").Big()
+	// altBN128SqrtExp is (P+1)/4, the square root exponent since P = 3 mod 4
+	altBN128SqrtExp = new(big.Int).Rsh(new(big.Int).Add(altBN128P, big.NewInt(1)), 2)
+	altBN128B       = big.NewInt(3)
+	// collectionIdOddBit flags an odd y coordinate in a compressed collection ID
+	collectionIdOddBit = new(big.Int).Lsh(big.NewInt(1), 254)
+)
+
+// ComputeConditionId returns the ID of the condition prepared by oracle for questionId,
+// like ConditionalTokens.getConditionId
+func ComputeConditionId(oracle common.Address, questionId [32]byte, outcomeSlotCount uint64) [32]byte {
+	return crypto.Keccak256Hash(oracle.Bytes(), questionId[:], common.LeftPadBytes(new(big.Int).SetUint64(outcomeSlotCount).Bytes(), 32))
+}
+
+// ComputeCollectionId returns the ID of the collection of indexSet outcomes of conditionId nested in
+// parentCollectionId (zero for a top-level collection), like ConditionalTokens.getCollectionId
+func ComputeCollectionId(parentCollectionId, conditionId [32]byte, indexSet *big.Int) ([32]byte, error) {
+	if indexSet == nil || indexSet.Sign() <= 0 || indexSet.BitLen() > 256 {
+		return [32]byte{}, fmt.Errorf("invalid index set %v", indexSet)
+	}
+	x1 := crypto.Keccak256Hash(conditionId[:], common.LeftPadBytes(indexSet.Bytes(), 32)).Big()
+	odd := x1.Bit(255) == 1
+	var y1 *big.Int
+	for {
+		x1.Add(x1, big.NewInt(1)).Mod(x1, altBN128P)
+		var ok bool
+		if y1, ok = altBN128Y(x1, odd); ok {
+			break
+		}
+	}
+
+	parent := new(big.Int).SetBytes(parentCollectionId[:])
+	if parent.Sign() != 0 {
+		odd = parent.Bit(254) == 1
+		x2 := parent.SetBit(parent, 254, 0).SetBit(parent, 255, 0)
+		y2, ok := altBN128Y(x2, odd)
+		if !ok {
+			return [32]byte{}, ErrInvalidCollectionId
+		}
+		var err error
+		if x1, y1, err = altBN128Add(x1, y1, x2, y2); err != nil {
+			return [32]byte{}, err
+		}
+	}
+
+	if y1.Bit(0) == 1 {
+		x1.Xor(x1, collectionIdOddBit)
+	}
+	return common.BigToHash(x1), nil
+}
+
+// ComputePositionId returns the ERC1155 token ID of collectionId backed by collateral,
+// like ConditionalTokens.getPositionId
+func ComputePositionId(collateral common.Address, collectionId [32]byte) *big.Int {
+	return crypto.Keccak256Hash(collateral.Bytes(), collectionId[:]).Big()
+}
+
+// ComputeOutcomePositionIds returns the token IDs of every single outcome of a top-level condition,
+// indexed by outcome slot
+func ComputeOutcomePositionIds(collateral common.Address, conditionId [32]byte, outcomeSlotCount int) ([]*big.Int, error) {
+	positionIds := make([]*big.Int, outcomeSlotCount)
+	for i := range positionIds {
+		collectionId, err := ComputeCollectionId([32]byte{}, conditionId, new(big.Int).Lsh(big.NewInt(1), uint(i)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute collection ID of outcome %d: %w", i, err)
+		}
+		positionIds[i] = ComputePositionId(collateral, collectionId)
+	}
+	return positionIds, nil
+}
+
+// ComputeNegRiskPositionId returns the token ID of the YES (outcome true) or NO position of a
+// neg-risk question, like NegRiskAdapter.getPositionId. Neg-risk positions are backed by the adapter's
+// wrapped collateral, see ContractConfig.NegRiskWrappedCollateral.
+func ComputeNegRiskPositionId(negRiskAdapter, wrappedCollateral common.Address, questionId [32]byte, outcome bool) *big.Int {
+	indexSet := big.NewInt(2)
+	if outcome {
+		indexSet = big.NewInt(1)
+	}
+	// Top-level collections of a valid index set always exist
+	collectionId, _ := ComputeCollectionId([32]byte{}, ComputeConditionId(negRiskAdapter, questionId, 2), indexSet)
+	return ComputePositionId(wrappedCollateral, collectionId)
+}
+
+// altBN128Y returns the y coordinate with the parity given by odd of the curve point at x,
+// or false if there is none
+func altBN128Y(x *big.Int, odd bool) (*big.Int, bool) {
+	yy := new(big.Int).Exp(x, big.NewInt(3), altBN128P)
+	yy.Add(yy, altBN128B).Mod(yy, altBN128P)
+	y := new(big.Int).Exp(yy, altBN128SqrtExp, altBN128P)
+	if new(big.Int).Exp(y, big.NewInt(2), altBN128P).Cmp(yy) != 0 {
+		return nil, false
+	}
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(altBN128P, y)
+	}
+	return y, true
+}
+
+// altBN128Add adds two curve points like the ecAdd precompile
+func altBN128Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int, error) {
+	var p1, p2 bn256.G1
+	if _, err := p1.Unmarshal(append(common.LeftPadBytes(x1.Bytes(), 32), common.LeftPadBytes(y1.Bytes(), 32)...)); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode curve point: %w", err)
+	}
+	if _, err := p2.Unmarshal(append(common.LeftPadBytes(x2.Bytes(), 32), common.LeftPadBytes(y2.Bytes(), 32)...)); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode curve point: %w", err)
+	}
+	sum := new(bn256.G1).Add(&p1, &p2).Marshal()
+	return new(big.Int).SetBytes(sum[:32]), new(big.Int).SetBytes(sum[32:]), nil
+}
@@ -0,0 +1,142 @@
+package polymarketcontracts
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	conditionaltokens "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/conditional-tokens"
+)
+
+// evmCTF runs the ConditionalTokens bytecode in an in-process EVM
+type evmCTF struct {
+	t    *testing.T
+	cfg  *runtime.Config
+	addr common.Address
+}
+
+func newEVMCTF(t *testing.T) *evmCTF {
+	t.Helper()
+	cfg := &runtime.Config{}
+	_, addr, _, err := runtime.Create(common.FromHex(conditionaltokens.ConditionalTokensMetaData.Bin), cfg)
+	if err != nil {
+		t.Fatalf("failed to deploy ConditionalTokens: %v", err)
+	}
+	return &evmCTF{t: t, cfg: cfg, addr: addr}
+}
+
+func (c *evmCTF) call(method string, args ...interface{}) []interface{} {
+	c.t.Helper()
+	parsed, err := conditionaltokens.ConditionalTokensMetaData.GetAbi()
+	if err != nil {
+		c.t.Fatalf("GetAbi: %v", err)
+	}
+	input, err := parsed.Pack(method, args...)
+	if err != nil {
+		c.t.Fatalf("Pack %s: %v", method, err)
+	}
+	ret, _, err := runtime.Call(c.addr, input, c.cfg)
+	if err != nil {
+		c.t.Fatalf("%s: %v", method, err)
+	}
+	out, err := parsed.Unpack(method, ret)
+	if err != nil {
+		c.t.Fatalf("Unpack %s: %v", method, err)
+	}
+	return out
+}
+
+func (c *evmCTF) collectionId(parent, conditionId [32]byte, indexSet *big.Int) [32]byte {
+	return c.call("getCollectionId", parent, conditionId, indexSet)[0].([32]byte)
+}
+
+func (c *evmCTF) positionId(collateral common.Address, collectionId [32]byte) *big.Int {
+	return c.call("getPositionId", collateral, collectionId)[0].(*big.Int)
+}
+
+func TestComputeIds_MatchConditionalTokens(t *testing.T) {
+	ctf := newEVMCTF(t)
+	oracle := common.HexToAddress("0x6A9D222616C90FcA5754cd1333cFD9b7fb6a4F74")
+
+	for q := byte(0); q < 8; q++ {
+		questionId := crypto.Keccak256Hash([]byte{q})
+		conditionId := ComputeConditionId(oracle, questionId, 3)
+		if want := ctf.call("getConditionId", oracle, questionId, big.NewInt(3))[0].([32]byte); conditionId != want {
+			t.Fatalf("condition ID %x, want %x", conditionId, want)
+		}
+		parentConditionId := ComputeConditionId(oracle, crypto.Keccak256Hash([]byte{q, 1}), 2)
+
+		for indexSet := int64(1); indexSet < 8; indexSet++ {
+			collectionId, err := ComputeCollectionId([32]byte{}, conditionId, big.NewInt(indexSet))
+			if err != nil {
+				t.Fatalf("ComputeCollectionId: %v", err)
+			}
+			if want := ctf.collectionId([32]byte{}, conditionId, big.NewInt(indexSet)); collectionId != want {
+				t.Fatalf("question %d index set %d: collection ID %x, want %x", q, indexSet, collectionId, want)
+			}
+
+			// Nested collections are backed by curve point addition
+			parent := ctf.collectionId([32]byte{}, parentConditionId, big.NewInt(1+int64(q)%2))
+			nested, err := ComputeCollectionId(parent, conditionId, big.NewInt(indexSet))
+			if err != nil {
+				t.Fatalf("ComputeCollectionId: %v", err)
+			}
+			if want := ctf.collectionId(parent, conditionId, big.NewInt(indexSet)); nested != want {
+				t.Fatalf("question %d index set %d: nested collection ID %x, want %x", q, indexSet, nested, want)
+			}
+
+			for _, collateral := range []common.Address{MATIC_CONTRACTS.Collateral, MATIC_CONTRACTS.NegRiskWrappedCollateral} {
+				if got, want := ComputePositionId(collateral, nested), ctf.positionId(collateral, nested); got.Cmp(want) != 0 {
+					t.Fatalf("position ID %s, want %s", got, want)
+				}
+			}
+		}
+	}
+
+	// x = 4 is not on the curve
+	if _, err := ComputeCollectionId(common.HexToHash("0x04"), [32]byte{}, big.NewInt(1)); !errors.Is(err, ErrInvalidCollectionId) {
+		t.Errorf("got %v, want ErrInvalidCollectionId", err)
+	}
+	if _, err := ComputeCollectionId([32]byte{}, [32]byte{}, big.NewInt(0)); err == nil {
+		t.Error("expected an empty index set to fail")
+	}
+}
+
+func TestComputeOutcomePositionIds(t *testing.T) {
+	ctf := newEVMCTF(t)
+	conditionId := ComputeConditionId(MATIC_CONTRACTS.NegRiskAdapter, testCondID, 4)
+	positionIds, err := ComputeOutcomePositionIds(MATIC_CONTRACTS.Collateral, conditionId, 4)
+	if err != nil {
+		t.Fatalf("ComputeOutcomePositionIds: %v", err)
+	}
+	for i, positionId := range positionIds {
+		collectionId := ctf.collectionId([32]byte{}, conditionId, big.NewInt(1<<i))
+		if want := ctf.positionId(MATIC_CONTRACTS.Collateral, collectionId); positionId.Cmp(want) != 0 {
+			t.Errorf("outcome %d: position ID %s, want %s", i, positionId, want)
+		}
+	}
+}
+
+func TestComputeNegRiskPositionId(t *testing.T) {
+	c := MATIC_CONTRACTS
+	if want := crypto.CreateAddress(c.NegRiskAdapter, 1); c.NegRiskWrappedCollateral != want {
+		t.Fatalf("NegRiskWrappedCollateral = %s, want the adapter's first deployment %s", c.NegRiskWrappedCollateral.Hex(), want.Hex())
+	}
+
+	// NegRiskAdapter.getPositionId: the adapter is the oracle of binary conditions backed by its wrapped collateral
+	ctf := newEVMCTF(t)
+	conditionId := ctf.call("getConditionId", c.NegRiskAdapter, testCondID, big.NewInt(2))[0].([32]byte)
+	for _, outcome := range []bool{true, false} {
+		indexSet := big.NewInt(2)
+		if outcome {
+			indexSet = big.NewInt(1)
+		}
+		want := ctf.positionId(c.NegRiskWrappedCollateral, ctf.collectionId([32]byte{}, conditionId, indexSet))
+		if got := ComputeNegRiskPositionId(c.NegRiskAdapter, c.NegRiskWrappedCollateral, testCondID, outcome); got.Cmp(want) != 0 {
+			t.Errorf("outcome %v: position ID %s, want %s", outcome, got, want)
+		}
+	}
+}
@@ -0,0 +1,662 @@
+package polymarketcontracts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ivanzzeth/ethsig/eip712"
+	collateral_offramp "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/collateral-offramp"
+	collateral_onramp "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/collateral-onramp"
+	conditional_tokens "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/conditional-tokens"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/erc20"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/exchange"
+	exchange_v2 "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/exchange-v2"
+	gnosissafel2 "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/gnosis-safe-l2"
+	negriskadapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-adapter"
+	neg_risk_v2 "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-v2"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+// ErrNotDescribed is returned by Describer for typed data it does not recognize
+var ErrNotDescribed = errors.New("typed data not recognized")
+
+// multiSendSelector is the selector of multiSend(bytes), implemented by Safe's MultiSend and MultiSendCallOnly
+var (
+	multiSendSelector  = crypto.Keccak256([]byte("multiSend(bytes)"))[:4]
+	multiSendArguments = abi.Arguments{{Name: "transactions", Type: mustABIType("bytes")}}
+)
+
+// Describer turns typed data and calls signed for Polymarket into human-readable statements, such as
+// "approve pUSD to ExchangeV2: unlimited", for people approving signatures who would otherwise see raw
+// EIP-712 JSON and calldata. It describes SafeTx (including MultiSend batches), Order, ClobAuth and
+// CreateProxy typed data. A Describer must not be modified while it is in use.
+type Describer struct {
+	names   map[common.Address]string
+	tokens  map[common.Address]describedToken
+	methods map[[4]byte]abi.Method
+	// multiSends are the MultiSend contracts whose multiSend(bytes) batches are described call by call
+	multiSends map[common.Address]bool
+
+	collateral, pUSD common.Address
+	// adapters split, merge and redeem with pUSD whatever collateral their calls name
+	adapters map[common.Address]common.Address
+}
+
+type describedToken struct {
+	symbol   string
+	decimals int
+}
+
+// NewDescriber creates a Describer naming the contracts and collateral tokens of config, which may be nil
+func NewDescriber(config *ContractConfig) *Describer {
+	d := &Describer{
+		names:      make(map[common.Address]string),
+		tokens:     make(map[common.Address]describedToken),
+		methods:    make(map[[4]byte]abi.Method),
+		multiSends: map[common.Address]bool{SafeMultiSendCallOnly: true},
+		adapters:   make(map[common.Address]common.Address),
+	}
+
+	// Earlier ABIs win for selectors shared by several contracts
+	for _, abiJSON := range []string{
+		erc20.Erc20MetaData.ABI,
+		conditional_tokens.ConditionalTokensMetaData.ABI,
+		negriskadapter.NegRiskAdapterMetaData.ABI,
+		collateral_onramp.CollateralOnrampMetaData.ABI,
+		collateral_offramp.CollateralOfframpMetaData.ABI,
+		exchange_v2.ExchangeV2MetaData.ABI,
+		neg_risk_v2.NegRiskV2MetaData.ABI,
+		exchange.ExchangeMetaData.ABI,
+		gnosissafel2.GnosisSafeL2MetaData.ABI,
+	} {
+		parsed, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			continue
+		}
+		for _, method := range parsed.Methods {
+			var id [4]byte
+			copy(id[:], method.ID)
+			if _, ok := d.methods[id]; !ok {
+				d.methods[id] = method
+			}
+		}
+	}
+
+	if config == nil {
+		return d
+	}
+	for addr, name := range map[common.Address]string{
+		config.ConditionalTokens:           "ConditionalTokens",
+		config.Exchange:                    "Exchange",
+		config.NegRiskAdapter:              "NegRiskAdapter",
+		config.NegRiskExchange:             "NegRiskExchange",
+		config.SafeProxyFactory:            "SafeProxyFactory",
+		config.ExchangeV2:                  "ExchangeV2",
+		config.NegRiskExchangeV2:           "NegRiskExchangeV2",
+		config.CollateralOnramp:            "CollateralOnramp",
+		config.CollateralOfframp:           "CollateralOfframp",
+		config.CtfCollateralAdapter:        "CtfCollateralAdapter",
+		config.NegRiskCtfCollateralAdapter: "NegRiskCtfCollateralAdapter",
+		config.PermissionedRamp:            "PermissionedRamp",
+	} {
+		d.RegisterAddress(addr, name)
+	}
+	d.RegisterToken(config.Collateral, "USDC.e", 6)
+	d.RegisterToken(config.USDC, "USDC", 6)
+	d.RegisterToken(config.CollateralToken, "pUSD", 6)
+	d.collateral = config.Collateral
+	d.pUSD = config.CollateralToken
+	// The V1 NegRiskAdapter wraps USDC.e, the V2 adapters pUSD
+	d.adapters[config.NegRiskAdapter] = config.Collateral
+	d.adapters[config.CtfCollateralAdapter] = config.CollateralToken
+	d.adapters[config.NegRiskCtfCollateralAdapter] = config.CollateralToken
+	delete(d.adapters, common.Address{})
+	return d
+}
+
+// RegisterAddress names the contract or account at addr in descriptions
+func (d *Describer) RegisterAddress(addr common.Address, name string) {
+	if addr != (common.Address{}) {
+		d.names[addr] = name
+	}
+}
+
+// RegisterMultiSend marks addr as a Safe MultiSend contract, such as MultiSend v1.3.0, whose multiSend(bytes)
+// batches are described call by call. SafeMultiSendCallOnly is registered by default; multiSend calldata
+// sent to any other address is not decoded, since that contract may do anything with it.
+func (d *Describer) RegisterMultiSend(addr common.Address) {
+	d.multiSends[addr] = true
+}
+
+// RegisterToken names the ERC-20 token at addr and formats its amounts with decimals
+func (d *Describer) RegisterToken(addr common.Address, symbol string, decimals int) {
+	if addr != (common.Address{}) {
+		d.names[addr] = symbol
+		d.tokens[addr] = describedToken{symbol: symbol, decimals: decimals}
+	}
+}
+
+// Describe describes typedData in one statement per line
+func (d *Describer) Describe(typedData eip712.TypedData) (string, error) {
+	statements, err := d.DescribeTypedData(typedData)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(statements, "\n"), nil
+}
+
+// DescribeTypedData describes typedData as a list of statements.
+// It fails with ErrNotDescribed for typed data it does not recognize.
+func (d *Describer) DescribeTypedData(typedData eip712.TypedData) ([]string, error) {
+	switch typedData.PrimaryType {
+	case "SafeTx":
+		return d.describeSafeTx(typedData)
+	case "Order":
+		return d.describeOrder(typedData)
+	case "ClobAuth":
+		return d.describeClobAuth(typedData)
+	case "CreateProxy":
+		return d.describeCreateProxy(typedData)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrNotDescribed, typedData.PrimaryType)
+	}
+}
+
+// PreSignHook returns a hook describing typed data and passing the statements to review before it is signed.
+// Typed data the Describer does not recognize is reviewed as such. Use it with signer.NewHookedTypedDataSigner
+// or signer.NewHookedEOASigner.
+func (d *Describer) PreSignHook(review func(ctx context.Context, statements []string) error) signer.PreSignHook {
+	return func(ctx context.Context, typedData eip712.TypedData) error {
+		statements, err := d.DescribeTypedData(typedData)
+		if errors.Is(err, ErrNotDescribed) {
+			statements = []string{fmt.Sprintf("sign unrecognized %s typed data for %q", typedData.PrimaryType, typedData.Domain.Name)}
+		} else if err != nil {
+			return fmt.Errorf("failed to describe typed data: %w", err)
+		}
+		return review(ctx, statements)
+	}
+}
+
+func (d *Describer) describeSafeTx(typedData eip712.TypedData) ([]string, error) {
+	msg := typedData.Message
+	to, err := messageAddress(msg, "to")
+	if err != nil {
+		return nil, err
+	}
+	value, err := messageBig(msg, "value")
+	if err != nil {
+		return nil, err
+	}
+	data, err := messageBytes(msg, "data")
+	if err != nil {
+		return nil, err
+	}
+	operation, err := messageBig(msg, "operation")
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := messageBig(msg, "nonce")
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []string{fmt.Sprintf("Safe %s transaction #%s:", d.name(common.HexToAddress(typedData.Domain.VerifyingContract)), nonce)}
+	switch SafeOperation(operation.Uint64()) {
+	case SafeOperationCall:
+		statements = append(statements, d.DescribeCall(to, value, data)...)
+	case SafeOperationDelegateCall:
+		if d.multiSends[to] && bytes.HasPrefix(data, multiSendSelector) {
+			batch, err := d.describeMultiSend(data)
+			if err != nil {
+				return nil, err
+			}
+			statements = append(statements, batch...)
+			break
+		}
+		for _, statement := range d.DescribeCall(to, value, data) {
+			statements = append(statements, "DELEGATECALL with the Safe's storage and funds: "+statement)
+		}
+	default:
+		return nil, fmt.Errorf("invalid Safe operation %s", operation)
+	}
+
+	gasPrice, err := messageBig(msg, "gasPrice")
+	if err != nil {
+		return nil, err
+	}
+	if gasPrice.Sign() > 0 {
+		safeTxGas, err := messageBig(msg, "safeTxGas")
+		if err != nil {
+			return nil, err
+		}
+		baseGas, err := messageBig(msg, "baseGas")
+		if err != nil {
+			return nil, err
+		}
+		gasToken, err := messageAddress(msg, "gasToken")
+		if err != nil {
+			return nil, err
+		}
+		refundReceiver, err := messageAddress(msg, "refundReceiver")
+		if err != nil {
+			return nil, err
+		}
+		refund := new(big.Int).Mul(new(big.Int).Add(safeTxGas, baseGas), gasPrice)
+		receiver := "the executor"
+		if refundReceiver != (common.Address{}) {
+			receiver = d.name(refundReceiver)
+		}
+		token := describedToken{symbol: "POL", decimals: 18}
+		if gasToken != (common.Address{}) {
+			token = d.token(gasToken)
+		}
+		statements = append(statements, fmt.Sprintf("refund gas up to %s %s to %s", formatAmount(refund, token.decimals), token.symbol, receiver))
+	}
+	return statements, nil
+}
+
+// describeMultiSend describes the calls batched in multiSend(bytes) calldata:
+// each is packed as operation (1 byte), to (20 bytes), value (32 bytes), data length (32 bytes) and data
+func (d *Describer) describeMultiSend(data []byte) ([]string, error) {
+	args, err := multiSendArguments.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode multiSend calldata: %w", err)
+	}
+	packed := args[0].([]byte)
+
+	var statements []string
+	for i := 1; len(packed) > 0; i++ {
+		if len(packed) < 85 {
+			return nil, errors.New("failed to decode multiSend calldata: truncated transaction")
+		}
+		operation := packed[0]
+		to := common.BytesToAddress(packed[1:21])
+		value := new(big.Int).SetBytes(packed[21:53])
+		length := new(big.Int).SetBytes(packed[53:85])
+		if !length.IsUint64() || length.Uint64() > uint64(len(packed)-85) {
+			return nil, errors.New("failed to decode multiSend calldata: truncated transaction data")
+		}
+		callData := packed[85 : 85+length.Uint64()]
+		packed = packed[85+length.Uint64():]
+
+		prefix := fmt.Sprintf("%d. ", i)
+		if operation == uint8(SafeOperationDelegateCall) {
+			prefix += "DELEGATECALL with the Safe's storage and funds: "
+		}
+		for _, statement := range d.DescribeCall(to, value, callData) {
+			statements = append(statements, prefix+statement)
+		}
+	}
+	return statements, nil
+}
+
+// DescribeCall describes a call to `to` sending value with data. Calls it does not recognize are described
+// by their function name if a bundled ABI defines it, by their selector otherwise.
+func (d *Describer) DescribeCall(to common.Address, value *big.Int, data []byte) []string {
+	var statements []string
+	if value != nil && value.Sign() > 0 {
+		statements = append(statements, fmt.Sprintf("send %s POL to %s", formatAmount(value, 18), d.name(to)))
+	}
+	if len(data) == 0 {
+		if len(statements) == 0 {
+			statements = append(statements, fmt.Sprintf("call %s without data", d.name(to)))
+		}
+		return statements
+	}
+	if len(data) < 4 {
+		return append(statements, fmt.Sprintf("call %s with invalid data 0x%x", d.name(to), data))
+	}
+	if bytes.Equal(data[:4], multiSendSelector) {
+		if !d.multiSends[to] {
+			return append(statements, fmt.Sprintf("call multiSend on %s, which is not a known MultiSend", d.name(to)))
+		}
+		batch, err := d.describeMultiSend(data)
+		if err != nil {
+			return append(statements, fmt.Sprintf("call multiSend on %s with invalid data: %v", d.name(to), err))
+		}
+		return append(statements, batch...)
+	}
+
+	var id [4]byte
+	copy(id[:], data)
+	method, ok := d.methods[id]
+	if !ok {
+		return append(statements, fmt.Sprintf("call unknown function 0x%x on %s", id, d.name(to)))
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return append(statements, fmt.Sprintf("call %s on %s with invalid arguments", method.RawName, d.name(to)))
+	}
+	return append(statements, d.describeMethod(to, method, args))
+}
+
+func (d *Describer) describeMethod(to common.Address, method abi.Method, args []interface{}) string {
+	switch method.Sig {
+	case "approve(address,uint256)":
+		return fmt.Sprintf("approve %s to %s: %s", d.token(to).symbol, d.name(args[0].(common.Address)), d.allowance(to, args[1].(*big.Int)))
+	case "transfer(address,uint256)":
+		return fmt.Sprintf("transfer %s to %s", d.amount(to, args[1].(*big.Int)), d.name(args[0].(common.Address)))
+	case "transferFrom(address,address,uint256)":
+		return fmt.Sprintf("transfer %s from %s to %s", d.amount(to, args[2].(*big.Int)), d.name(args[0].(common.Address)), d.name(args[1].(common.Address)))
+	case "setApprovalForAll(address,bool)":
+		if args[1].(bool) {
+			return fmt.Sprintf("approve all %s positions to %s", d.name(to), d.name(args[0].(common.Address)))
+		}
+		return fmt.Sprintf("revoke the approval of all %s positions from %s", d.name(to), d.name(args[0].(common.Address)))
+	case "safeTransferFrom(address,address,uint256,uint256,bytes)":
+		return fmt.Sprintf("transfer %s of position %s from %s to %s", formatAmount(args[3].(*big.Int), 6), args[2].(*big.Int), d.name(args[0].(common.Address)), d.name(args[1].(common.Address)))
+	case "safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)":
+		ids, amounts := args[2].([]*big.Int), args[3].([]*big.Int)
+		positions := make([]string, len(ids))
+		for i := range ids {
+			amount := "?"
+			if i < len(amounts) {
+				amount = formatAmount(amounts[i], 6)
+			}
+			positions[i] = fmt.Sprintf("%s of position %s", amount, ids[i])
+		}
+		return fmt.Sprintf("transfer %s from %s to %s", strings.Join(positions, ", "), d.name(args[0].(common.Address)), d.name(args[1].(common.Address)))
+	case "splitPosition(address,bytes32,bytes32,uint256[],uint256)":
+		return fmt.Sprintf("split %s on condition %s%s", d.amount(d.collateralOf(to, args[0].(common.Address)), args[4].(*big.Int)), hexutil.Encode(bytes32(args[2])), partitionSuffix(args[3].([]*big.Int)))
+	case "mergePositions(address,bytes32,bytes32,uint256[],uint256)":
+		return fmt.Sprintf("merge %s on condition %s%s", d.amount(d.collateralOf(to, args[0].(common.Address)), args[4].(*big.Int)), hexutil.Encode(bytes32(args[2])), partitionSuffix(args[3].([]*big.Int)))
+	case "redeemPositions(address,bytes32,bytes32,uint256[])":
+		return fmt.Sprintf("redeem condition %s for %s (index sets %s)", hexutil.Encode(bytes32(args[2])), d.token(d.collateralOf(to, args[0].(common.Address))).symbol, joinInts(args[3].([]*big.Int)))
+	case "splitPosition(bytes32,uint256)":
+		return fmt.Sprintf("split %s on condition %s", d.amount(d.collateralOf(to, common.Address{}), args[1].(*big.Int)), hexutil.Encode(bytes32(args[0])))
+	case "mergePositions(bytes32,uint256)":
+		return fmt.Sprintf("merge %s on condition %s", d.amount(d.collateralOf(to, common.Address{}), args[1].(*big.Int)), hexutil.Encode(bytes32(args[0])))
+	case "redeemPositions(bytes32,uint256[])":
+		amounts := args[1].([]*big.Int)
+		formatted := make([]string, len(amounts))
+		for i, amount := range amounts {
+			formatted[i] = formatAmount(amount, 6)
+		}
+		return fmt.Sprintf("redeem condition %s for %s (amounts %s)", hexutil.Encode(bytes32(args[0])), d.token(d.collateralOf(to, common.Address{})).symbol, strings.Join(formatted, ", "))
+	case "convertPositions(bytes32,uint256,uint256)":
+		return fmt.Sprintf("convert %s NO positions (index set %s) of market %s on %s", formatAmount(args[2].(*big.Int), 6), args[1].(*big.Int), hexutil.Encode(bytes32(args[0])), d.name(to))
+	case "wrap(address,address,uint256)":
+		return fmt.Sprintf("wrap %s into pUSD for %s", d.amount(args[0].(common.Address), args[2].(*big.Int)), d.name(args[1].(common.Address)))
+	case "unwrap(address,address,uint256)":
+		return fmt.Sprintf("unwrap %s into %s for %s", d.amount(d.pUSD, args[2].(*big.Int)), d.token(args[0].(common.Address)).symbol, d.name(args[1].(common.Address)))
+	default:
+		return fmt.Sprintf("call %s on %s", method.RawName, d.name(to))
+	}
+}
+
+func (d *Describer) describeOrder(typedData eip712.TypedData) ([]string, error) {
+	msg := typedData.Message
+	maker, err := messageAddress(msg, "maker")
+	if err != nil {
+		return nil, err
+	}
+	tokenId, err := messageBig(msg, "tokenId")
+	if err != nil {
+		return nil, err
+	}
+	makerAmount, err := messageBig(msg, "makerAmount")
+	if err != nil {
+		return nil, err
+	}
+	takerAmount, err := messageBig(msg, "takerAmount")
+	if err != nil {
+		return nil, err
+	}
+	side, err := messageBig(msg, "side")
+	if err != nil {
+		return nil, err
+	}
+
+	// V1 exchanges settle in USDC.e, V2 exchanges in pUSD
+	collateral := d.pUSD
+	if typedData.Domain.Version == "1" {
+		collateral = d.collateral
+	}
+	symbol := d.token(collateral).symbol
+	exchange := d.name(common.HexToAddress(typedData.Domain.VerifyingContract))
+
+	var statement string
+	switch side.Uint64() {
+	case 0:
+		statement = fmt.Sprintf("buy %s shares of token %s for %s %s (%s %s each) on %s", formatAmount(takerAmount, 6), tokenId, formatAmount(makerAmount, 6), symbol, price(makerAmount, takerAmount), symbol, exchange)
+	case 1:
+		statement = fmt.Sprintf("sell %s shares of token %s for %s %s (%s %s each) on %s", formatAmount(makerAmount, 6), tokenId, formatAmount(takerAmount, 6), symbol, price(takerAmount, makerAmount), symbol, exchange)
+	default:
+		return nil, fmt.Errorf("invalid order side %s", side)
+	}
+	statements := []string{statement, fmt.Sprintf("order maker: %s", d.name(maker))}
+
+	if _, ok := msg["expiration"]; ok {
+		expiration, err := messageBig(msg, "expiration")
+		if err != nil {
+			return nil, err
+		}
+		if expiration.Sign() > 0 && expiration.IsInt64() {
+			statements = append(statements, "expires at "+time.Unix(expiration.Int64(), 0).UTC().Format(time.RFC3339))
+		}
+	}
+	if _, ok := msg["feeRateBps"]; ok {
+		feeRate, err := messageBig(msg, "feeRateBps")
+		if err != nil {
+			return nil, err
+		}
+		if feeRate.Sign() > 0 {
+			statements = append(statements, fmt.Sprintf("fee rate: %s bps", feeRate))
+		}
+	}
+	return statements, nil
+}
+
+func (d *Describer) describeClobAuth(typedData eip712.TypedData) ([]string, error) {
+	address, err := messageAddress(typedData.Message, "address")
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := messageBig(typedData.Message, "nonce")
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := messageString(typedData.Message, "timestamp")
+	if err != nil {
+		return nil, err
+	}
+	return []string{fmt.Sprintf("authenticate %s to the Polymarket CLOB (timestamp %s, nonce %s)", d.name(address), timestamp, nonce)}, nil
+}
+
+func (d *Describer) describeCreateProxy(typedData eip712.TypedData) ([]string, error) {
+	payment, err := messageBig(typedData.Message, "payment")
+	if err != nil {
+		return nil, err
+	}
+	statement := fmt.Sprintf("create a Safe through %s", d.name(common.HexToAddress(typedData.Domain.VerifyingContract)))
+	if payment.Sign() > 0 {
+		paymentToken, err := messageAddress(typedData.Message, "paymentToken")
+		if err != nil {
+			return nil, err
+		}
+		paymentReceiver, err := messageAddress(typedData.Message, "paymentReceiver")
+		if err != nil {
+			return nil, err
+		}
+		statement += fmt.Sprintf(", paying %s to %s", d.amount(paymentToken, payment), d.name(paymentReceiver))
+	}
+	return []string{statement}, nil
+}
+
+// name returns the registered name of addr, or its hex
+func (d *Describer) name(addr common.Address) string {
+	if name, ok := d.names[addr]; ok {
+		return name
+	}
+	return addr.Hex()
+}
+
+// token returns the registered token at addr; unknown tokens are named by address and amounts left unscaled
+func (d *Describer) token(addr common.Address) describedToken {
+	if token, ok := d.tokens[addr]; ok {
+		return token
+	}
+	return describedToken{symbol: addr.Hex()}
+}
+
+// collateralOf returns the collateral a split, merge or redeem on `to` naming collateral uses
+func (d *Describer) collateralOf(to, collateral common.Address) common.Address {
+	if adapterCollateral, ok := d.adapters[to]; ok {
+		return adapterCollateral
+	}
+	return collateral
+}
+
+func (d *Describer) amount(token common.Address, amount *big.Int) string {
+	t := d.token(token)
+	return formatAmount(amount, t.decimals) + " " + t.symbol
+}
+
+func (d *Describer) allowance(token common.Address, amount *big.Int) string {
+	if amount.Cmp(math.MaxBig256) == 0 {
+		return "unlimited"
+	}
+	return d.amount(token, amount)
+}
+
+// formatAmount formats amount with decimals, without trailing zeros: 1500000 with 6 decimals is "1.5"
+func formatAmount(amount *big.Int, decimals int) string {
+	if decimals == 0 {
+		return amount.String()
+	}
+	s := new(big.Rat).SetFrac(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)).FloatString(decimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// price returns collateral per share, rounded to 4 decimals
+func price(collateral, shares *big.Int) string {
+	if shares.Sign() == 0 {
+		return "?"
+	}
+	s := new(big.Rat).SetFrac(collateral, shares).FloatString(4)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// partitionSuffix mentions partitions other than the binary YES/NO one
+func partitionSuffix(partition []*big.Int) string {
+	if len(partition) == 2 && partition[0].Cmp(big.NewInt(1)) == 0 && partition[1].Cmp(big.NewInt(2)) == 0 {
+		return ""
+	}
+	return " (partition " + joinInts(partition) + ")"
+}
+
+func joinInts(values []*big.Int) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = v.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+func bytes32(v interface{}) []byte {
+	b := v.([32]byte)
+	return b[:]
+}
+
+func mustABIType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+func messageString(msg eip712.TypedDataMessage, key string) (string, error) {
+	switch v := msg[key].(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case nil:
+		return "", fmt.Errorf("typed data message has no %s", key)
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+func messageBig(msg eip712.TypedDataMessage, key string) (*big.Int, error) {
+	switch v := msg[key].(type) {
+	case *big.Int:
+		return v, nil
+	case *hexutil.Big:
+		return v.ToInt(), nil
+	case *math.HexOrDecimal256:
+		return (*big.Int)(v), nil
+	case float64:
+		// JSON numbers; larger values lose precision and are expected as strings
+		if v >= 0 && v == float64(uint64(v)) {
+			return new(big.Int).SetUint64(uint64(v)), nil
+		}
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint8:
+		return big.NewInt(int64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case json.Number:
+		if n, ok := new(big.Int).SetString(v.String(), 10); ok {
+			return n, nil
+		}
+	case string:
+		if n, ok := math.ParseBig256(v); ok {
+			return n, nil
+		}
+	case nil:
+		return nil, fmt.Errorf("typed data message has no %s", key)
+	}
+	return nil, fmt.Errorf("typed data message has an invalid %s: %v", key, msg[key])
+}
+
+func messageAddress(msg eip712.TypedDataMessage, key string) (common.Address, error) {
+	switch v := msg[key].(type) {
+	case common.Address:
+		return v, nil
+	case string:
+		if common.IsHexAddress(v) {
+			return common.HexToAddress(v), nil
+		}
+	case nil:
+		return common.Address{}, fmt.Errorf("typed data message has no %s", key)
+	}
+	return common.Address{}, fmt.Errorf("typed data message has an invalid %s: %v", key, msg[key])
+}
+
+func messageBytes(msg eip712.TypedDataMessage, key string) ([]byte, error) {
+	switch v := msg[key].(type) {
+	case []byte:
+		return v, nil
+	case hexutil.Bytes:
+		return v, nil
+	case string:
+		if v == "" || v == "0x" {
+			return nil, nil
+		}
+		b, err := hexutil.Decode(v)
+		if err != nil {
+			return nil, fmt.Errorf("typed data message has an invalid %s: %w", key, err)
+		}
+		return b, nil
+	case nil:
+		return nil, fmt.Errorf("typed data message has no %s", key)
+	}
+	return nil, fmt.Errorf("typed data message has an invalid %s: %v", key, msg[key])
+}
@@ -0,0 +1,150 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ivanzzeth/ethsig/eip712"
+)
+
+var testSafe = common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+func testSafeTx(to common.Address, data []byte, operation SafeOperation) eip712.TypedData {
+	return BuildSafeTransactionTypedData(big.NewInt(137), testSafe, to, big.NewInt(0), data, operation,
+		big.NewInt(0), big.NewInt(0), big.NewInt(0), common.Address{}, common.Address{}, big.NewInt(7))
+}
+
+// packMultiSend packs calls as MultiSend transactions
+func packMultiSend(t *testing.T, calls ...contractCall) []byte {
+	t.Helper()
+	var packed []byte
+	for _, call := range calls {
+		packed = append(packed, byte(SafeOperationCall))
+		packed = append(packed, call.Target.Bytes()...)
+		packed = append(packed, common.LeftPadBytes(call.Value.Bytes(), 32)...)
+		length := make([]byte, 32)
+		binary.BigEndian.PutUint64(length[24:], uint64(len(call.Calldata)))
+		packed = append(packed, length...)
+		packed = append(packed, call.Calldata...)
+	}
+	data, err := multiSendArguments.Pack(packed)
+	if err != nil {
+		t.Fatalf("Pack: %v", err)
+	}
+	return append(append([]byte{}, multiSendSelector...), data...)
+}
+
+func TestDescriber_SafeTx(t *testing.T) {
+	c := MATIC_CONTRACTS
+	d := NewDescriber(c)
+	condition := "0x0102030000000000000000000000000000000000000000000000000000000000"
+
+	approve, _ := buildERC20ApproveCall(c.CollateralToken, c.ExchangeV2, math.MaxBig256)
+	statements, err := d.DescribeTypedData(testSafeTx(approve.Target, approve.Calldata, SafeOperationCall))
+	if err != nil {
+		t.Fatalf("DescribeTypedData: %v", err)
+	}
+	want := []string{"Safe " + testSafe.Hex() + " transaction #7:", "approve pUSD to ExchangeV2: unlimited"}
+	if !reflect.DeepEqual(statements, want) {
+		t.Errorf("got %q, want %q", statements, want)
+	}
+
+	split, _ := buildAdapterSplitCall(c.CtfCollateralAdapter, testCondID, testPartition, big.NewInt(100e6))
+	approveAll, _ := buildSetApprovalForAllCall(c.ConditionalTokens, c.NegRiskExchangeV2, true)
+	limited, _ := buildERC20ApproveCall(c.Collateral, c.CollateralOnramp, big.NewInt(1500000))
+	redeem, _ := buildRedeemNegRiskCall(c.NegRiskAdapter, testCondID, []*big.Int{big.NewInt(2e6), big.NewInt(0)})
+	multiSend := common.HexToAddress("0x40A2aCCbd92BCA938b02010E17A5b8929b49130D")
+	statements, err = d.DescribeTypedData(testSafeTx(multiSend, packMultiSend(t, split, approveAll, limited, redeem), SafeOperationDelegateCall))
+	if err != nil {
+		t.Fatalf("DescribeTypedData: %v", err)
+	}
+	want = []string{
+		"Safe " + testSafe.Hex() + " transaction #7:",
+		"1. split 100 pUSD on condition " + condition,
+		"2. approve all ConditionalTokens positions to NegRiskExchangeV2",
+		"3. approve USDC.e to CollateralOnramp: 1.5 USDC.e",
+		"4. redeem condition " + condition + " for USDC.e (amounts 2, 0)",
+	}
+	if !reflect.DeepEqual(statements, want) {
+		t.Errorf("got %q, want %q", statements, want)
+	}
+
+	// Delegate calls to anything but MultiSend are called out
+	statements, err = d.DescribeTypedData(testSafeTx(c.ExchangeV2, []byte{0xde, 0xad, 0xbe, 0xef}, SafeOperationDelegateCall))
+	if err != nil || !strings.HasPrefix(statements[1], "DELEGATECALL") || !strings.Contains(statements[1], "unknown function 0xdeadbeef on ExchangeV2") {
+		t.Errorf("got %q, %v", statements, err)
+	}
+
+	// A multiSend payload is only decoded for a known MultiSend
+	batch := packMultiSend(t, split)
+	other := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+	statements, err = d.DescribeTypedData(testSafeTx(other, batch, SafeOperationDelegateCall))
+	want = []string{
+		"Safe " + testSafe.Hex() + " transaction #7:",
+		"DELEGATECALL with the Safe's storage and funds: call multiSend on " + other.Hex() + ", which is not a known MultiSend",
+	}
+	if err != nil || !reflect.DeepEqual(statements, want) {
+		t.Errorf("got %q, %v, want %q", statements, err, want)
+	}
+	d.RegisterMultiSend(other)
+	statements, err = d.DescribeTypedData(testSafeTx(other, batch, SafeOperationDelegateCall))
+	if err != nil || len(statements) != 2 || statements[1] != "1. split 100 pUSD on condition "+condition {
+		t.Errorf("got %q, %v", statements, err)
+	}
+}
+
+func TestDescriber_OrderAndClobAuth(t *testing.T) {
+	d := NewDescriber(MATIC_CONTRACTS)
+	order := eip712.TypedData{
+		PrimaryType: "Order",
+		Domain:      eip712.TypedDataDomain{Name: "Polymarket CTF Exchange", Version: "2", ChainId: "137", VerifyingContract: MATIC_CONTRACTS.ExchangeV2.Hex()},
+		Message: eip712.TypedDataMessage{
+			"maker":       testSafe.Hex(),
+			"tokenId":     "12345",
+			"makerAmount": "55000000",
+			"takerAmount": "100000000",
+			"side":        "0",
+		},
+	}
+	statements, err := d.DescribeTypedData(order)
+	if err != nil {
+		t.Fatalf("DescribeTypedData: %v", err)
+	}
+	want := []string{"buy 100 shares of token 12345 for 55 pUSD (0.55 pUSD each) on ExchangeV2", "order maker: " + testSafe.Hex()}
+	if !reflect.DeepEqual(statements, want) {
+		t.Errorf("got %q, want %q", statements, want)
+	}
+
+	description, err := d.Describe(BuildClobAuthTypedData(testSafe, big.NewInt(137), 1700000000, 3))
+	if err != nil || description != "authenticate "+testSafe.Hex()+" to the Polymarket CLOB (timestamp 1700000000, nonce 3)" {
+		t.Errorf("got %q, %v", description, err)
+	}
+
+	if _, err := d.DescribeTypedData(eip712.TypedData{PrimaryType: "Permit"}); !errors.Is(err, ErrNotDescribed) {
+		t.Errorf("got %v, want ErrNotDescribed", err)
+	}
+}
+
+func TestDescriber_PreSignHook(t *testing.T) {
+	d := NewDescriber(MATIC_CONTRACTS)
+	declined := errors.New("declined")
+	var reviewed []string
+	hook := d.PreSignHook(func(_ context.Context, statements []string) error {
+		reviewed = statements
+		return declined
+	})
+
+	if err := hook(context.Background(), eip712.TypedData{PrimaryType: "Permit", Domain: eip712.TypedDataDomain{Name: "USD Coin"}}); !errors.Is(err, declined) {
+		t.Fatalf("got %v, want the review error", err)
+	}
+	if len(reviewed) != 1 || reviewed[0] != `sign unrecognized Permit typed data for "USD Coin"` {
+		t.Errorf("reviewed %q", reviewed)
+	}
+}
@@ -0,0 +1,225 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gnosissafel2 "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/gnosis-safe-l2"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+// SafeSimulateTxAccessor is the Safe v1.3.0 SimulateTxAccessor used to dry-run Safe calls through simulateAndRevert
+var SafeSimulateTxAccessor = common.HexToAddress("0x59AD6735bCd8152B84860Cb256dD9e96b85F69Da")
+
+// ErrDryRunReverted is wrapped by the error returned for a dry-run call that would revert
+var ErrDryRunReverted = errors.New("dry run: call would revert")
+
+const simulateTxAccessorABI = `[{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"bytes","name":"data","type":"bytes"},{"internalType":"enum Enum.Operation","name":"operation","type":"uint8"}],"name":"simulate","outputs":[{"internalType":"uint256","name":"estimate","type":"uint256"},{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"stateMutability":"nonpayable","type":"function"}]`
+
+// DryRunResult is the outcome of simulating one contractCall instead of sending it
+type DryRunResult struct {
+	From     common.Address // Acting account: the EOA, or the Safe for Safe calls
+	Safe     bool           // Whether the call was simulated as a Safe execution
+	Target   common.Address
+	Calldata []byte
+	Value    *big.Int
+
+	Success bool
+	// Reverted is true when the call itself would revert, as opposed to the simulation failing
+	Reverted   bool
+	ReturnData []byte
+	// GasEstimate is the gas of the call itself. For Safe calls it excludes the execTransaction overhead.
+	GasEstimate uint64
+	// Err is the decoded revert (see package revert) or the simulation error when Success is false
+	Err error
+}
+
+// DryRunRecorder collects DryRunResults. It is safe for concurrent use by multiple goroutines.
+type DryRunRecorder struct {
+	mu      sync.Mutex
+	results []DryRunResult
+}
+
+// NewDryRunRecorder creates an empty DryRunRecorder
+func NewDryRunRecorder() *DryRunRecorder {
+	return &DryRunRecorder{}
+}
+
+// Results returns the recorded results in call order
+func (r *DryRunRecorder) Results() []DryRunResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]DryRunResult(nil), r.results...)
+}
+
+// Reset clears the recorded results
+func (r *DryRunRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.results = nil
+}
+
+func (r *DryRunRecorder) record(result DryRunResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.results = append(r.results, result)
+}
+
+// dryRun records a simulation and returns what the caller sees instead of a tx hash.
+// Calls are simulated against current chain state one by one: a call depending on an earlier
+// call of the same batch (e.g. an approval) is simulated without that earlier call applied.
+func (e *txExecutor) dryRun(result DryRunResult) (common.Hash, error) {
+	e.dryRunRecorder.record(result)
+	if result.Success {
+		return common.Hash{}, nil
+	}
+	if result.Reverted {
+		return common.Hash{}, fmt.Errorf("%w: %w", ErrDryRunReverted, result.Err)
+	}
+	return common.Hash{}, fmt.Errorf("dry run failed: %w", result.Err)
+}
+
+// isRevert reports whether a simulation error is an actual revert rather than a failure to simulate
+func isRevert(err error) bool {
+	var decoded *revert.Error
+	return errors.As(err, &decoded) || strings.Contains(err.Error(), "execution reverted")
+}
+
+// simulateEOA eth_calls the call from the EOA of txSender and estimates its gas
+func (e *txExecutor) simulateEOA(ctx context.Context, txSender sender.TransactionSender, call contractCall) DryRunResult {
+	result := DryRunResult{Target: call.Target, Calldata: call.Calldata, Value: call.Value}
+
+	ag, ok := txSender.(interface{ GetAddress() common.Address })
+	if !ok {
+		result.Err = fmt.Errorf("txSender does not implement GetAddress; cannot simulate from the EOA")
+		return result
+	}
+	result.From = ag.GetAddress()
+
+	msg := call.toCallMsg()
+	msg.From = result.From
+	returnData, err := e.client.CallContract(ctx, msg, nil)
+	if err != nil {
+		result.Err = revert.Wrap(&call.Target, err)
+		result.Reverted = isRevert(result.Err)
+		return result
+	}
+	gas, err := e.client.EstimateGas(ctx, msg)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to estimate gas: %w", revert.Wrap(&call.Target, err))
+		result.Reverted = isRevert(result.Err)
+		return result
+	}
+
+	result.Success = true
+	result.ReturnData = returnData
+	result.GasEstimate = gas
+	return result
+}
+
+// simulateSafe runs the call as the Safe through simulateAndRevert + SimulateTxAccessor.simulate,
+// which executes it in the Safe's context and reverts with the outcome, so nothing is signed or sent.
+func (e *txExecutor) simulateSafe(ctx context.Context, owner, safeAddr common.Address, call contractCall) DryRunResult {
+	result := DryRunResult{From: safeAddr, Safe: true, Target: call.Target, Calldata: call.Calldata, Value: call.Value}
+
+	accessorABI, err := abi.JSON(strings.NewReader(simulateTxAccessorABI))
+	if err != nil {
+		result.Err = fmt.Errorf("failed to parse SimulateTxAccessor ABI: %w", err)
+		return result
+	}
+	safeABI, err := gnosissafel2.GnosisSafeL2MetaData.GetAbi()
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get Safe ABI: %w", err)
+		return result
+	}
+
+	value := call.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	simulateData, err := accessorABI.Pack("simulate", call.Target, value, call.Calldata, uint8(call.Operation))
+	if err != nil {
+		result.Err = fmt.Errorf("failed to pack simulate: %w", err)
+		return result
+	}
+	accessor := e.simulateTxAccessor
+	if accessor == (common.Address{}) {
+		accessor = SafeSimulateTxAccessor
+	}
+	payload, err := safeABI.Pack("simulateAndRevert", accessor, simulateData)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to pack simulateAndRevert: %w", err)
+		return result
+	}
+
+	_, callErr := e.client.CallContract(ctx, ethereum.CallMsg{From: owner, To: &safeAddr, Data: payload}, nil)
+	if callErr == nil {
+		result.Err = fmt.Errorf("simulateAndRevert did not revert: is %s a deployed Safe?", safeAddr.Hex())
+		return result
+	}
+	revertData, ok := revert.ExtractData(callErr)
+	if !ok {
+		result.Err = fmt.Errorf("failed to simulate Safe call: %w", callErr)
+		return result
+	}
+
+	estimate, success, returnData, err := decodeSafeSimulation(accessorABI, revertData)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.GasEstimate = estimate
+	result.ReturnData = returnData
+	if !success {
+		result.Reverted = true
+		if decoded, ok := revert.Default().Decode(&call.Target, returnData); ok {
+			result.Err = decoded
+		} else {
+			result.Err = fmt.Errorf("execution reverted (data: 0x%x)", returnData)
+		}
+		return result
+	}
+	result.Success = true
+	return result
+}
+
+// decodeSafeSimulation decodes the revert data of simulateAndRevert: abi.encodePacked(bool success, uint256 length, bytes response),
+// where response is the ABI-encoded (estimate, success, returnData) of SimulateTxAccessor.simulate.
+func decodeSafeSimulation(accessorABI abi.ABI, revertData []byte) (uint64, bool, []byte, error) {
+	if len(revertData) < 64 {
+		return 0, false, nil, fmt.Errorf("unexpected simulateAndRevert result length %d", len(revertData))
+	}
+	delegateSuccess := new(big.Int).SetBytes(revertData[:32]).Sign() != 0
+	length := new(big.Int).SetBytes(revertData[32:64])
+	if !length.IsUint64() || uint64(len(revertData)-64) < length.Uint64() {
+		return 0, false, nil, fmt.Errorf("malformed simulateAndRevert result")
+	}
+	response := revertData[64 : 64+length.Uint64()]
+	if !delegateSuccess {
+		return 0, false, nil, fmt.Errorf("SimulateTxAccessor call failed (data: 0x%x)", response)
+	}
+
+	out, err := accessorABI.Unpack("simulate", response)
+	if err != nil || len(out) != 3 {
+		return 0, false, nil, fmt.Errorf("failed to decode simulate result: %w", err)
+	}
+	estimate, _ := out[0].(*big.Int)
+	success, _ := out[1].(bool)
+	returnData, _ := out[2].([]byte)
+	if estimate == nil || !estimate.IsUint64() {
+		return 0, false, nil, fmt.Errorf("invalid simulate gas estimate")
+	}
+	return estimate.Uint64(), success, returnData, nil
+}
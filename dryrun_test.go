@@ -0,0 +1,234 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ivanzzeth/ethclient"
+	conditional_tokens "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/conditional-tokens"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/erc20"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+// revertDataError mimics the JSON-RPC error returned by nodes for reverted calls.
+type revertDataError struct {
+	data []byte
+}
+
+func (e *revertDataError) Error() string          { return "execution reverted" }
+func (e *revertDataError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// mockCallClient answers eth_call and eth_estimateGas; other methods panic through the nil embedded interface.
+type mockCallClient struct {
+	ethclient.EthClientInterface
+
+	callErr    error
+	returnData []byte // returned by eth_call if set, 0x01 otherwise
+	gas        uint64
+	lastCall   ethereum.CallMsg
+}
+
+func (c *mockCallClient) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	c.lastCall = msg
+	if c.returnData != nil {
+		return c.returnData, c.callErr
+	}
+	return []byte{0x01}, c.callErr
+}
+
+func (c *mockCallClient) EstimateGas(_ context.Context, _ ethereum.CallMsg) (uint64, error) {
+	return c.gas, nil
+}
+
+// addrSender is a mockTransactionSender that also exposes the EOA address.
+type addrSender struct {
+	mockTransactionSender
+	addr common.Address
+}
+
+func (s *addrSender) GetAddress() common.Address { return s.addr }
+
+// safeSimulationRevert builds the revert data of simulateAndRevert wrapping SimulateTxAccessor.simulate's result.
+func safeSimulationRevert(t *testing.T, estimate int64, success bool, returnData []byte) []byte {
+	t.Helper()
+	accessorABI, err := abi.JSON(strings.NewReader(simulateTxAccessorABI))
+	if err != nil {
+		t.Fatalf("abi: %v", err)
+	}
+	response, err := accessorABI.Methods["simulate"].Outputs.Pack(big.NewInt(estimate), success, returnData)
+	if err != nil {
+		t.Fatalf("pack: %v", err)
+	}
+	data := common.LeftPadBytes([]byte{1}, 32)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(response))).Bytes(), 32)...)
+	return append(data, response...)
+}
+
+func TestDryRunEOA_Success(t *testing.T) {
+	eoa := common.HexToAddress("0xEOA")
+	client := &mockCallClient{gas: 84000}
+	txSender := &addrSender{addr: eoa}
+	recorder := NewDryRunRecorder()
+	exec := &txExecutor{client: client, txSender: txSender, dryRunRecorder: recorder}
+
+	hash, err := exec.executeEOA(context.Background(), contractCall{Target: testCTF, Calldata: []byte{0xAB}, Value: big.NewInt(0)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash != (common.Hash{}) {
+		t.Errorf("expected zero hash in dry run, got %s", hash.Hex())
+	}
+	if txSender.calls != 0 {
+		t.Error("dry run must not send transactions")
+	}
+	if client.lastCall.From != eoa {
+		t.Errorf("expected simulation from %s, got %s", eoa.Hex(), client.lastCall.From.Hex())
+	}
+
+	results := recorder.Results()
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if !results[0].Success || results[0].GasEstimate != 84000 || results[0].From != eoa {
+		t.Errorf("unexpected result: %+v", results[0])
+	}
+}
+
+func TestDryRunEOA_Revert(t *testing.T) {
+	client := &mockCallClient{callErr: &revertDataError{data: crypto.Keccak256([]byte("NotCrossing()"))[:4]}}
+	recorder := NewDryRunRecorder()
+	exec := &txExecutor{client: client, txSender: &addrSender{addr: common.HexToAddress("0xEOA")}, dryRunRecorder: recorder}
+
+	_, err := exec.executeEOA(context.Background(), contractCall{Target: testCTF, Value: big.NewInt(0)})
+	if !errors.Is(err, ErrDryRunReverted) {
+		t.Fatalf("expected ErrDryRunReverted, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "NotCrossing()") {
+		t.Errorf("expected decoded revert in error, got %v", err)
+	}
+	if r := recorder.Results()[0]; r.Success || !r.Reverted {
+		t.Errorf("expected reverted result, got %+v", r)
+	}
+}
+
+func TestDryRunEOA_NoAddress(t *testing.T) {
+	exec := &txExecutor{client: &mockCallClient{}, txSender: &mockTransactionSender{}, dryRunRecorder: NewDryRunRecorder()}
+
+	_, err := exec.executeEOA(context.Background(), contractCall{Target: testCTF, Value: big.NewInt(0)})
+	if err == nil || errors.Is(err, ErrDryRunReverted) {
+		t.Fatalf("expected a simulation failure that is not a revert, got %v", err)
+	}
+}
+
+func TestDryRunSafe(t *testing.T) {
+	safeAddr := common.HexToAddress("0x5afe")
+	eoaAddr := common.HexToAddress("0xEOA")
+	client := &mockCallClient{callErr: &revertDataError{data: safeSimulationRevert(t, 52000, true, nil)}}
+	recorder := NewDryRunRecorder()
+	exec := &txExecutor{
+		client:         client,
+		getSafeAddr:    func(common.Address) (common.Address, error) { return safeAddr, nil },
+		dryRunRecorder: recorder,
+		execSafeTx: func(context.Context, signer.SafeTradingSigner, *big.Int, common.Address, common.Address, *big.Int, []byte, SafeOperation, *big.Int, ...sender.SendOption) (common.Hash, error) {
+			t.Fatal("dry run must not execute the Safe transaction")
+			return common.Hash{}, nil
+		},
+	}
+
+	_, err := exec.executeSafe(context.Background(), &mockSafeSigner{addr: eoaAddr}, big.NewInt(137), contractCall{Target: testCTF, Calldata: []byte{0xAB}, Value: big.NewInt(0)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *client.lastCall.To != safeAddr || client.lastCall.From != eoaAddr {
+		t.Errorf("expected simulateAndRevert on the Safe from its owner, got to=%s from=%s", client.lastCall.To.Hex(), client.lastCall.From.Hex())
+	}
+
+	r := recorder.Results()[0]
+	if !r.Success || !r.Safe || r.From != safeAddr || r.GasEstimate != 52000 {
+		t.Errorf("unexpected result: %+v", r)
+	}
+}
+
+func TestDryRunSafe_InnerRevert(t *testing.T) {
+	inner := crypto.Keccak256([]byte("Paused()"))[:4]
+	client := &mockCallClient{callErr: &revertDataError{data: safeSimulationRevert(t, 30000, false, inner)}}
+	recorder := NewDryRunRecorder()
+	exec := &txExecutor{
+		client:         client,
+		getSafeAddr:    func(common.Address) (common.Address, error) { return common.HexToAddress("0x5afe"), nil },
+		dryRunRecorder: recorder,
+	}
+
+	_, err := exec.executeSafe(context.Background(), &mockSafeSigner{addr: common.HexToAddress("0xEOA")}, big.NewInt(137), contractCall{Target: testCTF, Value: big.NewInt(0)})
+	if !errors.Is(err, ErrDryRunReverted) || !strings.Contains(err.Error(), "Paused()") {
+		t.Fatalf("expected decoded Paused() revert, got %v", err)
+	}
+	if r := recorder.Results()[0]; r.Success || !r.Reverted {
+		t.Errorf("expected reverted result, got %+v", r)
+	}
+}
+
+func TestDryRunBatchSafe_DoesNotWait(t *testing.T) {
+	client := &mockCallClient{callErr: &revertDataError{data: safeSimulationRevert(t, 1, true, nil)}}
+	recorder := NewDryRunRecorder()
+	exec := &txExecutor{
+		client:         client,
+		getSafeAddr:    func(common.Address) (common.Address, error) { return common.HexToAddress("0x5afe"), nil },
+		dryRunRecorder: recorder,
+	}
+
+	calls := []contractCall{{Target: testCTF, Value: big.NewInt(0)}, {Target: testCTF, Value: big.NewInt(0)}}
+	if _, err := exec.executeBatchSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), calls); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorder.Results()) != 2 {
+		t.Errorf("expected 2 results, got %d", len(recorder.Results()))
+	}
+}
+
+func TestDryRunEnableTradingForEOA(t *testing.T) {
+	eoa := common.HexToAddress("0xEOA")
+	// Every read returns zero: no allowance or approval is set yet
+	client := &mockCallClient{gas: 46000, returnData: make([]byte, 32)}
+	txSender := &addrSender{addr: eoa}
+	recorder := NewDryRunRecorder()
+	collateral, _ := erc20.NewErc20(MATIC_CONTRACTS.Collateral, client)
+	ctf, _ := conditional_tokens.NewConditionalTokens(MATIC_CONTRACTS.ConditionalTokens, client)
+	b := &ContractInterface{
+		client:                    client,
+		contractConfig:            MATIC_CONTRACTS,
+		collateralContract:        collateral,
+		conditionalTokensContract: ctf,
+		executor:                  &txExecutor{client: client, txSender: txSender, dryRunRecorder: recorder},
+	}
+
+	if _, err := b.EnableTradingForEOA(context.Background(), &mockEOASigner{addr: eoa}); err != nil {
+		t.Fatalf("EnableTradingForEOA: %v", err)
+	}
+	if txSender.calls != 0 {
+		t.Errorf("dry run must not send transactions, sent %d", txSender.calls)
+	}
+
+	results := recorder.Results()
+	if len(results) != 6 {
+		t.Fatalf("expected 3 USDC and 3 CTF approvals simulated, got %d", len(results))
+	}
+	for i, r := range results {
+		want := MATIC_CONTRACTS.Collateral
+		if i >= 3 {
+			want = MATIC_CONTRACTS.ConditionalTokens
+		}
+		if !r.Success || r.From != eoa || r.Target != want {
+			t.Errorf("result %d: %+v", i, r)
+		}
+	}
+}
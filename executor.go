@@ -2,21 +2,41 @@ package polymarketcontracts
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ivanzzeth/ethclient"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/journal"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/offline"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/retry"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
 	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
 	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
 )
 
+// ErrCallPending is returned when a call with the same idempotency key is journaled as pending
+// without a transaction hash, e.g. it was handed to a remote signer before a crash. Reconcile it with journal.Recover first.
+var ErrCallPending = errors.New("call with the same idempotency key is pending in the journal")
+
+// ErrCallMined is returned when a call with the same idempotency key is journaled as mined
+var ErrCallMined = errors.New("call with the same idempotency key was already mined")
+
+// ErrEOASignerMismatch is returned by EOA methods given a signer other than the account their transactions are sent from
+var ErrEOASignerMismatch = errors.New("EOA signer is not the account transactions are sent from")
+
 type contractCall struct {
 	Target   common.Address
 	Calldata []byte
 	Value    *big.Int
+	// Urgency is passed to the GasPricer choosing the fees of the transaction
+	Urgency sender.Urgency
+	// Operation is how a Safe executes the call (default: call). EOAs can only call.
+	Operation SafeOperation
 }
 
 // toCallMsg converts contractCall to ethereum.CallMsg for eth_call simulation.
@@ -32,33 +52,208 @@ type txExecutor struct {
 	client      ethclient.EthClientInterface
 	txSender    sender.TransactionSender
 	getSafeAddr func(eoa common.Address) (common.Address, error)
-	execSafeTx  func(safeSigner signer.SafeTradingSigner, chainID *big.Int, safeAddr, to common.Address, value *big.Int, data []byte, operation SafeOperation, safeTxGas *big.Int) (common.Hash, error)
+	execSafeTx  func(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, safeAddr, to common.Address, value *big.Int, data []byte, operation SafeOperation, safeTxGas *big.Int, opts ...sender.SendOption) (common.Hash, error)
+
+	// dryRunRecorder, when set, makes every call simulated and recorded instead of sent
+	dryRunRecorder     *DryRunRecorder
+	simulateTxAccessor common.Address
+
+	// gasPricer, when set, chooses the fees of every transaction instead of the sender
+	gasPricer sender.GasPricer
+
+	// journal, when set, records every call and the transaction sent for it
+	journal journal.Journal
+	// journalMu makes the lookup and insertion of keyed calls atomic
+	journalMu sync.Mutex
+
+	// retryPolicy retries transient errors of gas pricing
+	retryPolicy retry.Policy
+}
+
+// startJournal records call in the journal and returns a context carrying the new entry.
+// A call made with an idempotency key (journal.WithIdempotencyKey) is not sent while an entry for the same
+// call and key is pending or mined: ErrCallPending or ErrCallMined is returned instead.
+func (e *txExecutor) startJournal(ctx context.Context, from, safe common.Address, call contractCall) (context.Context, *journal.Entry, error) {
+	entry := journal.NewEntry(from, safe, call.Target, call.Calldata, call.Value)
+	entry.Key = journal.IdempotencyKey(ctx)
+	if entry.Key != "" {
+		e.journalMu.Lock()
+		defer e.journalMu.Unlock()
+		if err := e.checkKeyed(ctx, entry); err != nil {
+			return ctx, nil, err
+		}
+	}
+
+	if err := e.journal.Put(entry); err != nil {
+		return ctx, nil, fmt.Errorf("failed to write journal: %w", err)
+	}
+	return journal.NewContext(ctx, e.journal, entry.ID), entry, nil
+}
+
+// checkKeyed returns an error if a call with the same idempotency key as entry was already sent
+func (e *txExecutor) checkKeyed(ctx context.Context, entry *journal.Entry) error {
+	prev, err := journal.FindKeyed(e.journal, entry)
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+	if prev != nil && prev.State == journal.StateSent && e.client != nil {
+		// It may have been mined or dropped since
+		if prev, err = journal.Reconcile(ctx, e.journal, e.client, prev.ID); err != nil {
+			return fmt.Errorf("failed to reconcile journal entry: %w", err)
+		}
+	}
+	switch {
+	case prev == nil || prev.State == journal.StateFailed:
+		return nil
+	case prev.State == journal.StateMined:
+		return fmt.Errorf("%w: entry %s, tx %s", ErrCallMined, prev.ID, prev.TxHash.Hex())
+	default:
+		return fmt.Errorf("%w: entry %s is %s", ErrCallPending, prev.ID, prev.State)
+	}
+}
+
+// finishJournal records the outcome of sending entry's call; entry is nil when the executor has no journal.
+// Journal errors are ignored once a transaction may be out: journal.Recover reconciles the entry later.
+func (e *txExecutor) finishJournal(entry *journal.Entry, txHash common.Hash, sendErr error) {
+	if entry == nil {
+		return
+	}
+	_ = e.journal.Update(entry.ID, func(en *journal.Entry) {
+		if sendErr != nil {
+			if en.State == journal.StateBuilt {
+				en.Fail(sendErr)
+			}
+			return
+		}
+		if txHash == (common.Hash{}) {
+			// Exported to an offline bundle: nothing was sent
+			return
+		}
+		en.State = journal.StateSent
+		en.TxHash = txHash
+	})
+}
+
+// markMined records the final state of the journal entry sent as txHash: mined, or failed if it was
+// mined but reverted or cancelled. Entries whose transaction is not mined yet are left for journal.Recover.
+func (e *txExecutor) markMined(txHash, minedHash common.Hash, waitErr error) {
+	if e == nil || e.journal == nil {
+		return
+	}
+	en, err := journal.FindByHash(e.journal, txHash)
+	if err != nil || en.State.IsFinal() {
+		return
+	}
+	var reverted *revert.Error
+	final := errors.As(waitErr, &reverted) || errors.Is(waitErr, revert.ErrTransactionFailed) || errors.Is(waitErr, signer.ErrTransactionCancelled)
+	if waitErr != nil && !final {
+		// Not mined yet: leave the entry for journal.Recover
+		return
+	}
+	_ = e.journal.Update(en.ID, func(en *journal.Entry) {
+		en.TxHash = minedHash
+		if waitErr != nil {
+			en.Fail(waitErr)
+		} else {
+			en.State = journal.StateMined
+		}
+	})
 }
 
-func (e *txExecutor) executeEOA(call contractCall) (common.Hash, error) {
-	txHash, err := e.txSender.SendEthereumTransaction(call.Target, call.Calldata, call.Value)
+// sendOptions returns the per-call options for call: its urgency, and explicit fees when the executor has a GasPricer
+func (e *txExecutor) sendOptions(ctx context.Context, call contractCall) ([]sender.SendOption, error) {
+	opts := []sender.SendOption{sender.WithUrgency(call.Urgency)}
+	if e.gasPricer == nil {
+		return opts, nil
+	}
+	fees, err := retry.DoValue(ctx, e.retryPolicy, func() (*sender.GasFees, error) {
+		return e.gasPricer.GasFees(ctx, call.Urgency)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to price gas: %w", err)
+	}
+	return append(opts, fees.SendOptions()...), nil
+}
+
+func (e *txExecutor) executeEOA(ctx context.Context, call contractCall) (common.Hash, error) {
+	return e.executeEOABy(ctx, e.txSender, call)
+}
+
+// eoaAddress returns the address of eoaSigner, which EOA methods act for. Their transactions are sent by
+// the executor's sender, so a signer for another account is rejected rather than acted for by the wrong one.
+func (e *txExecutor) eoaAddress(eoaSigner signer.EOATradingSigner) (common.Address, error) {
+	addr := eoaSigner.GetAddress()
+	if ag, ok := e.txSender.(interface{ GetAddress() common.Address }); ok && ag.GetAddress() != addr {
+		return common.Address{}, fmt.Errorf("%w: signer %s, sender %s", ErrEOASignerMismatch, addr.Hex(), ag.GetAddress().Hex())
+	}
+	return addr, nil
+}
+
+// executeEOABy is executeEOA sending through txSender instead of the executor's sender
+func (e *txExecutor) executeEOABy(ctx context.Context, txSender sender.TransactionSender, call contractCall) (common.Hash, error) {
+	if call.Operation != SafeOperationCall {
+		return common.Hash{}, fmt.Errorf("EOAs cannot execute Safe operation %d", call.Operation)
+	}
+	if e.dryRunRecorder != nil {
+		return e.dryRun(e.simulateEOA(ctx, txSender, call))
+	}
+	var entry *journal.Entry
+	if e.journal != nil {
+		var from common.Address
+		if ag, ok := txSender.(interface{ GetAddress() common.Address }); ok {
+			from = ag.GetAddress()
+		}
+		var err error
+		if ctx, entry, err = e.startJournal(ctx, from, common.Address{}, call); err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	opts, err := e.sendOptions(ctx, call)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to send EOA transaction: %w", err)
+		e.finishJournal(entry, common.Hash{}, err)
+		return common.Hash{}, err
 	}
-	return txHash, nil
+	txHash, err := sender.AsContextTransactionSender(txSender).SendEthereumTransactionWithContext(ctx, call.Target, call.Calldata, call.Value, opts...)
+	if err != nil {
+		err = fmt.Errorf("failed to send EOA transaction: %w", err)
+	}
+	e.finishJournal(entry, txHash, err)
+	return txHash, err
 }
 
-func (e *txExecutor) executeSafe(safeSigner signer.SafeTradingSigner, chainID *big.Int, call contractCall) (common.Hash, error) {
+func (e *txExecutor) executeSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, call contractCall) (common.Hash, error) {
 	safeAddr, err := e.getSafeAddr(safeSigner.GetAddress())
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to get Safe address: %w", err)
 	}
-	txHash, err := e.execSafeTx(safeSigner, chainID, safeAddr, call.Target, call.Value, call.Calldata, SafeOperationCall, big.NewInt(0))
+	if e.dryRunRecorder != nil {
+		return e.dryRun(e.simulateSafe(ctx, safeSigner.GetAddress(), safeAddr, call))
+	}
+	var entry *journal.Entry
+	if e.journal != nil {
+		if ctx, entry, err = e.startJournal(ctx, safeSigner.GetAddress(), safeAddr, call); err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	opts, err := e.sendOptions(ctx, call)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to execute Safe transaction: %w", err)
+		e.finishJournal(entry, common.Hash{}, err)
+		return common.Hash{}, err
 	}
-	return txHash, nil
+	txHash, err := e.execSafeTx(ctx, safeSigner, chainID, safeAddr, call.Target, call.Value, call.Calldata, call.Operation, big.NewInt(0), opts...)
+	if err != nil {
+		err = fmt.Errorf("failed to execute Safe transaction: %w", err)
+	}
+	e.finishJournal(entry, txHash, err)
+	return txHash, err
 }
 
-func (e *txExecutor) executeBatchEOA(calls []contractCall) ([]common.Hash, error) {
+func (e *txExecutor) executeBatchEOA(ctx context.Context, calls []contractCall) ([]common.Hash, error) {
 	hashes := make([]common.Hash, 0, len(calls))
 	for i, call := range calls {
-		txHash, err := e.executeEOA(call)
+		txHash, err := e.executeEOA(ctx, call)
 		if err != nil {
 			return hashes, fmt.Errorf("batch EOA tx %d failed: %w", i, err)
 		}
@@ -67,51 +262,120 @@ func (e *txExecutor) executeBatchEOA(calls []contractCall) ([]common.Hash, error
 	return hashes, nil
 }
 
-func (e *txExecutor) executeBatchSafe(safeSigner signer.SafeTradingSigner, chainID *big.Int, calls []contractCall) ([]common.Hash, error) {
+func (e *txExecutor) executeBatchSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, calls []contractCall) ([]common.Hash, error) {
 	hashes := make([]common.Hash, 0, len(calls))
 	for i, call := range calls {
-		txHash, err := e.executeSafe(safeSigner, chainID, call)
+		txHash, err := e.executeSafe(ctx, safeSigner, chainID, call)
 		if err != nil {
 			return hashes, fmt.Errorf("batch Safe tx %d failed: %w", i, err)
 		}
 		hashes = append(hashes, txHash)
+		if e.dryRunRecorder != nil {
+			continue
+		}
+		if _, exporting := offline.AsExportingSender(safeSigner); exporting {
+			continue
+		}
 
-		if err := e.waitTxConfirmation(txHash, 3, 2*time.Minute); err != nil {
+		// The tx may have been replaced while waiting: report the hash that was finally mined
+		minedHash, err := e.waitMined(ctx, safeSigner, txHash, 3, 2*time.Minute)
+		if err != nil {
 			return hashes, fmt.Errorf("batch Safe tx %d confirmation failed: %w", i, err)
 		}
+		hashes[i] = minedHash
 	}
 	return hashes, nil
 }
 
-func (e *txExecutor) waitTxConfirmation(txHash common.Hash, confirmations uint64, timeout time.Duration) error {
+// executeMultiSend sends calls from an EOA (safeSigner nil) as a transaction each, or from a Safe as a single
+// MultiSend transaction
+func (e *txExecutor) executeMultiSend(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, calls []contractCall) ([]common.Hash, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+	if safeSigner == nil {
+		return e.executeBatchEOA(ctx, calls)
+	}
+	call := calls[0]
+	if len(calls) > 1 {
+		var err error
+		if call, err = buildMultiSendCall(SafeMultiSendCallOnly, calls); err != nil {
+			return nil, err
+		}
+	}
+	txHash, err := e.executeSafe(ctx, safeSigner, chainID, call)
+	if err != nil {
+		return nil, err
+	}
+	return []common.Hash{txHash}, nil
+}
+
+// executeAfterApproval sends approval from the EOA, waits for it to be mined, then sends call,
+// whose gas could not be estimated before
+func (e *txExecutor) executeAfterApproval(ctx context.Context, approval, call contractCall) ([]common.Hash, error) {
+	approvalHash, err := e.executeEOA(ctx, approval)
+	if err != nil {
+		return nil, fmt.Errorf("approval failed: %w", err)
+	}
+	txHashes := []common.Hash{approvalHash}
+	_, exporting := offline.AsExportingSender(e.txSender)
+	if e.dryRunRecorder == nil && !exporting {
+		if txHashes[0], err = e.waitMined(ctx, e.txSender, approvalHash, 1, 2*time.Minute); err != nil {
+			return txHashes, fmt.Errorf("approval confirmation failed: %w", err)
+		}
+	}
+	txHash, err := e.executeEOA(ctx, call)
+	if err != nil {
+		return txHashes, err
+	}
+	return append(txHashes, txHash), nil
+}
+
+// waitMined is waitTxConfirmation recording the outcome in the journal entry sent as txHash
+func (e *txExecutor) waitMined(ctx context.Context, txSender sender.TransactionSender, txHash common.Hash, confirmations uint64, timeout time.Duration) (common.Hash, error) {
+	minedHash, err := e.waitTxConfirmation(ctx, txSender, txHash, confirmations, timeout)
+	e.markMined(txHash, minedHash, err)
+	return minedHash, err
+}
+
+// waitTxConfirmation waits for txHash to be confirmed and returns the hash that was finally mined.
+// If txSender is (or wraps) a sender.MinedWaiter, it is used so stuck transactions get replaced while waiting.
+func (e *txExecutor) waitTxConfirmation(ctx context.Context, txSender sender.TransactionSender, txHash common.Hash, confirmations uint64, timeout time.Duration) (common.Hash, error) {
 	if e.client == nil {
-		return nil
+		return txHash, nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	if waiter, ok := sender.AsMinedWaiter(txSender); ok {
+		receipt, err := waiter.WaitMined(ctx, txHash)
+		if err != nil {
+			return txHash, fmt.Errorf("tx %s not mined: %w", txHash.Hex(), err)
+		}
+		txHash = receipt.TxHash
+	}
+
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
 
 	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("tx %s not confirmed after %v", txHash.Hex(), timeout)
-		case <-ticker.C:
-			receipt, err := e.client.TransactionReceipt(ctx, txHash)
-			if err != nil || receipt == nil {
-				continue
+		// The receipt is checked right away, then on every tick
+		if receipt, err := e.client.TransactionReceipt(ctx, txHash); err == nil && receipt != nil {
+			if err := revert.ReceiptError(ctx, e.client, receipt); err != nil {
+				return txHash, err
 			}
 			if confirmations == 0 {
-				return nil
-			}
-			currentBlock, err := e.client.BlockNumber(ctx)
-			if err != nil {
-				continue
+				return txHash, nil
 			}
-			if currentBlock >= receipt.BlockNumber.Uint64()+confirmations {
-				return nil
+			if currentBlock, err := e.client.BlockNumber(ctx); err == nil && currentBlock >= receipt.BlockNumber.Uint64()+confirmations {
+				return txHash, nil
 			}
 		}
+
+		select {
+		case <-ctx.Done():
+			return txHash, fmt.Errorf("tx %s not confirmed after %v", txHash.Hex(), timeout)
+		case <-ticker.C:
+		}
 	}
 }
@@ -1,12 +1,20 @@
 package polymarketcontracts
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ivanzzeth/ethsig/eip712"
+	conditional_tokens "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/conditional-tokens"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/erc20"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/journal"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
 	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
 )
 
@@ -54,7 +62,7 @@ func TestExecuteEOA(t *testing.T) {
 		Value:    big.NewInt(42),
 	}
 
-	hash, err := exec.executeEOA(call)
+	hash, err := exec.executeEOA(context.Background(), call)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -76,7 +84,7 @@ func TestExecuteEOA_Error(t *testing.T) {
 	mock := &mockTransactionSender{retErr: errors.New("send failed")}
 	exec := &txExecutor{txSender: mock}
 
-	_, err := exec.executeEOA(contractCall{Value: big.NewInt(0)})
+	_, err := exec.executeEOA(context.Background(), contractCall{Value: big.NewInt(0)})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -97,7 +105,7 @@ func TestExecuteSafe(t *testing.T) {
 			}
 			return safeAddr, nil
 		},
-		execSafeTx: func(ss signer.SafeTradingSigner, chainID *big.Int, safe, to common.Address, value *big.Int, data []byte, op SafeOperation, gas *big.Int) (common.Hash, error) {
+		execSafeTx: func(_ context.Context, ss signer.SafeTradingSigner, chainID *big.Int, safe, to common.Address, value *big.Int, data []byte, op SafeOperation, gas *big.Int, _ ...sender.SendOption) (common.Hash, error) {
 			if safe != safeAddr {
 				t.Errorf("expected safe %s, got %s", safeAddr.Hex(), safe.Hex())
 			}
@@ -106,7 +114,7 @@ func TestExecuteSafe(t *testing.T) {
 	}
 
 	ms := &mockSafeSigner{addr: eoaAddr}
-	hash, err := exec.executeSafe(ms, big.NewInt(137), contractCall{
+	hash, err := exec.executeSafe(context.Background(), ms, big.NewInt(137), contractCall{
 		Target:   common.HexToAddress("0xTarget"),
 		Calldata: []byte{0xAB},
 		Value:    big.NewInt(0),
@@ -127,7 +135,7 @@ func TestExecuteSafe_GetSafeAddrError(t *testing.T) {
 	}
 
 	ms := &mockSafeSigner{addr: common.HexToAddress("0xEOA")}
-	_, err := exec.executeSafe(ms, big.NewInt(137), contractCall{Value: big.NewInt(0)})
+	_, err := exec.executeSafe(context.Background(), ms, big.NewInt(137), contractCall{Value: big.NewInt(0)})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -147,7 +155,7 @@ func TestExecuteBatchEOA(t *testing.T) {
 		{Target: common.HexToAddress("0xB"), Calldata: []byte{2}, Value: big.NewInt(0)},
 	}
 
-	result, err := exec.executeBatchEOA(calls)
+	result, err := exec.executeBatchEOA(context.Background(), calls)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -170,7 +178,7 @@ func TestExecuteBatchEOA_PartialFailure(t *testing.T) {
 		{Target: common.HexToAddress("0xB"), Calldata: []byte{2}, Value: big.NewInt(0)},
 	}
 
-	result, err := exec.executeBatchEOA(calls)
+	result, err := exec.executeBatchEOA(context.Background(), calls)
 	if err == nil {
 		t.Fatal("expected error on second call")
 	}
@@ -207,3 +215,212 @@ func (s *failOnSecondSender) SendEthereumTransaction(to common.Address, data []b
 	}
 	return common.Hash{}, errors.New("boom")
 }
+
+func TestExecuteEOA_CancelledContext(t *testing.T) {
+	mock := &mockTransactionSender{}
+	exec := &txExecutor{txSender: mock}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := exec.executeEOA(ctx, contractCall{Target: testCTF, Value: big.NewInt(0)})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if mock.calls != 0 {
+		t.Error("sender must not be called with a cancelled context")
+	}
+}
+
+// optionsRecordingSender records the per-call options it receives
+type optionsRecordingSender struct {
+	mockTransactionSender
+	opts *sender.SendOptions
+}
+
+func (s *optionsRecordingSender) SendEthereumTransactionWithContext(_ context.Context, to common.Address, data []byte, value *big.Int, opts ...sender.SendOption) (common.Hash, error) {
+	s.opts = sender.ApplySendOptions(opts...)
+	return s.SendEthereumTransaction(to, data, value)
+}
+
+type staticGasPricer struct {
+	urgency sender.Urgency
+}
+
+func (p *staticGasPricer) GasFees(_ context.Context, urgency sender.Urgency) (*sender.GasFees, error) {
+	p.urgency = urgency
+	return &sender.GasFees{GasFeeCap: big.NewInt(200), GasTipCap: big.NewInt(20)}, nil
+}
+
+func TestExecuteEOA_RedeemIsLowUrgency(t *testing.T) {
+	txSender := &optionsRecordingSender{}
+	exec := &txExecutor{txSender: txSender}
+
+	call, err := buildRedeemPositionsCall(testCTF, common.HexToAddress("0xC011"), [32]byte{1}, []*big.Int{big.NewInt(1), big.NewInt(2)})
+	if err != nil {
+		t.Fatalf("buildRedeemPositionsCall: %v", err)
+	}
+	if _, err := exec.executeEOA(context.Background(), call); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if txSender.opts.Urgency != sender.UrgencyLow || !txSender.opts.IsZero() {
+		t.Errorf("expected only the low urgency hint, got %+v", txSender.opts)
+	}
+}
+
+func TestExecuteSafe_GasPricer(t *testing.T) {
+	pricer := &staticGasPricer{urgency: -1}
+	var got *sender.SendOptions
+	exec := &txExecutor{
+		getSafeAddr: func(common.Address) (common.Address, error) { return common.HexToAddress("0x5afe"), nil },
+		execSafeTx: func(_ context.Context, _ signer.SafeTradingSigner, _ *big.Int, _, _ common.Address, _ *big.Int, _ []byte, _ SafeOperation, _ *big.Int, opts ...sender.SendOption) (common.Hash, error) {
+			got = sender.ApplySendOptions(opts...)
+			return common.Hash{}, nil
+		},
+		gasPricer: pricer,
+	}
+
+	if _, err := exec.executeSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), contractCall{Target: testCTF, Value: big.NewInt(0)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pricer.urgency != sender.UrgencyNormal {
+		t.Errorf("expected normal urgency, got %s", pricer.urgency)
+	}
+	if got.GasFeeCap.Int64() != 200 || got.GasTipCap.Int64() != 20 {
+		t.Errorf("expected the pricer's fee caps to be passed to the Safe execution, got %+v", got)
+	}
+}
+
+func TestExecuteSafe_JournalDedupesKeyedCalls(t *testing.T) {
+	j := journal.NewMemoryJournal()
+	sends := 0
+	exec := &txExecutor{
+		getSafeAddr: func(common.Address) (common.Address, error) { return common.HexToAddress("0x5afe"), nil },
+		execSafeTx: func(ctx context.Context, _ signer.SafeTradingSigner, _ *big.Int, _, _ common.Address, _ *big.Int, _ []byte, _ SafeOperation, _ *big.Int, _ ...sender.SendOption) (common.Hash, error) {
+			sends++
+			if _, _, ok := journal.FromContext(ctx); !ok {
+				t.Error("expected the journal entry in the context")
+			}
+			return common.BigToHash(big.NewInt(int64(sends))), nil
+		},
+		journal: j,
+	}
+	owner := &mockSafeSigner{addr: common.HexToAddress("0xEOA")}
+	call := contractCall{Target: testCTF, Calldata: []byte{0xAB}, Value: big.NewInt(0)}
+	ctx := context.Background()
+
+	// Identical calls without a key are all sent
+	for i := 0; i < 2; i++ {
+		if _, err := exec.executeSafe(ctx, owner, big.NewInt(137), call); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if sends != 2 {
+		t.Fatalf("expected both unkeyed calls sent, got %d sends", sends)
+	}
+
+	keyed := journal.WithIdempotencyKey(ctx, "order-1")
+	first, err := exec.executeSafe(keyed, owner, big.NewInt(137), call)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := exec.executeSafe(keyed, owner, big.NewInt(137), call)
+	if !errors.Is(err, ErrCallPending) || second != (common.Hash{}) || sends != 3 {
+		t.Fatalf("expected the keyed duplicate rejected without a hash, got %s, %v after %d sends", second.Hex(), err, sends)
+	}
+
+	// A failed call can be retried with the same key
+	entries, _ := j.List()
+	for _, en := range entries {
+		if en.TxHash == first {
+			_ = j.Update(en.ID, func(en *journal.Entry) { en.Fail(errors.New("dropped")) })
+		}
+	}
+	if _, err := exec.executeSafe(keyed, owner, big.NewInt(137), call); err != nil || sends != 4 {
+		t.Fatalf("expected the failed keyed call sent again, got %v after %d sends", err, sends)
+	}
+
+	entries, _ = j.List()
+	keyedEntries := 0
+	for _, en := range entries {
+		if en.Key == "order-1" && en.Safe == common.HexToAddress("0x5afe") {
+			keyedEntries++
+		}
+	}
+	if len(entries) != 4 || keyedEntries != 2 {
+		t.Errorf("unexpected journal: %+v", entries)
+	}
+}
+
+func TestExecuteEOA_JournalRecordsFailure(t *testing.T) {
+	j := journal.NewMemoryJournal()
+	exec := &txExecutor{txSender: &mockTransactionSender{retErr: errors.New("boom")}, journal: j}
+
+	if _, err := exec.executeEOA(context.Background(), contractCall{Target: testCTF, Value: big.NewInt(0)}); err == nil {
+		t.Fatal("expected error")
+	}
+	entries, _ := j.List()
+	if len(entries) != 1 || entries[0].State != journal.StateFailed {
+		t.Errorf("expected one failed entry, got %+v", entries)
+	}
+}
+
+// minedCallClient is a mockCallClient whose transactions are mined, or reverted, 10 blocks ago
+type minedCallClient struct {
+	mockCallClient
+	reverted map[common.Hash]bool
+}
+
+func (c *minedCallClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	status := types.ReceiptStatusSuccessful
+	if c.reverted[txHash] {
+		status = types.ReceiptStatusFailed
+	}
+	return &types.Receipt{TxHash: txHash, Status: status, BlockNumber: big.NewInt(1)}, nil
+}
+
+func (c *minedCallClient) BlockNumber(_ context.Context) (uint64, error) {
+	return 11, nil
+}
+
+func (c *minedCallClient) TransactionByHash(_ context.Context, _ common.Hash) (*types.Transaction, bool, error) {
+	return nil, false, ethereum.NotFound
+}
+
+func TestEnableTradingForEOA_JournalsAndMarksMined(t *testing.T) {
+	j := journal.NewMemoryJournal()
+	// Every read returns zero: no allowance or approval is set yet
+	client := &minedCallClient{mockCallClient: mockCallClient{returnData: make([]byte, 32)}}
+	hashes := make([]common.Hash, 6)
+	for i := range hashes {
+		hashes[i] = common.BigToHash(big.NewInt(int64(i + 1)))
+	}
+	client.reverted = map[common.Hash]bool{hashes[5]: true}
+	callIdx := 0
+	collateral, _ := erc20.NewErc20(MATIC_CONTRACTS.Collateral, client)
+	ctf, _ := conditional_tokens.NewConditionalTokens(MATIC_CONTRACTS.ConditionalTokens, client)
+	b := &ContractInterface{
+		client:                    client,
+		contractConfig:            MATIC_CONTRACTS,
+		collateralContract:        collateral,
+		conditionalTokensContract: ctf,
+		executor:                  &txExecutor{client: client, txSender: &sequentialMockSender{hashes: hashes, idx: &callIdx}, journal: j},
+	}
+
+	if _, err := b.EnableTradingForEOA(context.Background(), &mockEOASigner{}); !errors.Is(err, revert.ErrTransactionFailed) {
+		t.Fatalf("expected the reverted approval to be reported, got %v", err)
+	}
+
+	entries, _ := j.List()
+	if len(entries) != 6 {
+		t.Fatalf("expected every approval journaled, got %d entries", len(entries))
+	}
+	for _, en := range entries {
+		want := journal.StateMined
+		if en.TxHash == hashes[5] {
+			want = journal.StateFailed
+		}
+		if en.State != want {
+			t.Errorf("entry %s: state %s, want %s", en.TxHash.Hex(), en.State, want)
+		}
+	}
+}
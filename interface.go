@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ivanzzeth/ethclient"
 	"github.com/ivanzzeth/ethsig"
@@ -26,6 +27,10 @@ import (
 	negriskadapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-adapter"
 	negriskfees "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-fees"
 	safeproxyfactory "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/safe-proxy-factory"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/journal"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/offline"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/retry"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
 	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
 	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
 )
@@ -51,6 +56,7 @@ type ContractInterface struct {
 	safeTradingSigner signer.SafeTradingSigner
 	txSender          sender.TransactionSender
 	executor          *txExecutor
+	retryPolicy       retry.Policy
 
 	collateralContract        *erc20.Erc20
 	conditionalTokensContract *conditional_tokens.ConditionalTokens
@@ -60,6 +66,7 @@ type ContractInterface struct {
 	negRiskContract           *negrisk.NegRisk
 	negRiskFeesContract       *negriskfees.NegRiskFees
 	safeProxyFactoryContract  *safeproxyfactory.SafeProxyFactory
+	portfolio                 *portfolioReader
 
 	// Cache for Safe addresses (key: EOA address string, value: Safe address)
 	safeAddressCache sync.Map
@@ -72,6 +79,15 @@ type ContractInterfaceConfig struct {
 	SafeTradingSigner signer.SafeTradingSigner
 
 	ContractConfig *ContractConfig
+
+	// DryRun, when set, makes calls built by the executor simulated and recorded instead of sent
+	DryRun *DryRunRecorder
+	// GasPricer, when set, chooses the fees of every transaction sent by the executor, including Safe executions
+	GasPricer sender.GasPricer
+	// Journal, when set, records every call sent by the executor for crash recovery
+	Journal journal.Journal
+	// RetryPolicy retries transient errors of contract reads, gas estimation and gas pricing (default: retry.DefaultPolicy)
+	RetryPolicy *retry.Policy
 }
 
 type ContractInterfaceOption func(c *ContractInterfaceConfig)
@@ -102,6 +118,43 @@ func WithTransactionSender(txSender sender.TransactionSender) ContractInterfaceO
 	}
 }
 
+// WithDryRun simulates every call built by the interface with eth_call from the acting account
+// (EOA, or the Safe through simulateAndRevert) and records the outcome in recorder instead of sending it.
+// This includes EnableTrading approvals and Safe deployment.
+// Methods return a zero hash, or an error wrapping ErrDryRunReverted if the call would revert.
+func WithDryRun(recorder *DryRunRecorder) ContractInterfaceOption {
+	return func(c *ContractInterfaceConfig) {
+		c.DryRun = recorder
+	}
+}
+
+// WithGasPricer makes the interface choose transaction fees with pricer instead of leaving them to the sender.
+// Redeems are priced with sender.UrgencyLow, other calls with sender.UrgencyNormal.
+func WithGasPricer(pricer sender.GasPricer) ContractInterfaceOption {
+	return func(c *ContractInterfaceConfig) {
+		c.GasPricer = pricer
+	}
+}
+
+// WithJournal records every call built by the interface, the transaction sent for it and its state in j.
+// Calls made with a context from journal.WithIdempotencyKey are not sent again while an entry for the same
+// call and key is pending or mined. Entries are marked mined or failed when the interface waits for their
+// receipt (EnableTrading, Safe batches); others stay sent until journal.Recover reconciles them.
+// Call journal.Recover on startup to reconcile what a previous process left pending.
+func WithJournal(j journal.Journal) ContractInterfaceOption {
+	return func(c *ContractInterfaceConfig) {
+		c.Journal = j
+	}
+}
+
+// WithRetryPolicy sets how transient RPC errors (timeouts, rate limits, dropped connections) of contract
+// reads, gas estimation and gas pricing are retried. Reverts are never retried. Use retry.NoRetry to disable retries.
+func WithRetryPolicy(policy retry.Policy) ContractInterfaceOption {
+	return func(c *ContractInterfaceConfig) {
+		c.RetryPolicy = &policy
+	}
+}
+
 func NewContractInterface(
 	client ethclient.EthClientInterface,
 	options ...ContractInterfaceOption,
@@ -109,6 +162,7 @@ func NewContractInterface(
 	defaultOptions := &ContractInterfaceConfig{
 		SignatureType:  SignatureTypeEOA,
 		ContractConfig: MATIC_CONTRACTS,
+		RetryPolicy:    &retry.DefaultPolicy,
 	}
 
 	chainID, err := client.ChainID(context.Background())
@@ -137,44 +191,47 @@ func NewContractInterface(
 		defaultOptions.TxSender = txSender
 	}
 
+	// Contract reads retry transient errors
+	backend := retry.NewBackend(client, *defaultOptions.RetryPolicy)
+
 	// Initialize Collateral (USDC) contract
-	usdcContract, err := erc20.NewErc20(defaultOptions.ContractConfig.Collateral, client)
+	usdcContract, err := erc20.NewErc20(defaultOptions.ContractConfig.Collateral, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup USDC: %v", err)
 	}
 
 	// Initialize Conditional Tokens Framework contract
-	ctfContract, err := conditional_tokens.NewConditionalTokens(defaultOptions.ContractConfig.ConditionalTokens, client)
+	ctfContract, err := conditional_tokens.NewConditionalTokens(defaultOptions.ContractConfig.ConditionalTokens, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup ConditionalTokens: %v", err)
 	}
 
 	// Initialize Exchange contract
-	exchangeContract, err := exchange.NewExchange(defaultOptions.ContractConfig.Exchange, client)
+	exchangeContract, err := exchange.NewExchange(defaultOptions.ContractConfig.Exchange, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup Exchange: %v", err)
 	}
 
 	// Initialize Exchange Fees contract
-	exchangeFeesContract, err := exchangefees.NewExchangeFees(defaultOptions.ContractConfig.Exchange, client)
+	exchangeFeesContract, err := exchangefees.NewExchangeFees(defaultOptions.ContractConfig.Exchange, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup ExchangeFees: %v", err)
 	}
 
 	// Initialize NegRisk Adapter contract
-	negRiskAdapterContract, err := negriskadapter.NewNegRiskAdapter(defaultOptions.ContractConfig.NegRiskAdapter, client)
+	negRiskAdapterContract, err := negriskadapter.NewNegRiskAdapter(defaultOptions.ContractConfig.NegRiskAdapter, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup NegRiskAdapter: %v", err)
 	}
 
 	// Initialize NegRisk Exchange contract
-	negRiskContract, err := negrisk.NewNegRisk(defaultOptions.ContractConfig.NegRiskExchange, client)
+	negRiskContract, err := negrisk.NewNegRisk(defaultOptions.ContractConfig.NegRiskExchange, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup NegRisk: %v", err)
 	}
 
 	// Initialize NegRisk Fees contract
-	negRiskFeesContract, err := negriskfees.NewNegRiskFees(defaultOptions.ContractConfig.NegRiskExchange, client)
+	negRiskFeesContract, err := negriskfees.NewNegRiskFees(defaultOptions.ContractConfig.NegRiskExchange, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup NegRiskFees: %v", err)
 	}
@@ -182,12 +239,19 @@ func NewContractInterface(
 	// Initialize SafeProxyFactory contract (optional, may be zero address)
 	var safeProxyFactoryContract *safeproxyfactory.SafeProxyFactory
 	if defaultOptions.ContractConfig.SafeProxyFactory != (common.Address{}) {
-		safeProxyFactoryContract, err = safeproxyfactory.NewSafeProxyFactory(defaultOptions.ContractConfig.SafeProxyFactory, client)
+		safeProxyFactoryContract, err = safeproxyfactory.NewSafeProxyFactory(defaultOptions.ContractConfig.SafeProxyFactory, backend)
 		if err != nil {
 			return nil, fmt.Errorf("failed to setup SafeProxyFactory: %v", err)
 		}
 	}
 
+	portfolio, err := newPortfolioReader(defaultOptions.ContractConfig, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup portfolio reader: %v", err)
+	}
+
+	registerRevertAddresses(defaultOptions.ContractConfig)
+
 	ci := &ContractInterface{
 		chainID:           chainID,
 		client:            client,
@@ -196,6 +260,7 @@ func NewContractInterface(
 		txSender:          defaultOptions.TxSender,
 		safeTradingSigner: defaultOptions.SafeTradingSigner,
 		eoaTradingSigner:  defaultOptions.EOATradingSigner,
+		retryPolicy:       *defaultOptions.RetryPolicy,
 
 		collateralContract:        usdcContract,
 		conditionalTokensContract: ctfContract,
@@ -205,13 +270,19 @@ func NewContractInterface(
 		negRiskContract:           negRiskContract,
 		negRiskFeesContract:       negRiskFeesContract,
 		safeProxyFactoryContract:  safeProxyFactoryContract,
+		portfolio:                 portfolio,
 	}
 
 	ci.executor = &txExecutor{
 		client:      ci.client,
 		txSender:    ci.txSender,
 		getSafeAddr: ci.GetSafeAddress,
-		execSafeTx:  ci.ExecuteTransactionBySafeAndSingleSigner,
+		execSafeTx:  ci.ExecuteTransactionBySafeAndSingleSignerWithContext,
+
+		dryRunRecorder: defaultOptions.DryRun,
+		gasPricer:      defaultOptions.GasPricer,
+		journal:        defaultOptions.Journal,
+		retryPolicy:    *defaultOptions.RetryPolicy,
 	}
 
 	return ci, nil
@@ -273,17 +344,56 @@ func (b *ContractInterface) GetClient() ethclient.EthClientInterface {
 }
 
 // waitTxReceipts waits for all tx hashes to be confirmed using the underlying ethclient.Client.WaitTxReceipt.
-// If client is not *ethclient.Client, returns nil (no wait, caller assumes responsibility).
-func (b *ContractInterface) waitTxReceipts(txHashes []common.Hash, confirmations uint64, timeout time.Duration) error {
+// If txSender is (or wraps) a sender.MinedWaiter, stuck transactions are replaced while waiting and
+// txHashes is updated in place with the hashes that were finally mined.
+// If client is not *ethclient.Client, receipts are polled until confirmed.
+// Journal entries of the transactions are marked mined or failed once their receipt is known.
+func (b *ContractInterface) waitTxReceipts(txSender sender.TransactionSender, txHashes []common.Hash, confirmations uint64, timeout time.Duration) error {
+	if _, exporting := offline.AsExportingSender(txSender); exporting {
+		// Nothing was sent: the transactions are waiting in an offline bundle
+		return nil
+	}
+	sent := append([]common.Hash(nil), txHashes...)
+	if waiter, ok := sender.AsMinedWaiter(txSender); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		for i, h := range txHashes {
+			receipt, err := waiter.WaitMined(ctx, h)
+			if err != nil {
+				err = fmt.Errorf("tx %s not mined: %w", h.Hex(), err)
+				if receipt != nil {
+					h = receipt.TxHash
+				}
+				b.executor.markMined(sent[i], h, err)
+				return err
+			}
+			txHashes[i] = receipt.TxHash
+		}
+	}
+
 	client, ok := b.client.(*ethclient.Client)
 	if !ok {
+		// Other clients (e.g. multiclient.Client) are polled for receipts
+		for i, h := range txHashes {
+			hash, err := b.executor.waitTxConfirmation(context.Background(), nil, h, confirmations, timeout)
+			b.executor.markMined(sent[i], hash, err)
+			if err != nil {
+				return err
+			}
+			txHashes[i] = hash
+		}
 		return nil
 	}
-	for _, h := range txHashes {
-		_, confirmed := client.WaitTxReceipt(h, confirmations, timeout)
+	for i, h := range txHashes {
+		receipt, confirmed := client.WaitTxReceipt(h, confirmations, timeout)
 		if !confirmed {
 			return fmt.Errorf("tx %s not confirmed after %v", h.Hex(), timeout)
 		}
+		err := revert.ReceiptError(context.Background(), b.client, receipt)
+		b.executor.markMined(sent[i], h, err)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -459,7 +569,9 @@ func (b *ContractInterface) EstimateSafeTxGas(safeAddr, to common.Address, value
 
 	// EstimateGas will fail because simulateAndRevert always reverts,
 	// but we can extract the gas estimation from the error
-	gasLimit, err := b.client.EstimateGas(context.Background(), msg)
+	gasLimit, err := retry.DoValue(context.Background(), b.retryPolicy, func() (uint64, error) {
+		return b.client.EstimateGas(context.Background(), msg)
+	})
 	if err != nil {
 		// simulateAndRevert always reverts, so we expect an error
 		// However, EstimateGas still provides a gas estimate
@@ -474,9 +586,11 @@ func (b *ContractInterface) EstimateSafeTxGas(safeAddr, to common.Address, value
 			Data:  targetCallData,
 		}
 
-		directGas, directErr := b.client.EstimateGas(context.Background(), directMsg)
+		directGas, directErr := retry.DoValue(context.Background(), b.retryPolicy, func() (uint64, error) {
+			return b.client.EstimateGas(context.Background(), directMsg)
+		})
 		if directErr != nil {
-			return nil, fmt.Errorf("failed to estimate gas: %w", directErr)
+			return nil, fmt.Errorf("failed to estimate gas: %w", revert.Wrap(&to, directErr))
 		}
 
 		// Add Safe overhead: approximately 15000 gas for Safe execution logic
@@ -530,9 +644,12 @@ func (b *ContractInterface) EnableTrading(ctx context.Context) ([]common.Hash, e
 	}
 }
 
-func (b *ContractInterface) Redeem(ctx context.Context, conditionId [32]byte) (common.Hash, error) {
-	// For Polymarket binary markets, indexSets is always [1, 2] representing Yes/No outcomes
-	indexSets := []*big.Int{big.NewInt(1), big.NewInt(2)}
+// Redeem redeems every outcome of the condition, or the index sets of WithPartition
+func (b *ContractInterface) Redeem(ctx context.Context, conditionId [32]byte, opts ...PositionOption) (common.Hash, error) {
+	indexSets, err := resolvePartition(ctx, b.conditionalTokensContract, conditionId, false, opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
 
 	switch b.signatureType {
 	case SignatureTypePolyGnosisSafe:
@@ -555,9 +672,13 @@ func (b *ContractInterface) RedeemNegRisk(ctx context.Context, conditionId [32]b
 	}
 }
 
-func (b *ContractInterface) Split(ctx context.Context, conditionId [32]byte, amount *big.Int) (common.Hash, error) {
-	// For Polymarket binary markets, partition is always [1, 2] representing Yes/No outcomes
-	partition := []*big.Int{big.NewInt(1), big.NewInt(2)}
+// Split splits collateral into every outcome of the condition, or the partition of WithPartition.
+// A nil amount splits the entire collateral balance.
+func (b *ContractInterface) Split(ctx context.Context, conditionId [32]byte, amount *big.Int, opts ...PositionOption) (common.Hash, error) {
+	partition, err := resolvePartition(ctx, b.conditionalTokensContract, conditionId, true, opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
 
 	switch b.signatureType {
 	case SignatureTypePolyGnosisSafe:
@@ -569,9 +690,13 @@ func (b *ContractInterface) Split(ctx context.Context, conditionId [32]byte, amo
 	}
 }
 
-func (b *ContractInterface) Merge(ctx context.Context, conditionId [32]byte, amount *big.Int) (common.Hash, error) {
-	// For Polymarket binary markets, partition is always [1, 2] representing Yes/No outcomes
-	partition := []*big.Int{big.NewInt(1), big.NewInt(2)}
+// Merge merges the outcomes of the condition, or the partition of WithPartition, back into collateral.
+// A nil amount merges every complete set held.
+func (b *ContractInterface) Merge(ctx context.Context, conditionId [32]byte, amount *big.Int, opts ...PositionOption) (common.Hash, error) {
+	partition, err := resolvePartition(ctx, b.conditionalTokensContract, conditionId, true, opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
 
 	switch b.signatureType {
 	case SignatureTypePolyGnosisSafe:
@@ -679,15 +804,18 @@ func (b *ContractInterface) DeploySafeWithSig(txSender sender.TransactionSender,
 		return
 	}
 
-	txHash, err = txSender.SendEthereumTransaction(b.contractConfig.SafeProxyFactory, createProxyData, big.NewInt(0))
-	if err != nil {
-		return
-	}
-
+	// Sent through the executor so the deployment is journaled, and simulated instead in dry-run mode
+	txHash, err = b.executor.executeEOABy(context.Background(), txSender, contractCall{Target: b.contractConfig.SafeProxyFactory, Calldata: createProxyData, Value: big.NewInt(0)})
 	return
 }
 
 func (b *ContractInterface) ExecuteTransactionBySafeAndSingleSigner(safeSigner signer.SafeTradingSigner, chainID *big.Int, safeAddr common.Address, to common.Address, value *big.Int, data []byte, operation SafeOperation, safeTxGas *big.Int) (common.Hash, error) {
+	return b.ExecuteTransactionBySafeAndSingleSignerWithContext(context.Background(), safeSigner, chainID, safeAddr, to, value, data, operation, safeTxGas)
+}
+
+// ExecuteTransactionBySafeAndSingleSignerWithContext is ExecuteTransactionBySafeAndSingleSigner with a context
+// and per-call options for the outer transaction sent by the Safe owner.
+func (b *ContractInterface) ExecuteTransactionBySafeAndSingleSignerWithContext(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, safeAddr common.Address, to common.Address, value *big.Int, data []byte, operation SafeOperation, safeTxGas *big.Int, opts ...sender.SendOption) (common.Hash, error) {
 	baseGas := big.NewInt(0)
 	gasPrice := big.NewInt(0)
 	gasToken := common.Address{}
@@ -697,10 +825,18 @@ func (b *ContractInterface) ExecuteTransactionBySafeAndSingleSigner(safeSigner s
 		return common.Hash{}, err
 	}
 
-	nonce, err := safeContract.Nonce(nil)
+	nonce, err := safeContract.Nonce(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return common.Hash{}, err
 	}
+	// An offline owner signs later: consecutive exported Safe transactions take consecutive nonces
+	exporter, exporting := offline.AsExportingSender(safeSigner)
+	if exporting {
+		nonce = exporter.Bundle().NextSafeNonce(safeAddr, nonce)
+	}
+	if err := journal.Record(ctx, func(e *journal.Entry) { e.SafeNonce = (*hexutil.Big)(nonce) }); err != nil {
+		return common.Hash{}, err
+	}
 
 	// Estimate safeTxGas if not explicitly set or set to 0
 	// IMPORTANT: Must do this BEFORE signing, as safeTxGas is part of the signed data
@@ -714,9 +850,12 @@ func (b *ContractInterface) ExecuteTransactionBySafeAndSingleSigner(safeSigner s
 
 	// Build the Safe transaction typed data with the correct safeTxGas
 	typedData := BuildSafeTransactionTypedData(chainID, safeAddr, to, value, data, operation, safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, nonce)
+	if exporting {
+		return exportSafeTx(ctx, exporter, typedData, safeAddr, to, value, data, operation, safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, nonce, opts...)
+	}
 
 	// Sign the typed data
-	signature, err := safeSigner.SignTypedData(typedData)
+	signature, err := signer.SignTypedDataWithContext(ctx, safeSigner, typedData)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to sign Safe transaction: %w", err)
 	}
@@ -736,24 +875,29 @@ func (b *ContractInterface) ExecuteTransactionBySafeAndSingleSigner(safeSigner s
 	}
 
 	// Encode transaction data for signature verification
-	encodedTxData, err := safeL2.EncodeTransactionData(nil, to, value, data, uint8(operation), safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, nonce)
+	encodedTxData, err := safeL2.EncodeTransactionData(&bind.CallOpts{Context: ctx}, to, value, data, uint8(operation), safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, nonce)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to encode transaction data: %w", err)
 	}
 	// fmt.Printf("   Encoded transaction data length: %d bytes\n", len(encodedTxData))
 
 	// Verify signature using Safe contract's checkSignatures method
-	err = safeL2.CheckSignatures(nil, common.BytesToHash(safeTxHash), encodedTxData, signature)
+	err = safeL2.CheckSignatures(&bind.CallOpts{Context: ctx}, common.BytesToHash(safeTxHash), encodedTxData, signature)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("signature verification failed: %w", err)
+		return common.Hash{}, fmt.Errorf("signature verification failed: %w", revert.Wrap(&safeAddr, err))
 	}
 	// fmt.Println("✅ Signature verification passed")
 
 	// Execute the transaction with the signature
-	return b.ExecuteTransactionBySafe(safeSigner, safeAddr, to, value, data, operation, safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, signature)
+	return b.ExecuteTransactionBySafeWithContext(ctx, safeSigner, safeAddr, to, value, data, operation, safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, signature, opts...)
 }
 
 func (b *ContractInterface) ExecuteTransactionBySafe(txSender sender.TransactionSender, safeAddr common.Address, to common.Address, value *big.Int, data []byte, operation SafeOperation, safeTxGas *big.Int, baseGas *big.Int, gasPrice *big.Int, gasToken common.Address, refundReceiver common.Address, signatures []byte) (common.Hash, error) {
+	return b.ExecuteTransactionBySafeWithContext(context.Background(), txSender, safeAddr, to, value, data, operation, safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, signatures)
+}
+
+// ExecuteTransactionBySafeWithContext is ExecuteTransactionBySafe with a context and per-call send options
+func (b *ContractInterface) ExecuteTransactionBySafeWithContext(ctx context.Context, txSender sender.TransactionSender, safeAddr common.Address, to common.Address, value *big.Int, data []byte, operation SafeOperation, safeTxGas *big.Int, baseGas *big.Int, gasPrice *big.Int, gasToken common.Address, refundReceiver common.Address, signatures []byte, opts ...sender.SendOption) (common.Hash, error) {
 	safeAbi, err := gnosissafel2.GnosisSafeL2MetaData.GetAbi()
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to get Safe ABI: %w", err)
@@ -776,7 +920,7 @@ func (b *ContractInterface) ExecuteTransactionBySafe(txSender sender.Transaction
 		return common.Hash{}, fmt.Errorf("failed to pack execTransaction: %w", err)
 	}
 
-	txHash, err := txSender.SendEthereumTransaction(safeAddr, execTransactionData, big.NewInt(0))
+	txHash, err := sender.AsContextTransactionSender(txSender).SendEthereumTransactionWithContext(ctx, safeAddr, execTransactionData, big.NewInt(0), opts...)
 	if err != nil {
 		if strings.Contains(err.Error(), "GS010") {
 			return common.Hash{}, fmt.Errorf("GS010 but signature is valid, considering not enough gas token")
@@ -786,19 +930,71 @@ func (b *ContractInterface) ExecuteTransactionBySafe(txSender sender.Transaction
 	return txHash, nil
 }
 
-// EnableTradingForSafe enables trading for a Safe wallet by setting all required allowances
-// This function uses ExecuteTransactionBySafeAndSingleSigner to execute all approval operations
+// enableTradingStep is one approval set by V1 EnableTrading
+type enableTradingStep struct {
+	label string // e.g. "USDC → Exchange"
+	call  contractCall
+}
+
+// enableTradingSteps returns the approvals still missing in info, in the order they are set.
+// The USDC → ConditionalTokens approval is only included when withConditionalTokens is set.
+func (b *ContractInterface) enableTradingSteps(info *BalanceAllowanceInfo, withConditionalTokens bool) ([]enableTradingStep, error) {
+	maxAllowance := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	var steps []enableTradingStep
+	for _, a := range []struct {
+		label     string
+		spender   common.Address
+		allowance *big.Int
+		skip      bool
+	}{
+		{"USDC → Exchange", b.contractConfig.Exchange, info.AllowanceExchange, false},
+		{"USDC → ConditionalTokens", b.contractConfig.ConditionalTokens, info.AllowanceConditionalTokens, !withConditionalTokens},
+		{"USDC → NegRiskAdapter", b.contractConfig.NegRiskAdapter, info.AllowanceNegRiskAdapter, false},
+		{"USDC → NegRiskExchange", b.contractConfig.NegRiskExchange, info.AllowanceNegRiskExchange, false},
+	} {
+		if a.skip || a.allowance.Sign() != 0 {
+			continue
+		}
+		call, err := buildERC20ApproveCall(b.contractConfig.Collateral, a.spender, maxAllowance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s approval: %w", a.label, err)
+		}
+		steps = append(steps, enableTradingStep{label: a.label, call: call})
+	}
+
+	for _, a := range []struct {
+		label    string
+		operator common.Address
+		approved bool
+	}{
+		{"CTF → Exchange", b.contractConfig.Exchange, info.CTFApprovedExchange},
+		{"CTF → NegRiskAdapter", b.contractConfig.NegRiskAdapter, info.CTFApprovedNegRiskAdapter},
+		{"CTF → NegRiskExchange", b.contractConfig.NegRiskExchange, info.CTFApprovedNegRiskExchange},
+	} {
+		if a.approved {
+			continue
+		}
+		call, err := buildSetApprovalForAllCall(b.contractConfig.ConditionalTokens, a.operator, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s approval: %w", a.label, err)
+		}
+		steps = append(steps, enableTradingStep{label: a.label, call: call})
+	}
+	return steps, nil
+}
+
+// EnableTradingForSafe enables trading for a Safe wallet by setting all required allowances.
+// Each approval is executed by the Safe and confirmed before the next one is sent.
 func (b *ContractInterface) EnableTradingForSafe(
 	ctx context.Context,
 	safeSigner signer.SafeTradingSigner,
-	// txSigner signer.TypedDataSigner,
 	chainID *big.Int,
 ) ([]common.Hash, error) {
 	safeAddr, err := b.GetSafeAddress(safeSigner.GetAddress())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Safe address: %w", err)
 	}
-	var txHashes []common.Hash
 
 	// Check current status
 	info, err := b.CheckBalanceAndAllowance(ctx, safeAddr)
@@ -821,191 +1017,21 @@ func (b *ContractInterface) EnableTradingForSafe(
 	fmt.Printf("  CTF → NegRiskExchange: %s\n", checkmark(info.CTFApprovedNegRiskExchange))
 	fmt.Println()
 
-	// Maximum allowance for ERC20 approvals
-	maxAllowance := new(big.Int)
-	maxAllowance.SetString("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", 16)
-
-	// Parse ERC20 ABI for approve function
-	erc20ABI, err := abi.JSON(strings.NewReader(erc20.Erc20MetaData.ABI))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
-	}
-
-	// Parse ConditionalTokens ABI for setApprovalForAll function
-	ctfABI, err := abi.JSON(strings.NewReader(conditional_tokens.ConditionalTokensMetaData.ABI))
+	steps, err := b.enableTradingSteps(info, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse ConditionalTokens ABI: %w", err)
+		return nil, err
 	}
 
-	// Approve USDC for all contracts if needed
-	if info.AllowanceExchange.Cmp(big.NewInt(0)) == 0 {
-		fmt.Printf("⚠️  Setting USDC → Exchange approval...\n")
-		approveData, err := erc20ABI.Pack("approve", b.contractConfig.Exchange, maxAllowance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack approve data for Exchange: %w", err)
-		}
-
-		txHash, err := b.ExecuteTransactionBySafeAndSingleSigner(
-			safeSigner, chainID, safeAddr,
-			b.contractConfig.Collateral, big.NewInt(0), approveData,
-			SafeOperationCall, big.NewInt(0),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute Safe transaction for USDC → Exchange approval: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-		fmt.Printf("✅ USDC → Exchange approval transaction: %s\n", txHash.Hex())
-		if err := b.waitTxReceipts([]common.Hash{txHash}, 3, 1*time.Minute); err != nil {
-			return txHashes, err
-		}
-	} else {
-		fmt.Printf("✅ USDC → Exchange already approved\n")
-	}
-
-	// Approve USDC for ConditionalTokens (needed for split/merge operations)
-	if info.AllowanceConditionalTokens.Cmp(big.NewInt(0)) == 0 {
-		fmt.Printf("⚠️  Setting USDC → ConditionalTokens approval...\n")
-		approveData, err := erc20ABI.Pack("approve", b.contractConfig.ConditionalTokens, maxAllowance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack approve data for ConditionalTokens: %w", err)
-		}
-
-		txHash, err := b.ExecuteTransactionBySafeAndSingleSigner(
-			safeSigner, chainID, safeAddr,
-			b.contractConfig.Collateral, big.NewInt(0), approveData,
-			SafeOperationCall, big.NewInt(0),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute Safe transaction for USDC → ConditionalTokens approval: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-		fmt.Printf("✅ USDC → ConditionalTokens approval transaction: %s\n", txHash.Hex())
-		if err := b.waitTxReceipts([]common.Hash{txHash}, 3, 1*time.Minute); err != nil {
-			return txHashes, err
-		}
-	} else {
-		fmt.Printf("✅ USDC → ConditionalTokens already approved\n")
-	}
-
-	if info.AllowanceNegRiskAdapter.Cmp(big.NewInt(0)) == 0 {
-		fmt.Printf("⚠️  Setting USDC → NegRiskAdapter approval...\n")
-		approveData, err := erc20ABI.Pack("approve", b.contractConfig.NegRiskAdapter, maxAllowance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack approve data for NegRiskAdapter: %w", err)
-		}
-
-		txHash, err := b.ExecuteTransactionBySafeAndSingleSigner(
-			safeSigner, chainID, safeAddr,
-			b.contractConfig.Collateral, big.NewInt(0), approveData,
-			SafeOperationCall, big.NewInt(0),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute Safe transaction for USDC → NegRiskAdapter approval: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-		fmt.Printf("✅ USDC → NegRiskAdapter approval transaction: %s\n", txHash.Hex())
-		if err := b.waitTxReceipts([]common.Hash{txHash}, 3, 1*time.Minute); err != nil {
-			return txHashes, err
-		}
-	} else {
-		fmt.Printf("✅ USDC → NegRiskAdapter already approved\n")
-	}
-
-	if info.AllowanceNegRiskExchange.Cmp(big.NewInt(0)) == 0 {
-		fmt.Printf("⚠️  Setting USDC → NegRiskExchange approval...\n")
-		approveData, err := erc20ABI.Pack("approve", b.contractConfig.NegRiskExchange, maxAllowance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack approve data for NegRiskExchange: %w", err)
-		}
-
-		txHash, err := b.ExecuteTransactionBySafeAndSingleSigner(
-			safeSigner, chainID, safeAddr,
-			b.contractConfig.Collateral, big.NewInt(0), approveData,
-			SafeOperationCall, big.NewInt(0),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute Safe transaction for USDC → NegRiskExchange approval: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-		fmt.Printf("✅ USDC → NegRiskExchange approval transaction: %s\n", txHash.Hex())
-		if err := b.waitTxReceipts([]common.Hash{txHash}, 3, 1*time.Minute); err != nil {
-			return txHashes, err
-		}
-	} else {
-		fmt.Printf("✅ USDC → NegRiskExchange already approved\n")
-	}
-
-	// Approve CTF for all contracts if needed
-	if !info.CTFApprovedExchange {
-		fmt.Printf("⚠️  Setting CTF → Exchange approval...\n")
-		setApprovalData, err := ctfABI.Pack("setApprovalForAll", b.contractConfig.Exchange, true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack setApprovalForAll data for Exchange: %w", err)
-		}
-
-		txHash, err := b.ExecuteTransactionBySafeAndSingleSigner(
-			safeSigner, chainID, safeAddr,
-			b.contractConfig.ConditionalTokens, big.NewInt(0), setApprovalData,
-			SafeOperationCall, big.NewInt(0),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute Safe transaction for CTF → Exchange approval: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-		fmt.Printf("✅ CTF → Exchange approval transaction: %s\n", txHash.Hex())
-		if err := b.waitTxReceipts([]common.Hash{txHash}, 3, 1*time.Minute); err != nil {
-			return txHashes, err
-		}
-	} else {
-		fmt.Printf("✅ CTF → Exchange already approved\n")
-	}
-
-	if !info.CTFApprovedNegRiskAdapter {
-		fmt.Printf("⚠️  Setting CTF → NegRiskAdapter approval...\n")
-		setApprovalData, err := ctfABI.Pack("setApprovalForAll", b.contractConfig.NegRiskAdapter, true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack setApprovalForAll data for NegRiskAdapter: %w", err)
-		}
-
-		txHash, err := b.ExecuteTransactionBySafeAndSingleSigner(
-			safeSigner, chainID, safeAddr,
-			b.contractConfig.ConditionalTokens, big.NewInt(0), setApprovalData,
-			SafeOperationCall, big.NewInt(0),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute Safe transaction for CTF → NegRiskAdapter approval: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-		fmt.Printf("✅ CTF → NegRiskAdapter approval transaction: %s\n", txHash.Hex())
-		if err := b.waitTxReceipts([]common.Hash{txHash}, 3, 1*time.Minute); err != nil {
-			return txHashes, err
-		}
-	} else {
-		fmt.Printf("✅ CTF → NegRiskAdapter already approved\n")
-	}
-
-	if !info.CTFApprovedNegRiskExchange {
-		fmt.Printf("⚠️  Setting CTF → NegRiskExchange approval...\n")
-		setApprovalData, err := ctfABI.Pack("setApprovalForAll", b.contractConfig.NegRiskExchange, true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack setApprovalForAll data for NegRiskExchange: %w", err)
-		}
-
-		txHash, err := b.ExecuteTransactionBySafeAndSingleSigner(
-			safeSigner, chainID, safeAddr,
-			b.contractConfig.ConditionalTokens, big.NewInt(0), setApprovalData,
-			SafeOperationCall, big.NewInt(0),
-		)
+	var txHashes []common.Hash
+	for _, step := range steps {
+		fmt.Printf("⚠️  Setting %s approval...\n", step.label)
+		// A batch of one waits for the receipt, unless the call is dry-run or exported
+		hashes, err := b.executor.executeBatchSafe(ctx, safeSigner, chainID, []contractCall{step.call})
+		txHashes = append(txHashes, hashes...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to execute Safe transaction for CTF → NegRiskExchange approval: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-		fmt.Printf("✅ CTF → NegRiskExchange approval transaction: %s\n", txHash.Hex())
-		if err := b.waitTxReceipts([]common.Hash{txHash}, 3, 1*time.Minute); err != nil {
-			return txHashes, err
+			return txHashes, fmt.Errorf("failed to execute Safe transaction for %s approval: %w", step.label, err)
 		}
-	} else {
-		fmt.Printf("✅ CTF → NegRiskExchange already approved\n")
+		fmt.Printf("✅ %s approval transaction: %s\n", step.label, hashes[0].Hex())
 	}
 
 	if len(txHashes) == 0 {
@@ -1023,109 +1049,32 @@ func (b *ContractInterface) EnableTradingForEOA(
 	ctx context.Context,
 	eoaSigner signer.EOATradingSigner,
 ) ([]common.Hash, error) {
-	var txHashes []common.Hash
-
-	eoaAddr := eoaSigner.GetAddress()
-	txSender := b.getTxSender()
-
-	// Check current status
-	info, err := b.CheckBalanceAndAllowance(ctx, eoaAddr)
+	owner, err := b.executor.eoaAddress(eoaSigner)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check balance and allowance: %w", err)
+		return nil, err
 	}
 
-	// Maximum allowance for ERC20 approvals
-	maxAllowance := new(big.Int)
-	maxAllowance.SetString("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", 16)
-
-	// Parse ERC20 ABI for approve function
-	erc20ABI, err := abi.JSON(strings.NewReader(erc20.Erc20MetaData.ABI))
+	// Check current status
+	info, err := b.CheckBalanceAndAllowance(ctx, owner)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+		return nil, fmt.Errorf("failed to check balance and allowance: %w", err)
 	}
 
-	// Parse ConditionalTokens ABI for setApprovalForAll function
-	ctfABI, err := abi.JSON(strings.NewReader(conditional_tokens.ConditionalTokensMetaData.ABI))
+	steps, err := b.enableTradingSteps(info, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse ConditionalTokens ABI: %w", err)
+		return nil, err
 	}
-
-	// Approve USDC for all contracts if needed
-	if info.AllowanceExchange.Cmp(big.NewInt(0)) == 0 {
-		approveData, err := erc20ABI.Pack("approve", b.contractConfig.Exchange, maxAllowance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack approve data for Exchange: %w", err)
-		}
-		txHash, err := txSender.SendEthereumTransaction(b.contractConfig.Collateral, approveData, big.NewInt(0))
-		if err != nil {
-			return nil, fmt.Errorf("failed to send USDC → Exchange approval transaction: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
+	calls := make([]contractCall, len(steps))
+	for i, step := range steps {
+		calls[i] = step.call
 	}
 
-	if info.AllowanceNegRiskAdapter.Cmp(big.NewInt(0)) == 0 {
-		approveData, err := erc20ABI.Pack("approve", b.contractConfig.NegRiskAdapter, maxAllowance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack approve data for NegRiskAdapter: %w", err)
-		}
-		txHash, err := txSender.SendEthereumTransaction(b.contractConfig.Collateral, approveData, big.NewInt(0))
-		if err != nil {
-			return nil, fmt.Errorf("failed to send USDC → NegRiskAdapter approval transaction: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-	}
-
-	if info.AllowanceNegRiskExchange.Cmp(big.NewInt(0)) == 0 {
-		approveData, err := erc20ABI.Pack("approve", b.contractConfig.NegRiskExchange, maxAllowance)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack approve data for NegRiskExchange: %w", err)
-		}
-		txHash, err := txSender.SendEthereumTransaction(b.contractConfig.Collateral, approveData, big.NewInt(0))
-		if err != nil {
-			return nil, fmt.Errorf("failed to send USDC → NegRiskExchange approval transaction: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-	}
-
-	// Approve CTF for all contracts if needed
-	if !info.CTFApprovedExchange {
-		setApprovalData, err := ctfABI.Pack("setApprovalForAll", b.contractConfig.Exchange, true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack setApprovalForAll data for Exchange: %w", err)
-		}
-		txHash, err := txSender.SendEthereumTransaction(b.contractConfig.ConditionalTokens, setApprovalData, big.NewInt(0))
-		if err != nil {
-			return nil, fmt.Errorf("failed to send CTF → Exchange approval transaction: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-	}
-
-	if !info.CTFApprovedNegRiskAdapter {
-		setApprovalData, err := ctfABI.Pack("setApprovalForAll", b.contractConfig.NegRiskAdapter, true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack setApprovalForAll data for NegRiskAdapter: %w", err)
-		}
-		txHash, err := txSender.SendEthereumTransaction(b.contractConfig.ConditionalTokens, setApprovalData, big.NewInt(0))
-		if err != nil {
-			return nil, fmt.Errorf("failed to send CTF → NegRiskAdapter approval transaction: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
-	}
-
-	if !info.CTFApprovedNegRiskExchange {
-		setApprovalData, err := ctfABI.Pack("setApprovalForAll", b.contractConfig.NegRiskExchange, true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack setApprovalForAll data for NegRiskExchange: %w", err)
-		}
-		txHash, err := txSender.SendEthereumTransaction(b.contractConfig.ConditionalTokens, setApprovalData, big.NewInt(0))
-		if err != nil {
-			return nil, fmt.Errorf("failed to send CTF → NegRiskExchange approval transaction: %w", err)
-		}
-		txHashes = append(txHashes, txHash)
+	txHashes, err := b.executor.executeBatchEOA(ctx, calls)
+	if err != nil {
+		return txHashes, err
 	}
-
-	if len(txHashes) > 0 {
-		if err := b.waitTxReceipts(txHashes, 3, 1*time.Minute); err != nil {
+	if len(txHashes) > 0 && b.executor.dryRunRecorder == nil {
+		if err := b.waitTxReceipts(b.executor.txSender, txHashes, 3, 1*time.Minute); err != nil {
 			return txHashes, err
 		}
 	}
@@ -1144,7 +1093,7 @@ func (b *ContractInterface) RedeemPositionsForEOA(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // RedeemPositionsNegRiskForEOA redeems NegRisk market positions using EOA
@@ -1158,10 +1107,24 @@ func (b *ContractInterface) RedeemPositionsNegRiskForEOA(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
-// SplitPositionForEOA splits collateral into conditional tokens for an EOA wallet
+// resolveCollateralBalance queries the on-chain USDC.e balance for addr.
+// Used to resolve nil amount (split full balance), so an empty balance is an error.
+func (b *ContractInterface) resolveCollateralBalance(ctx context.Context, addr common.Address) (*big.Int, error) {
+	balance, err := b.collateralContract.BalanceOf(&bind.CallOpts{Context: ctx}, addr)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Sign() == 0 {
+		return nil, fmt.Errorf("%s holds no USDC.e to split", addr.Hex())
+	}
+	return balance, nil
+}
+
+// SplitPositionForEOA splits collateral into conditional tokens for an EOA wallet.
+// If amount is nil, splits the wallet's entire collateral balance.
 func (b *ContractInterface) SplitPositionForEOA(
 	ctx context.Context,
 	eoaSigner signer.EOATradingSigner,
@@ -1169,14 +1132,25 @@ func (b *ContractInterface) SplitPositionForEOA(
 	partition []*big.Int,
 	amount *big.Int,
 ) (common.Hash, error) {
+	if amount == nil {
+		owner, err := b.executor.eoaAddress(eoaSigner)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		amount, err = b.resolveCollateralBalance(ctx, owner)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve full balance for split: %w", err)
+		}
+	}
 	call, err := buildSplitPositionCall(b.contractConfig.ConditionalTokens, b.contractConfig.Collateral, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
-// MergePositionsForEOA merges conditional tokens back into collateral for an EOA wallet
+// MergePositionsForEOA merges conditional tokens back into collateral for an EOA wallet.
+// If amount is nil, merges every complete set of partition the wallet holds.
 func (b *ContractInterface) MergePositionsForEOA(
 	ctx context.Context,
 	eoaSigner signer.EOATradingSigner,
@@ -1184,11 +1158,21 @@ func (b *ContractInterface) MergePositionsForEOA(
 	partition []*big.Int,
 	amount *big.Int,
 ) (common.Hash, error) {
+	if amount == nil {
+		owner, err := b.executor.eoaAddress(eoaSigner)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		amount, err = b.portfolio.completeSets(ctx, owner, b.contractConfig.Collateral, conditionId, partition)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve complete sets for merge: %w", err)
+		}
+	}
 	call, err := buildMergePositionsCall(b.contractConfig.ConditionalTokens, b.contractConfig.Collateral, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // RedeemPositionsForSafe redeems conditional tokens for a resolved market using Safe
@@ -1203,7 +1187,7 @@ func (b *ContractInterface) RedeemPositionsForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // RedeemPositionsNegRiskForSafe redeems NegRisk market positions using Safe
@@ -1218,10 +1202,11 @@ func (b *ContractInterface) RedeemPositionsNegRiskForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
-// SplitPositionForSafe splits collateral into conditional tokens for a Safe wallet
+// SplitPositionForSafe splits collateral into conditional tokens for a Safe wallet.
+// If amount is nil, splits the Safe's entire collateral balance.
 func (b *ContractInterface) SplitPositionForSafe(
 	ctx context.Context,
 	safeSigner signer.SafeTradingSigner,
@@ -1230,14 +1215,25 @@ func (b *ContractInterface) SplitPositionForSafe(
 	partition []*big.Int,
 	amount *big.Int,
 ) (common.Hash, error) {
+	if amount == nil {
+		safeAddr, err := b.executor.getSafeAddr(safeSigner.GetAddress())
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get safe address for balance: %w", err)
+		}
+		amount, err = b.resolveCollateralBalance(ctx, safeAddr)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve full balance for split: %w", err)
+		}
+	}
 	call, err := buildSplitPositionCall(b.contractConfig.ConditionalTokens, b.contractConfig.Collateral, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
-// MergePositionsForSafe merges conditional tokens back into collateral for a Safe wallet
+// MergePositionsForSafe merges conditional tokens back into collateral for a Safe wallet.
+// If amount is nil, merges every complete set of partition the Safe holds.
 func (b *ContractInterface) MergePositionsForSafe(
 	ctx context.Context,
 	safeSigner signer.SafeTradingSigner,
@@ -1246,42 +1242,75 @@ func (b *ContractInterface) MergePositionsForSafe(
 	partition []*big.Int,
 	amount *big.Int,
 ) (common.Hash, error) {
+	if amount == nil {
+		safeAddr, err := b.executor.getSafeAddr(safeSigner.GetAddress())
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get safe address for balance: %w", err)
+		}
+		amount, err = b.portfolio.completeSets(ctx, safeAddr, b.contractConfig.Collateral, conditionId, partition)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve complete sets for merge: %w", err)
+		}
+	}
 	call, err := buildMergePositionsCall(b.contractConfig.ConditionalTokens, b.contractConfig.Collateral, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
-// SplitPositionNegRiskForEOA splits NegRisk market positions using EOA
+// SplitPositionNegRiskForEOA splits NegRisk market positions using EOA.
+// If amount is nil, splits the wallet's entire collateral balance.
 func (b *ContractInterface) SplitPositionNegRiskForEOA(
 	ctx context.Context,
 	eoaSigner signer.EOATradingSigner,
 	conditionId [32]byte,
 	amount *big.Int,
 ) (common.Hash, error) {
+	if amount == nil {
+		owner, err := b.executor.eoaAddress(eoaSigner)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		amount, err = b.resolveCollateralBalance(ctx, owner)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve full balance for split: %w", err)
+		}
+	}
 	call, err := buildSplitNegRiskCall(b.contractConfig.NegRiskAdapter, conditionId, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
-// MergePositionsNegRiskForEOA merges NegRisk market positions using EOA
+// MergePositionsNegRiskForEOA merges NegRisk market positions using EOA.
+// If amount is nil, merges every YES/NO pair the wallet holds.
 func (b *ContractInterface) MergePositionsNegRiskForEOA(
 	ctx context.Context,
 	eoaSigner signer.EOATradingSigner,
 	conditionId [32]byte,
 	amount *big.Int,
 ) (common.Hash, error) {
+	if amount == nil {
+		owner, err := b.executor.eoaAddress(eoaSigner)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		amount, err = b.portfolio.completeSets(ctx, owner, b.contractConfig.NegRiskWrappedCollateral, conditionId, FullPartition(2))
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve complete sets for merge: %w", err)
+		}
+	}
 	call, err := buildMergeNegRiskCall(b.contractConfig.NegRiskAdapter, conditionId, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
-// SplitPositionNegRiskForSafe splits NegRisk market positions using Safe
+// SplitPositionNegRiskForSafe splits NegRisk market positions using Safe.
+// If amount is nil, splits the Safe's entire collateral balance.
 func (b *ContractInterface) SplitPositionNegRiskForSafe(
 	ctx context.Context,
 	safeSigner signer.SafeTradingSigner,
@@ -1289,14 +1318,25 @@ func (b *ContractInterface) SplitPositionNegRiskForSafe(
 	conditionId [32]byte,
 	amount *big.Int,
 ) (common.Hash, error) {
+	if amount == nil {
+		safeAddr, err := b.executor.getSafeAddr(safeSigner.GetAddress())
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get safe address for balance: %w", err)
+		}
+		amount, err = b.resolveCollateralBalance(ctx, safeAddr)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve full balance for split: %w", err)
+		}
+	}
 	call, err := buildSplitNegRiskCall(b.contractConfig.NegRiskAdapter, conditionId, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
-// MergePositionsNegRiskForSafe merges NegRisk market positions using Safe
+// MergePositionsNegRiskForSafe merges NegRisk market positions using Safe.
+// If amount is nil, merges every YES/NO pair the Safe holds.
 func (b *ContractInterface) MergePositionsNegRiskForSafe(
 	ctx context.Context,
 	safeSigner signer.SafeTradingSigner,
@@ -1304,11 +1344,21 @@ func (b *ContractInterface) MergePositionsNegRiskForSafe(
 	conditionId [32]byte,
 	amount *big.Int,
 ) (common.Hash, error) {
+	if amount == nil {
+		safeAddr, err := b.executor.getSafeAddr(safeSigner.GetAddress())
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get safe address for balance: %w", err)
+		}
+		amount, err = b.portfolio.completeSets(ctx, safeAddr, b.contractConfig.NegRiskWrappedCollateral, conditionId, FullPartition(2))
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve complete sets for merge: %w", err)
+		}
+	}
 	call, err := buildMergeNegRiskCall(b.contractConfig.NegRiskAdapter, conditionId, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // --- V2 Extension Methods (backward-compatible) ---
@@ -1392,7 +1442,7 @@ func (b *ContractInterface) SplitPositionWithCollateralForEOA(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // MergePositionsWithCollateralForEOA merges using the specified collateral type
@@ -1408,7 +1458,7 @@ func (b *ContractInterface) MergePositionsWithCollateralForEOA(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // RedeemPositionsWithCollateralForEOA redeems using the specified collateral type
@@ -1423,7 +1473,7 @@ func (b *ContractInterface) RedeemPositionsWithCollateralForEOA(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // SplitPositionNegRiskWithCollateralForEOA splits NegRisk using the specified collateral type
@@ -1439,7 +1489,7 @@ func (b *ContractInterface) SplitPositionNegRiskWithCollateralForEOA(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // MergePositionsNegRiskWithCollateralForEOA merges NegRisk using the specified collateral type
@@ -1455,7 +1505,7 @@ func (b *ContractInterface) MergePositionsNegRiskWithCollateralForEOA(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // RedeemPositionsNegRiskWithCollateralForEOA redeems NegRisk using the specified collateral type
@@ -1470,7 +1520,7 @@ func (b *ContractInterface) RedeemPositionsNegRiskWithCollateralForEOA(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // SplitPositionWithCollateralForSafe splits using the specified collateral type via Safe
@@ -1487,7 +1537,7 @@ func (b *ContractInterface) SplitPositionWithCollateralForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // MergePositionsWithCollateralForSafe merges using the specified collateral type via Safe
@@ -1504,7 +1554,7 @@ func (b *ContractInterface) MergePositionsWithCollateralForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // RedeemPositionsWithCollateralForSafe redeems using the specified collateral type via Safe
@@ -1520,7 +1570,7 @@ func (b *ContractInterface) RedeemPositionsWithCollateralForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // SplitPositionNegRiskWithCollateralForSafe splits NegRisk using the specified collateral type via Safe
@@ -1537,7 +1587,7 @@ func (b *ContractInterface) SplitPositionNegRiskWithCollateralForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // MergePositionsNegRiskWithCollateralForSafe merges NegRisk using the specified collateral type via Safe
@@ -1554,7 +1604,7 @@ func (b *ContractInterface) MergePositionsNegRiskWithCollateralForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // RedeemPositionsNegRiskWithCollateralForSafe redeems NegRisk using the specified collateral type via Safe
@@ -1570,7 +1620,7 @@ func (b *ContractInterface) RedeemPositionsNegRiskWithCollateralForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // --- pUSD Convenience Methods ---
@@ -1632,12 +1682,15 @@ func (b *ContractInterface) WrapCollateralForEOA(
 	asset common.Address,
 	amount *big.Int,
 ) (common.Hash, error) {
-	to := eoaSigner.GetAddress()
+	to, err := b.executor.eoaAddress(eoaSigner)
+	if err != nil {
+		return common.Hash{}, err
+	}
 	call, err := buildWrapCall(b.contractConfig.CollateralOnramp, asset, to, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // UnwrapCollateralForEOA unwraps pUSD back to an asset (USDC/USDC.e) via CollateralOfframp
@@ -1647,12 +1700,15 @@ func (b *ContractInterface) UnwrapCollateralForEOA(
 	asset common.Address,
 	amount *big.Int,
 ) (common.Hash, error) {
-	to := eoaSigner.GetAddress()
+	to, err := b.executor.eoaAddress(eoaSigner)
+	if err != nil {
+		return common.Hash{}, err
+	}
 	call, err := buildUnwrapCall(b.contractConfig.CollateralOfframp, asset, to, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeEOA(call)
+	return b.executor.executeEOA(ctx, call)
 }
 
 // WrapCollateralForSafe wraps an asset into pUSD via Safe
@@ -1671,7 +1727,7 @@ func (b *ContractInterface) WrapCollateralForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // UnwrapCollateralForSafe unwraps pUSD back to an asset via Safe
@@ -1690,7 +1746,7 @@ func (b *ContractInterface) UnwrapCollateralForSafe(
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return b.executor.executeSafe(safeSigner, chainID, call)
+	return b.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // --- EnableTradingV2 ---
@@ -1731,7 +1787,7 @@ func (b *ContractInterface) EnableTradingV2ForEOA(
 		calls = append(calls, call)
 	}
 
-	return b.executor.executeBatchEOA(calls)
+	return b.executor.executeBatchEOA(ctx, calls)
 }
 
 // EnableTradingV2ForSafe approves all V2 contracts for trading via Safe
@@ -1769,7 +1825,7 @@ func (b *ContractInterface) EnableTradingV2ForSafe(
 		calls = append(calls, call)
 	}
 
-	return b.executor.executeBatchSafe(safeSigner, chainID, calls)
+	return b.executor.executeBatchSafe(ctx, safeSigner, chainID, calls)
 }
 
 // BuildSafeTransactionTypedData builds the typed data for Gnosis Safe transaction
@@ -3,6 +3,7 @@ package polymarketcontracts
 import (
 	"bytes"
 	"context"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -187,6 +188,34 @@ func TestWrapCollateralForEOA(t *testing.T) {
 	}
 }
 
+func TestEOAMethods_RejectSignerOtherThanSender(t *testing.T) {
+	mock := &addrSender{addr: common.HexToAddress("0x1")}
+	eoaSigner := &mockEOASigner{addr: common.HexToAddress("0x2")}
+	ci := &ContractInterface{
+		contractConfig: MATIC_CONTRACTS,
+		executor:       &txExecutor{txSender: mock},
+	}
+	ctx := context.Background()
+
+	if _, err := ci.WrapCollateralForEOA(ctx, eoaSigner, MATIC_CONTRACTS.Collateral, big.NewInt(1)); !errors.Is(err, ErrEOASignerMismatch) {
+		t.Errorf("WrapCollateralForEOA: got %v, want ErrEOASignerMismatch", err)
+	}
+	if _, err := ci.TransferPositionsForEOA(ctx, eoaSigner, common.HexToAddress("0x3"), []*big.Int{big.NewInt(1)}, []*big.Int{big.NewInt(1)}); !errors.Is(err, ErrEOASignerMismatch) {
+		t.Errorf("TransferPositionsForEOA: got %v, want ErrEOASignerMismatch", err)
+	}
+	if _, err := ci.RedeemResolvedForEOA(ctx, eoaSigner, nil); !errors.Is(err, ErrEOASignerMismatch) {
+		t.Errorf("RedeemResolvedForEOA: got %v, want ErrEOASignerMismatch", err)
+	}
+	if mock.calls != 0 {
+		t.Errorf("expected nothing sent, got %d transaction(s)", mock.calls)
+	}
+
+	// The sender's own account is accepted
+	if _, err := ci.WrapCollateralForEOA(ctx, &mockEOASigner{addr: mock.addr}, MATIC_CONTRACTS.Collateral, big.NewInt(1)); err != nil {
+		t.Errorf("WrapCollateralForEOA: %v", err)
+	}
+}
+
 func TestUnwrapCollateralForEOA(t *testing.T) {
 	mock := &mockTransactionSender{retHash: common.HexToHash("0xUNWRAP")}
 	eoaAddr := common.HexToAddress("0xEOA2")
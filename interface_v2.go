@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ivanzzeth/ethclient"
 	"github.com/ivanzzeth/ethsig"
@@ -28,6 +29,10 @@ import (
 	permissioned_ramp "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/permissioned-ramp"
 	safeproxyfactory "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/safe-proxy-factory"
 	"github.com/ivanzzeth/ethsig/eip712"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/journal"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/offline"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/retry"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
 	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
 	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
 )
@@ -83,6 +88,7 @@ type ContractInterfaceV2 struct {
 	ctfCollateralAdapter        *ctf_collateral_adapter.CtfCollateralAdapter
 	negRiskCtfCollateralAdapter *neg_risk_ctf_collateral_adapter.NegRiskCtfCollateralAdapter
 	permissionedRamp            *permissioned_ramp.PermissionedRamp
+	portfolio                   *portfolioReader
 
 	// Safe support (migrated from V1 for backward compatibility)
 	safeProxyFactory *safeproxyfactory.SafeProxyFactory // SafeProxyFactory contract
@@ -96,6 +102,10 @@ type ContractInterfaceV2 struct {
 	// Optional auto-routing fields (set via functional options)
 	signatureType     SignatureType
 	safeTradingSigner signer.SafeTradingSigner
+	dryRunRecorder    *DryRunRecorder
+	gasPricer         sender.GasPricer
+	journal           journal.Journal
+	retryPolicy       retry.Policy
 }
 
 // ContractInterfaceV2Option configures optional fields on ContractInterfaceV2.
@@ -121,6 +131,43 @@ func WithV2SafeSigner(s signer.SafeTradingSigner) ContractInterfaceV2Option {
 	}
 }
 
+// WithV2DryRun simulates every call built by the interface with eth_call from the acting account
+// (EOA, or the Safe through simulateAndRevert) and records the outcome in recorder instead of sending it.
+// This includes EnableTrading approvals and Safe deployment.
+// Methods return a zero hash, or an error wrapping ErrDryRunReverted if the call would revert.
+func WithV2DryRun(recorder *DryRunRecorder) ContractInterfaceV2Option {
+	return func(v *ContractInterfaceV2) {
+		v.dryRunRecorder = recorder
+	}
+}
+
+// WithV2GasPricer makes the interface choose transaction fees with pricer instead of leaving them to the sender.
+// Redeems are priced with sender.UrgencyLow, other calls with sender.UrgencyNormal.
+func WithV2GasPricer(pricer sender.GasPricer) ContractInterfaceV2Option {
+	return func(v *ContractInterfaceV2) {
+		v.gasPricer = pricer
+	}
+}
+
+// WithV2Journal records every call built by the interface, the transaction sent for it and its state in j.
+// Calls made with a context from journal.WithIdempotencyKey are not sent again while an entry for the same
+// call and key is pending or mined. Entries are marked mined or failed when the interface waits for their
+// receipt (EnableTrading, Safe batches); others stay sent until journal.Recover reconciles them.
+// Call journal.Recover on startup to reconcile what a previous process left pending.
+func WithV2Journal(j journal.Journal) ContractInterfaceV2Option {
+	return func(v *ContractInterfaceV2) {
+		v.journal = j
+	}
+}
+
+// WithV2RetryPolicy sets how transient RPC errors (timeouts, rate limits, dropped connections) of contract
+// reads, gas estimation and gas pricing are retried. Reverts are never retried. Use retry.NoRetry to disable retries.
+func WithV2RetryPolicy(policy retry.Policy) ContractInterfaceV2Option {
+	return func(v *ContractInterfaceV2) {
+		v.retryPolicy = policy
+	}
+}
+
 // NewContractInterfaceV2 creates a V2 interface. All V2 contract addresses in config must be non-zero.
 // V2 is fully self-contained and does not depend on V1 ContractInterface.
 func NewContractInterfaceV2(
@@ -133,98 +180,97 @@ func NewContractInterfaceV2(
 	if config.ExchangeV2 == (common.Address{}) {
 		return nil, fmt.Errorf("V2 not configured: ExchangeV2 address is zero")
 	}
+	registerRevertAddresses(config)
 
-	exchV2, err := exchange_v2.NewExchangeV2(config.ExchangeV2, client)
+	v2 := &ContractInterfaceV2{
+		chainID: chainID,
+		config:  config,
+		client:  client,
+
+		tokenStatus: make(map[common.Address]*TokenStatus),
+		statusTTL:   5 * time.Minute,
+		retryPolicy: retry.DefaultPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(v2)
+	}
+
+	// Contract reads retry transient errors
+	backend := retry.NewBackend(client, v2.retryPolicy)
+
+	var err error
+	v2.exchangeV2, err = exchange_v2.NewExchangeV2(config.ExchangeV2, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ExchangeV2 binding: %w", err)
 	}
-	nrV2, err := neg_risk_v2.NewNegRiskV2(config.NegRiskExchangeV2, client)
+	v2.negRiskExchangeV2, err = neg_risk_v2.NewNegRiskV2(config.NegRiskExchangeV2, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create NegRiskV2 binding: %w", err)
 	}
-	ct, err := collateral_token.NewCollateralToken(config.CollateralToken, client)
+	v2.collateralToken, err = collateral_token.NewCollateralToken(config.CollateralToken, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CollateralToken binding: %w", err)
 	}
-	usdce, err := erc20.NewErc20(config.Collateral, client)
+	v2.usdce, err = erc20.NewErc20(config.Collateral, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create USDC.e binding: %w", err)
 	}
-	var usdc *erc20.Erc20
 	if config.USDC != (common.Address{}) {
-		usdc, err = erc20.NewErc20(config.USDC, client)
+		v2.usdc, err = erc20.NewErc20(config.USDC, backend)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create USDC binding: %w", err)
 		}
 	}
-	ctf, err := conditional_tokens.NewConditionalTokens(config.ConditionalTokens, client)
+	v2.conditionalTokens, err = conditional_tokens.NewConditionalTokens(config.ConditionalTokens, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ConditionalTokens binding: %w", err)
 	}
-	onramp, err := collateral_onramp.NewCollateralOnramp(config.CollateralOnramp, client)
+	v2.collateralOnramp, err = collateral_onramp.NewCollateralOnramp(config.CollateralOnramp, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CollateralOnramp binding: %w", err)
 	}
-	offramp, err := collateral_offramp.NewCollateralOfframp(config.CollateralOfframp, client)
+	v2.collateralOfframp, err = collateral_offramp.NewCollateralOfframp(config.CollateralOfframp, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CollateralOfframp binding: %w", err)
 	}
-	ctfAdapter, err := ctf_collateral_adapter.NewCtfCollateralAdapter(config.CtfCollateralAdapter, client)
+	v2.ctfCollateralAdapter, err = ctf_collateral_adapter.NewCtfCollateralAdapter(config.CtfCollateralAdapter, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CtfCollateralAdapter binding: %w", err)
 	}
-	nrCtfAdapter, err := neg_risk_ctf_collateral_adapter.NewNegRiskCtfCollateralAdapter(config.NegRiskCtfCollateralAdapter, client)
+	v2.negRiskCtfCollateralAdapter, err = neg_risk_ctf_collateral_adapter.NewNegRiskCtfCollateralAdapter(config.NegRiskCtfCollateralAdapter, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create NegRiskCtfCollateralAdapter binding: %w", err)
 	}
-	pr, err := permissioned_ramp.NewPermissionedRamp(config.PermissionedRamp, client)
+	v2.permissionedRamp, err = permissioned_ramp.NewPermissionedRamp(config.PermissionedRamp, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create PermissionedRamp binding: %w", err)
 	}
 
+	v2.portfolio, err = newPortfolioReader(config, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create portfolio reader: %w", err)
+	}
+
 	// Initialize SafeProxyFactory for Safe address computation (migrated from V1)
-	var safeFactory *safeproxyfactory.SafeProxyFactory
 	if config.SafeProxyFactory != (common.Address{}) {
-		safeFactory, err = safeproxyfactory.NewSafeProxyFactory(config.SafeProxyFactory, client)
+		v2.safeProxyFactory, err = safeproxyfactory.NewSafeProxyFactory(config.SafeProxyFactory, backend)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create SafeProxyFactory binding: %w", err)
 		}
 	}
 
-	// Create V2 instance first (without executor)
-	v2 := &ContractInterfaceV2{
-		chainID: chainID,
-		config:  config,
-		client:  client,
-
-		exchangeV2:                  exchV2,
-		negRiskExchangeV2:           nrV2,
-		collateralToken:             ct,
-		usdc:                        usdc,
-		usdce:                       usdce,
-		conditionalTokens:           ctf,
-		collateralOnramp:            onramp,
-		collateralOfframp:           offramp,
-		ctfCollateralAdapter:        ctfAdapter,
-		negRiskCtfCollateralAdapter: nrCtfAdapter,
-		permissionedRamp:            pr,
-
-		safeProxyFactory: safeFactory,
-
-		tokenStatus: make(map[common.Address]*TokenStatus),
-		statusTTL:   5 * time.Minute,
-	}
-
-	for _, opt := range opts {
-		opt(v2)
-	}
-
 	// Create executor using v2's own methods (no dependency on V1)
 	v2.executor = &txExecutor{
 		client:      client,
 		txSender:    txSender,
 		getSafeAddr: v2.GetSafeAddress,
-		execSafeTx:  v2.ExecuteTransactionBySafeAndSingleSigner,
+		execSafeTx:  v2.ExecuteTransactionBySafeAndSingleSignerWithContext,
+
+		dryRunRecorder: v2.dryRunRecorder,
+		gasPricer:      v2.gasPricer,
+		journal:        v2.journal,
+		retryPolicy:    v2.retryPolicy,
 	}
 
 	// Initial token status check (non-blocking, just log warnings)
@@ -377,7 +423,7 @@ func (v *ContractInterfaceV2) EnableTradingForEOA(ctx context.Context, opts ...E
 	if err != nil {
 		return nil, err
 	}
-	return v.executor.executeBatchEOA(calls)
+	return v.executor.executeBatchEOA(ctx, calls)
 }
 
 // EnableTradingForSafe approves pUSD to adapters/offramp and sets CTF approvals for V2 exchanges/adapters via Safe.
@@ -390,7 +436,7 @@ func (v *ContractInterfaceV2) EnableTradingForSafe(ctx context.Context, safeSign
 	if err != nil {
 		return nil, err
 	}
-	return v.executor.executeBatchSafe(safeSigner, chainID, calls)
+	return v.executor.executeBatchSafe(ctx, safeSigner, chainID, calls)
 }
 
 func (v *ContractInterfaceV2) enableTradingCalls(ctx context.Context, address common.Address, opts ...EnableTradingOption) ([]contractCall, error) {
@@ -573,6 +619,18 @@ func (v *ContractInterfaceV2) resolvePUSDBalance(ctx context.Context, addr commo
 	return v.collateralToken.BalanceOf(opts, addr)
 }
 
+// resolvePUSDSplitAmount resolves a nil split amount to addr's entire pUSD balance, which must not be empty.
+func (v *ContractInterfaceV2) resolvePUSDSplitAmount(ctx context.Context, addr common.Address) (*big.Int, error) {
+	balance, err := v.resolvePUSDBalance(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Sign() == 0 {
+		return nil, fmt.Errorf("%s holds no pUSD to split", addr.Hex())
+	}
+	return balance, nil
+}
+
 // WrapToPUSDForEOA wraps USDC/USDC.e to pUSD via the CollateralOnramp.
 // If amount is nil, wraps the sender's entire asset balance.
 func (v *ContractInterfaceV2) WrapToPUSDForEOA(ctx context.Context, asset common.Address, to common.Address, amount *big.Int) (common.Hash, error) {
@@ -599,7 +657,7 @@ func (v *ContractInterfaceV2) WrapToPUSDForEOA(ctx context.Context, asset common
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeEOA(call)
+	return v.executor.executeEOA(ctx, call)
 }
 
 // WrapToPUSDForSafe wraps USDC/USDC.e to pUSD via Safe.
@@ -628,7 +686,7 @@ func (v *ContractInterfaceV2) WrapToPUSDForSafe(ctx context.Context, safeSigner
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeSafe(safeSigner, chainID, call)
+	return v.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // UnwrapFromPUSDForEOA unwraps pUSD to USDC/USDC.e via the CollateralOfframp.
@@ -657,7 +715,7 @@ func (v *ContractInterfaceV2) UnwrapFromPUSDForEOA(ctx context.Context, asset co
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeEOA(call)
+	return v.executor.executeEOA(ctx, call)
 }
 
 // UnwrapFromPUSDForSafe unwraps pUSD to USDC/USDC.e via Safe.
@@ -686,45 +744,89 @@ func (v *ContractInterfaceV2) UnwrapFromPUSDForSafe(ctx context.Context, safeSig
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeSafe(safeSigner, chainID, call)
+	return v.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // --- Split / Merge / Redeem (regular markets via CtfCollateralAdapter) ---
 
 // SplitPositionForEOA splits pUSD into conditional tokens via the CtfCollateralAdapter.
+// If amount is nil, splits the sender's entire pUSD balance.
 func (v *ContractInterfaceV2) SplitPositionForEOA(ctx context.Context, conditionId [32]byte, partition []*big.Int, amount *big.Int) (common.Hash, error) {
+	if amount == nil {
+		addr, err := v.getEOAAddress()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		amount, err = v.resolvePUSDSplitAmount(ctx, addr)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve full balance for split: %w", err)
+		}
+	}
 	call, err := buildAdapterSplitCall(v.config.CtfCollateralAdapter, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeEOA(call)
+	return v.executor.executeEOA(ctx, call)
 }
 
 // SplitPositionForSafe splits pUSD into conditional tokens via Safe.
+// If amount is nil, splits the Safe's entire pUSD balance.
 func (v *ContractInterfaceV2) SplitPositionForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, conditionId [32]byte, partition []*big.Int, amount *big.Int) (common.Hash, error) {
+	if amount == nil {
+		safeAddr, err := v.GetSafeAddress(safeSigner.GetAddress())
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get safe address for balance: %w", err)
+		}
+		amount, err = v.resolvePUSDSplitAmount(ctx, safeAddr)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve full balance for split: %w", err)
+		}
+	}
 	call, err := buildAdapterSplitCall(v.config.CtfCollateralAdapter, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeSafe(safeSigner, chainID, call)
+	return v.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // MergePositionsForEOA merges conditional tokens back into pUSD via the CtfCollateralAdapter.
+// If amount is nil, merges every complete set of partition the sender holds.
 func (v *ContractInterfaceV2) MergePositionsForEOA(ctx context.Context, conditionId [32]byte, partition []*big.Int, amount *big.Int) (common.Hash, error) {
+	if amount == nil {
+		addr, err := v.getEOAAddress()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		amount, err = v.portfolio.completeSets(ctx, addr, v.config.Collateral, conditionId, partition)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve complete sets for merge: %w", err)
+		}
+	}
 	call, err := buildAdapterMergeCall(v.config.CtfCollateralAdapter, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeEOA(call)
+	return v.executor.executeEOA(ctx, call)
 }
 
 // MergePositionsForSafe merges conditional tokens back into pUSD via Safe.
+// If amount is nil, merges every complete set of partition the Safe holds.
 func (v *ContractInterfaceV2) MergePositionsForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, conditionId [32]byte, partition []*big.Int, amount *big.Int) (common.Hash, error) {
+	if amount == nil {
+		safeAddr, err := v.GetSafeAddress(safeSigner.GetAddress())
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get safe address for balance: %w", err)
+		}
+		amount, err = v.portfolio.completeSets(ctx, safeAddr, v.config.Collateral, conditionId, partition)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve complete sets for merge: %w", err)
+		}
+	}
 	call, err := buildAdapterMergeCall(v.config.CtfCollateralAdapter, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeSafe(safeSigner, chainID, call)
+	return v.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // RedeemPositionsForEOA redeems conditional tokens for pUSD via the CtfCollateralAdapter.
@@ -733,7 +835,7 @@ func (v *ContractInterfaceV2) RedeemPositionsForEOA(ctx context.Context, conditi
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeEOA(call)
+	return v.executor.executeEOA(ctx, call)
 }
 
 // RedeemPositionsForSafe redeems conditional tokens for pUSD via Safe.
@@ -742,45 +844,89 @@ func (v *ContractInterfaceV2) RedeemPositionsForSafe(ctx context.Context, safeSi
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeSafe(safeSigner, chainID, call)
+	return v.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // --- Split / Merge / Redeem (neg-risk markets via NegRiskCtfCollateralAdapter) ---
 
 // SplitPositionNegRiskForEOA splits pUSD into neg-risk conditional tokens via the NegRiskCtfCollateralAdapter.
+// If amount is nil, splits the sender's entire pUSD balance.
 func (v *ContractInterfaceV2) SplitPositionNegRiskForEOA(ctx context.Context, conditionId [32]byte, partition []*big.Int, amount *big.Int) (common.Hash, error) {
+	if amount == nil {
+		addr, err := v.getEOAAddress()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		amount, err = v.resolvePUSDSplitAmount(ctx, addr)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve full balance for split: %w", err)
+		}
+	}
 	call, err := buildNegRiskAdapterSplitCall(v.config.NegRiskCtfCollateralAdapter, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeEOA(call)
+	return v.executor.executeEOA(ctx, call)
 }
 
 // SplitPositionNegRiskForSafe splits pUSD into neg-risk conditional tokens via Safe.
+// If amount is nil, splits the Safe's entire pUSD balance.
 func (v *ContractInterfaceV2) SplitPositionNegRiskForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, conditionId [32]byte, partition []*big.Int, amount *big.Int) (common.Hash, error) {
+	if amount == nil {
+		safeAddr, err := v.GetSafeAddress(safeSigner.GetAddress())
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get safe address for balance: %w", err)
+		}
+		amount, err = v.resolvePUSDSplitAmount(ctx, safeAddr)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve full balance for split: %w", err)
+		}
+	}
 	call, err := buildNegRiskAdapterSplitCall(v.config.NegRiskCtfCollateralAdapter, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeSafe(safeSigner, chainID, call)
+	return v.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // MergePositionsNegRiskForEOA merges neg-risk conditional tokens back into pUSD.
+// If amount is nil, merges every complete set of partition the sender holds.
 func (v *ContractInterfaceV2) MergePositionsNegRiskForEOA(ctx context.Context, conditionId [32]byte, partition []*big.Int, amount *big.Int) (common.Hash, error) {
+	if amount == nil {
+		addr, err := v.getEOAAddress()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		amount, err = v.portfolio.completeSets(ctx, addr, v.config.NegRiskWrappedCollateral, conditionId, partition)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve complete sets for merge: %w", err)
+		}
+	}
 	call, err := buildNegRiskAdapterMergeCall(v.config.NegRiskCtfCollateralAdapter, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeEOA(call)
+	return v.executor.executeEOA(ctx, call)
 }
 
 // MergePositionsNegRiskForSafe merges neg-risk conditional tokens back into pUSD via Safe.
+// If amount is nil, merges every complete set of partition the Safe holds.
 func (v *ContractInterfaceV2) MergePositionsNegRiskForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, conditionId [32]byte, partition []*big.Int, amount *big.Int) (common.Hash, error) {
+	if amount == nil {
+		safeAddr, err := v.GetSafeAddress(safeSigner.GetAddress())
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get safe address for balance: %w", err)
+		}
+		amount, err = v.portfolio.completeSets(ctx, safeAddr, v.config.NegRiskWrappedCollateral, conditionId, partition)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to resolve complete sets for merge: %w", err)
+		}
+	}
 	call, err := buildNegRiskAdapterMergeCall(v.config.NegRiskCtfCollateralAdapter, conditionId, partition, amount)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeSafe(safeSigner, chainID, call)
+	return v.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // RedeemPositionsNegRiskForEOA redeems neg-risk conditional tokens for pUSD.
@@ -789,7 +935,7 @@ func (v *ContractInterfaceV2) RedeemPositionsNegRiskForEOA(ctx context.Context,
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeEOA(call)
+	return v.executor.executeEOA(ctx, call)
 }
 
 // RedeemPositionsNegRiskForSafe redeems neg-risk conditional tokens for pUSD via Safe.
@@ -798,7 +944,7 @@ func (v *ContractInterfaceV2) RedeemPositionsNegRiskForSafe(ctx context.Context,
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return v.executor.executeSafe(safeSigner, chainID, call)
+	return v.executor.executeSafe(ctx, safeSigner, chainID, call)
 }
 
 // --- Auto-routing convenience methods ---
@@ -827,8 +973,14 @@ func (v *ContractInterfaceV2) EnableTrading(ctx context.Context, opts ...EnableT
 	}
 }
 
-func (v *ContractInterfaceV2) Split(ctx context.Context, conditionId [32]byte, amount *big.Int) (common.Hash, error) {
-	partition := []*big.Int{big.NewInt(1), big.NewInt(2)}
+// Split splits collateral into both outcomes of a binary condition.
+// The CtfCollateralAdapter ignores partitions, so WithPartition only accepts [1, 2] and other conditions
+// fail with ErrAdapterNotBinary. A nil amount splits the entire collateral balance.
+func (v *ContractInterfaceV2) Split(ctx context.Context, conditionId [32]byte, amount *big.Int, opts ...PositionOption) (common.Hash, error) {
+	partition, err := resolvePartition(ctx, v.conditionalTokens, conditionId, true, opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
 	switch v.signatureType {
 	case SignatureTypePolyGnosisSafe:
 		s, err := v.getSafeTradingSignerOrErr()
@@ -843,8 +995,14 @@ func (v *ContractInterfaceV2) Split(ctx context.Context, conditionId [32]byte, a
 	}
 }
 
-func (v *ContractInterfaceV2) Merge(ctx context.Context, conditionId [32]byte, amount *big.Int) (common.Hash, error) {
-	partition := []*big.Int{big.NewInt(1), big.NewInt(2)}
+// Merge merges both outcomes of a binary condition back into collateral.
+// Like Split, it fails with ErrAdapterNotBinary for other conditions and partitions.
+// A nil amount merges every complete set held.
+func (v *ContractInterfaceV2) Merge(ctx context.Context, conditionId [32]byte, amount *big.Int, opts ...PositionOption) (common.Hash, error) {
+	partition, err := resolvePartition(ctx, v.conditionalTokens, conditionId, true, opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
 	switch v.signatureType {
 	case SignatureTypePolyGnosisSafe:
 		s, err := v.getSafeTradingSignerOrErr()
@@ -859,8 +1017,13 @@ func (v *ContractInterfaceV2) Merge(ctx context.Context, conditionId [32]byte, a
 	}
 }
 
-func (v *ContractInterfaceV2) Redeem(ctx context.Context, conditionId [32]byte) (common.Hash, error) {
-	indexSets := []*big.Int{big.NewInt(1), big.NewInt(2)}
+// Redeem redeems both outcomes of a binary condition: the CtfCollateralAdapter ignores the index sets
+// of WithPartition. Other conditions fail with ErrAdapterNotBinary.
+func (v *ContractInterfaceV2) Redeem(ctx context.Context, conditionId [32]byte, opts ...PositionOption) (common.Hash, error) {
+	indexSets, err := resolvePartition(ctx, v.conditionalTokens, conditionId, false, opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
 	switch v.signatureType {
 	case SignatureTypePolyGnosisSafe:
 		s, err := v.getSafeTradingSignerOrErr()
@@ -1104,7 +1267,9 @@ func (v *ContractInterfaceV2) EstimateSafeTxGas(safeAddr, to common.Address, val
 
 	// EstimateGas will fail because simulateAndRevert always reverts,
 	// but we can extract the gas estimation from the error
-	gasLimit, err := v.client.EstimateGas(context.Background(), msg)
+	gasLimit, err := retry.DoValue(context.Background(), v.retryPolicy, func() (uint64, error) {
+		return v.client.EstimateGas(context.Background(), msg)
+	})
 	if err != nil {
 		// simulateAndRevert always reverts, so we expect an error
 		// However, EstimateGas still provides a gas estimate
@@ -1119,9 +1284,11 @@ func (v *ContractInterfaceV2) EstimateSafeTxGas(safeAddr, to common.Address, val
 			Data:  targetCallData,
 		}
 
-		directGas, directErr := v.client.EstimateGas(context.Background(), directMsg)
+		directGas, directErr := retry.DoValue(context.Background(), v.retryPolicy, func() (uint64, error) {
+			return v.client.EstimateGas(context.Background(), directMsg)
+		})
 		if directErr != nil {
-			return nil, fmt.Errorf("failed to estimate gas: %w", directErr)
+			return nil, fmt.Errorf("failed to estimate gas: %w", revert.Wrap(&to, directErr))
 		}
 
 		// Add Safe overhead: approximately 15000 gas for Safe execution logic
@@ -1152,6 +1319,22 @@ func (v *ContractInterfaceV2) ExecuteTransactionBySafeAndSingleSigner(
 	data []byte,
 	operation SafeOperation,
 	safeTxGas *big.Int,
+) (common.Hash, error) {
+	return v.ExecuteTransactionBySafeAndSingleSignerWithContext(context.Background(), safeSigner, chainID, safeAddr, to, value, data, operation, safeTxGas)
+}
+
+// ExecuteTransactionBySafeAndSingleSignerWithContext is ExecuteTransactionBySafeAndSingleSigner with a context
+// and per-call options for the outer transaction sent by the Safe owner.
+func (v *ContractInterfaceV2) ExecuteTransactionBySafeAndSingleSignerWithContext(
+	ctx context.Context,
+	safeSigner signer.SafeTradingSigner,
+	chainID *big.Int,
+	safeAddr, to common.Address,
+	value *big.Int,
+	data []byte,
+	operation SafeOperation,
+	safeTxGas *big.Int,
+	opts ...sender.SendOption,
 ) (common.Hash, error) {
 	baseGas := big.NewInt(0)
 	gasPrice := big.NewInt(0)
@@ -1163,10 +1346,18 @@ func (v *ContractInterfaceV2) ExecuteTransactionBySafeAndSingleSigner(
 		return common.Hash{}, err
 	}
 
-	nonce, err := safeContract.Nonce(nil)
+	nonce, err := safeContract.Nonce(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return common.Hash{}, err
 	}
+	// An offline owner signs later: consecutive exported Safe transactions take consecutive nonces
+	exporter, exporting := offline.AsExportingSender(safeSigner)
+	if exporting {
+		nonce = exporter.Bundle().NextSafeNonce(safeAddr, nonce)
+	}
+	if err := journal.Record(ctx, func(e *journal.Entry) { e.SafeNonce = (*hexutil.Big)(nonce) }); err != nil {
+		return common.Hash{}, err
+	}
 
 	// Estimate safeTxGas if not set
 	if safeTxGas == nil || safeTxGas.Cmp(big.NewInt(0)) == 0 {
@@ -1179,9 +1370,12 @@ func (v *ContractInterfaceV2) ExecuteTransactionBySafeAndSingleSigner(
 
 	// Build typed data for signing
 	typedData := BuildSafeTransactionTypedData(chainID, safeAddr, to, value, data, operation, safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, nonce)
+	if exporting {
+		return exportSafeTx(ctx, exporter, typedData, safeAddr, to, value, data, operation, safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, nonce, opts...)
+	}
 
 	// Sign
-	signature, err := safeSigner.SignTypedData(typedData)
+	signature, err := signer.SignTypedDataWithContext(ctx, safeSigner, typedData)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to sign Safe transaction: %w", err)
 	}
@@ -1197,14 +1391,14 @@ func (v *ContractInterfaceV2) ExecuteTransactionBySafeAndSingleSigner(
 		return common.Hash{}, fmt.Errorf("failed to get GnosisSafeL2 contract: %w", err)
 	}
 
-	encodedTxData, err := safeL2.EncodeTransactionData(nil, to, value, data, uint8(operation), safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, nonce)
+	encodedTxData, err := safeL2.EncodeTransactionData(&bind.CallOpts{Context: ctx}, to, value, data, uint8(operation), safeTxGas, baseGas, gasPrice, gasToken, refundReceiver, nonce)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to encode transaction data: %w", err)
 	}
 
-	err = safeL2.CheckSignatures(nil, common.BytesToHash(safeTxHash), encodedTxData, signature)
+	err = safeL2.CheckSignatures(&bind.CallOpts{Context: ctx}, common.BytesToHash(safeTxHash), encodedTxData, signature)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("signature verification failed: %w", err)
+		return common.Hash{}, fmt.Errorf("signature verification failed: %w", revert.Wrap(&safeAddr, err))
 	}
 
 	// Execute via txSender — prefer executor's txSender, fall back to safeSigner itself
@@ -1228,7 +1422,7 @@ func (v *ContractInterfaceV2) ExecuteTransactionBySafeAndSingleSigner(
 		return common.Hash{}, fmt.Errorf("failed to pack execTransaction: %w", err)
 	}
 
-	txHash, err := txSender.SendEthereumTransaction(safeAddr, execTxData, big.NewInt(0))
+	txHash, err := sender.AsContextTransactionSender(txSender).SendEthereumTransactionWithContext(ctx, safeAddr, execTxData, big.NewInt(0), opts...)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to send Safe transaction: %w", err)
 	}
@@ -1349,11 +1543,8 @@ func (v *ContractInterfaceV2) deploySafeWithSig(chainID *big.Int, safeFactory, p
 	if txSender == nil {
 		txSender = safeSigner
 	}
-	txHash, err = txSender.SendEthereumTransaction(v.config.SafeProxyFactory, createProxyData, big.NewInt(0))
-	if err != nil {
-		return
-	}
-
+	// Sent through the executor so the deployment is journaled, and simulated instead in dry-run mode
+	txHash, err = v.executor.executeEOABy(context.Background(), txSender, contractCall{Target: v.config.SafeProxyFactory, Calldata: createProxyData, Value: big.NewInt(0)})
 	return
 }
 
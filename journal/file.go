@@ -0,0 +1,187 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FileJournal keeps entries in a JSON file. Every change rewrites the file atomically
+// (write to a temporary file, sync, rename), so the file always holds a complete journal.
+// Mined and failed entries are kept until pruned: call Prune, or open the journal WithRetention,
+// so the file does not grow without bound.
+type FileJournal struct {
+	path      string
+	retention time.Duration
+
+	mu      sync.Mutex
+	entries *entrySet
+}
+
+var (
+	_ HashIndex = (*FileJournal)(nil)
+	_ Pruner    = (*FileJournal)(nil)
+)
+
+// FileJournalOption configures optional fields on FileJournal
+type FileJournalOption func(j *FileJournal)
+
+// WithRetention drops mined and failed entries last updated more than retention ago whenever the journal is written
+func WithRetention(retention time.Duration) FileJournalOption {
+	return func(j *FileJournal) {
+		j.retention = retention
+	}
+}
+
+// OpenFileJournal opens the journal at path, creating it if it does not exist
+func OpenFileJournal(path string, opts ...FileJournalOption) (*FileJournal, error) {
+	j := &FileJournal{path: path, entries: newEntrySet()}
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	if len(raw) == 0 {
+		return j, nil
+	}
+
+	var entries []*Entry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode journal %s: %w", path, err)
+	}
+	for _, e := range entries {
+		j.entries.put(e)
+	}
+	return j, nil
+}
+
+// Put implements Journal
+func (j *FileJournal) Put(e *Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	prev, existed := j.entries.byID[e.ID]
+	j.entries.put(e.clone())
+	if err := j.flush(); err != nil {
+		if existed {
+			j.entries.put(prev)
+		} else {
+			j.entries.remove(e.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// Update implements Journal
+func (j *FileJournal) Update(id string, fn func(e *Entry)) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	prev, ok := j.entries.byID[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	updated := prev.clone()
+	fn(updated)
+	updated.UpdatedAt = time.Now().UTC()
+	j.entries.put(updated)
+	if err := j.flush(); err != nil {
+		j.entries.put(prev)
+		return err
+	}
+	return nil
+}
+
+// Get implements Journal
+func (j *FileJournal) Get(id string) (*Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e, ok := j.entries.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return e.clone(), nil
+}
+
+// GetByHash implements HashIndex
+func (j *FileJournal) GetByHash(txHash common.Hash) (*Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.entries.getByHash(txHash)
+}
+
+// List implements Journal
+func (j *FileJournal) List() ([]*Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return sortedClones(j.entries.byID), nil
+}
+
+// Prune implements Pruner
+func (j *FileJournal) Prune(olderThan time.Duration) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	pruned := j.entries.prune(time.Now().Add(-olderThan))
+	if len(pruned) == 0 {
+		return 0, nil
+	}
+	if err := j.flush(); err != nil {
+		for _, e := range pruned {
+			j.entries.put(e)
+		}
+		return 0, err
+	}
+	return len(pruned), nil
+}
+
+// flush writes all entries to the journal file, dropping those past the retention. Callers must hold j.mu.
+func (j *FileJournal) flush() error {
+	if j.retention > 0 {
+		// Dropped from memory too: they are final, so nothing reads them back
+		j.entries.prune(time.Now().Add(-j.retention))
+	}
+	raw, err := json.MarshalIndent(sortedClones(j.entries.byID), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create journal temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close journal: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		return fmt.Errorf("failed to replace journal: %w", err)
+	}
+	return nil
+}
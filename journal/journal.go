@@ -0,0 +1,399 @@
+// Package journal records every transaction the library builds, signs and sends, so a process that dies
+// between sending a transaction and seeing it mined can find out what it sent and reconcile it on restart.
+package journal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrNotFound is returned when an entry does not exist
+var ErrNotFound = errors.New("journal entry not found")
+
+// State is the lifecycle state of a journal entry
+type State string
+
+const (
+	// StateBuilt means the call was built but no transaction was signed yet
+	StateBuilt State = "built"
+	// StateSigned means the transaction was signed (or handed to a remote signer) but may not have been broadcast
+	StateSigned State = "signed"
+	// StateSent means the transaction was broadcast
+	StateSent State = "sent"
+	// StateMined means the transaction was mined successfully
+	StateMined State = "mined"
+	// StateFailed means the transaction reverted, was never sent, or its nonce was used by another transaction
+	StateFailed State = "failed"
+)
+
+// IsFinal reports whether the state can no longer change
+func (s State) IsFinal() bool {
+	return s == StateMined || s == StateFailed
+}
+
+// Entry is one call sent through the library, directly from an EOA or through a Safe
+type Entry struct {
+	ID        string    `json:"id"`
+	State     State     `json:"state"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// From is the EOA sending the transaction (the Safe owner for Safe calls)
+	From common.Address `json:"from"`
+	// Safe is the Safe executing the call, zero for EOA calls
+	Safe common.Address `json:"safe,omitempty"`
+	// To, Data and Value describe the call itself (the inner call for Safe calls)
+	To    common.Address `json:"to"`
+	Data  hexutil.Bytes  `json:"data,omitempty"`
+	Value *hexutil.Big   `json:"value,omitempty"`
+
+	// SafeNonce is the Safe nonce the call was signed with
+	SafeNonce *hexutil.Big `json:"safeNonce,omitempty"`
+	// Nonce is the account nonce of the transaction
+	Nonce *hexutil.Uint64 `json:"nonce,omitempty"`
+	// RawTx is the RLP-encoded signed transaction, kept so it can be rebroadcast
+	RawTx  hexutil.Bytes `json:"rawTx,omitempty"`
+	TxHash common.Hash   `json:"txHash,omitempty"`
+
+	// Key is the idempotency key the call was made with, see WithIdempotencyKey
+	Key string `json:"key,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// NewEntry creates a StateBuilt entry with a random ID for a call
+func NewEntry(from, safe, to common.Address, data []byte, value *big.Int) *Entry {
+	now := time.Now().UTC()
+	e := &Entry{
+		ID:        newID(),
+		State:     StateBuilt,
+		CreatedAt: now,
+		UpdatedAt: now,
+		From:      from,
+		Safe:      safe,
+		To:        to,
+		Data:      common.CopyBytes(data),
+	}
+	if value != nil {
+		e.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	return e
+}
+
+// SameCall reports whether e is for the same call from the same account as other
+func (e *Entry) SameCall(other *Entry) bool {
+	return e.From == other.From && e.Safe == other.Safe && e.To == other.To &&
+		string(e.Data) == string(other.Data) && bigValue(e.Value).Cmp(bigValue(other.Value)) == 0
+}
+
+// Fail marks the entry failed with err
+func (e *Entry) Fail(err error) {
+	e.State = StateFailed
+	if err != nil {
+		e.Error = err.Error()
+	}
+}
+
+func (e *Entry) clone() *Entry {
+	c := *e
+	c.Data = common.CopyBytes(e.Data)
+	c.RawTx = common.CopyBytes(e.RawTx)
+	if e.Value != nil {
+		c.Value = (*hexutil.Big)(new(big.Int).Set(e.Value.ToInt()))
+	}
+	if e.SafeNonce != nil {
+		c.SafeNonce = (*hexutil.Big)(new(big.Int).Set(e.SafeNonce.ToInt()))
+	}
+	if e.Nonce != nil {
+		n := *e.Nonce
+		c.Nonce = &n
+	}
+	return &c
+}
+
+func bigValue(v *hexutil.Big) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v.ToInt()
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("journal: failed to generate entry id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// Journal stores entries. Implementations must be safe for concurrent use.
+type Journal interface {
+	// Put inserts or replaces an entry
+	Put(e *Entry) error
+	// Update applies fn to the entry with the given ID and stores the result atomically
+	Update(id string, fn func(e *Entry)) error
+	// Get returns a copy of an entry, or ErrNotFound
+	Get(id string) (*Entry, error)
+	// List returns copies of all entries, oldest first
+	List() ([]*Entry, error)
+}
+
+// HashIndex is implemented by journals looking entries up by transaction hash without listing them all
+type HashIndex interface {
+	// GetByHash returns a copy of the entry last sent as txHash, or ErrNotFound
+	GetByHash(txHash common.Hash) (*Entry, error)
+}
+
+// Pruner is implemented by journals that can drop the entries they no longer need
+type Pruner interface {
+	// Prune removes the mined and failed entries last updated more than olderThan ago and returns how many it removed
+	Prune(olderThan time.Duration) (int, error)
+}
+
+// FindByHash returns the entry last sent as txHash, or ErrNotFound.
+// It uses the HashIndex of j if it has one, and lists every entry otherwise.
+func FindByHash(j Journal, txHash common.Hash) (*Entry, error) {
+	if index, ok := j.(HashIndex); ok {
+		return index.GetByHash(txHash)
+	}
+	entries, err := j.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.TxHash == txHash {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: tx %s", ErrNotFound, txHash.Hex())
+}
+
+// Pending returns the entries that are not mined or failed, oldest first
+func Pending(j Journal) ([]*Entry, error) {
+	entries, err := j.List()
+	if err != nil {
+		return nil, err
+	}
+	pending := entries[:0]
+	for _, e := range entries {
+		if !e.State.IsFinal() {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+// FindKeyed returns the newest entry that is not failed for the same call and idempotency key as e, if any
+func FindKeyed(j Journal, e *Entry) (*Entry, error) {
+	if e.Key == "" {
+		return nil, nil
+	}
+	entries, err := j.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if p := entries[i]; p.Key == e.Key && p.State != StateFailed && p.SameCall(e) {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// MemoryJournal keeps entries in memory. It does not survive a restart and is meant for tests
+// and for tracking within a single process.
+type MemoryJournal struct {
+	mu      sync.Mutex
+	entries *entrySet
+}
+
+var (
+	_ HashIndex = (*MemoryJournal)(nil)
+	_ Pruner    = (*MemoryJournal)(nil)
+)
+
+// NewMemoryJournal creates an empty MemoryJournal
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{entries: newEntrySet()}
+}
+
+// Put implements Journal
+func (j *MemoryJournal) Put(e *Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries.put(e.clone())
+	return nil
+}
+
+// Update implements Journal
+func (j *MemoryJournal) Update(id string, fn func(e *Entry)) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e, ok := j.entries.byID[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	updated := e.clone()
+	fn(updated)
+	updated.UpdatedAt = time.Now().UTC()
+	j.entries.put(updated)
+	return nil
+}
+
+// Get implements Journal
+func (j *MemoryJournal) Get(id string) (*Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e, ok := j.entries.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return e.clone(), nil
+}
+
+// GetByHash implements HashIndex
+func (j *MemoryJournal) GetByHash(txHash common.Hash) (*Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.entries.getByHash(txHash)
+}
+
+// List implements Journal
+func (j *MemoryJournal) List() ([]*Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return sortedClones(j.entries.byID), nil
+}
+
+// Prune implements Pruner
+func (j *MemoryJournal) Prune(olderThan time.Duration) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return len(j.entries.prune(time.Now().Add(-olderThan))), nil
+}
+
+// entrySet holds entries by ID and indexes them by transaction hash. Callers synchronize access.
+type entrySet struct {
+	byID   map[string]*Entry
+	byHash map[common.Hash]string
+}
+
+func newEntrySet() *entrySet {
+	return &entrySet{byID: make(map[string]*Entry), byHash: make(map[common.Hash]string)}
+}
+
+// put stores e as is, replacing the entry with the same ID
+func (s *entrySet) put(e *Entry) {
+	if prev, ok := s.byID[e.ID]; ok && prev.TxHash != e.TxHash && s.byHash[prev.TxHash] == e.ID {
+		delete(s.byHash, prev.TxHash)
+	}
+	s.byID[e.ID] = e
+	if e.TxHash != (common.Hash{}) {
+		s.byHash[e.TxHash] = e.ID
+	}
+}
+
+func (s *entrySet) remove(id string) {
+	if e, ok := s.byID[id]; ok && s.byHash[e.TxHash] == id {
+		delete(s.byHash, e.TxHash)
+	}
+	delete(s.byID, id)
+}
+
+func (s *entrySet) getByHash(txHash common.Hash) (*Entry, error) {
+	id, ok := s.byHash[txHash]
+	if !ok {
+		return nil, fmt.Errorf("%w: tx %s", ErrNotFound, txHash.Hex())
+	}
+	return s.byID[id].clone(), nil
+}
+
+// prune removes the final entries last updated before cutoff and returns them
+func (s *entrySet) prune(cutoff time.Time) []*Entry {
+	var pruned []*Entry
+	for id, e := range s.byID {
+		if e.State.IsFinal() && e.UpdatedAt.Before(cutoff) {
+			pruned = append(pruned, e)
+			s.remove(id)
+		}
+	}
+	return pruned
+}
+
+func sortedClones(entries map[string]*Entry) []*Entry {
+	list := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e.clone())
+	}
+	sort.Slice(list, func(a, b int) bool {
+		if list[a].CreatedAt.Equal(list[b].CreatedAt) {
+			return list[a].ID < list[b].ID
+		}
+		return list[a].CreatedAt.Before(list[b].CreatedAt)
+	})
+	return list
+}
+
+type contextKey struct{}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context making the calls sent with it idempotent: a call is not sent again
+// while an entry for the same call and key is pending or mined. Calls without a key are always sent.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// IdempotencyKey returns the key set by WithIdempotencyKey, or ""
+func IdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+type contextEntry struct {
+	journal Journal
+	id      string
+}
+
+// NewContext returns a context carrying the journal entry a transaction belongs to.
+// Senders record what they sign and send on that entry.
+func NewContext(ctx context.Context, j Journal, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, contextEntry{journal: j, id: id})
+}
+
+// FromContext returns the journal and entry ID carried by ctx
+func FromContext(ctx context.Context) (Journal, string, bool) {
+	ce, ok := ctx.Value(contextKey{}).(contextEntry)
+	if !ok {
+		return nil, "", false
+	}
+	return ce.journal, ce.id, true
+}
+
+// Record applies fn to the entry carried by ctx. It does nothing if ctx carries no entry.
+func Record(ctx context.Context, fn func(e *Entry)) error {
+	j, id, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if err := j.Update(id, fn); err != nil {
+		return fmt.Errorf("failed to update journal entry: %w", err)
+	}
+	return nil
+}
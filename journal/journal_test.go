@@ -0,0 +1,230 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type mockClient struct {
+	receipts map[common.Hash]*types.Receipt
+	nonce    uint64
+	sent     []*types.Transaction
+	sendErr  error
+}
+
+func (c *mockClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if r, ok := c.receipts[txHash]; ok {
+		return r, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *mockClient) NonceAt(_ context.Context, _ common.Address, _ *big.Int) (uint64, error) {
+	return c.nonce, nil
+}
+
+func (c *mockClient) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	c.sent = append(c.sent, tx)
+	return c.sendErr
+}
+
+// signedEntry returns a journaled entry in state with a signed transaction of the given nonce
+func signedEntry(t *testing.T, j Journal, state State, nonce uint64) *Entry {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	to := common.HexToAddress("0x1111")
+	tx, err := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(0), 21000, big.NewInt(1), nil), types.LatestSignerForChainID(big.NewInt(137)), key)
+	if err != nil {
+		t.Fatalf("SignTx: %v", err)
+	}
+	raw, _ := tx.MarshalBinary()
+
+	e := NewEntry(crypto.PubkeyToAddress(key.PublicKey), common.Address{}, to, nil, big.NewInt(0))
+	e.State, e.RawTx, e.TxHash = state, raw, tx.Hash()
+	if err := j.Put(e); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	return e
+}
+
+func TestFileJournal_PersistsAcrossOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	j, err := OpenFileJournal(path)
+	if err != nil {
+		t.Fatalf("OpenFileJournal: %v", err)
+	}
+
+	e := NewEntry(common.HexToAddress("0xEOA"), common.HexToAddress("0x5afe"), common.HexToAddress("0x1111"), []byte{0xAB}, big.NewInt(7))
+	if err := j.Put(e); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := j.Update(e.ID, func(e *Entry) { e.State = StateSent; e.TxHash = common.HexToHash("0x01") }); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	reopened, err := OpenFileJournal(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, err := reopened.Get(e.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.State != StateSent || got.TxHash != common.HexToHash("0x01") || !got.SameCall(e) {
+		t.Errorf("entry not persisted: %+v", got)
+	}
+	if _, err := reopened.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRecord_WithoutEntryIsNoop(t *testing.T) {
+	if err := Record(context.Background(), func(e *Entry) { t.Fatal("must not be called") }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	j := NewMemoryJournal()
+	e := NewEntry(common.Address{}, common.Address{}, common.Address{}, nil, nil)
+	_ = j.Put(e)
+	if err := Record(NewContext(context.Background(), j, e.ID), func(e *Entry) { e.State = StateSigned }); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if got, _ := j.Get(e.ID); got.State != StateSigned {
+		t.Errorf("expected signed, got %s", got.State)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	j := NewMemoryJournal()
+	client := &mockClient{receipts: make(map[common.Hash]*types.Receipt), nonce: 5}
+
+	mined := signedEntry(t, j, StateSent, 3)
+	client.receipts[mined.TxHash] = &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	reverted := signedEntry(t, j, StateSent, 4)
+	client.receipts[reverted.TxHash] = &types.Receipt{Status: types.ReceiptStatusFailed}
+	replaced := signedEntry(t, j, StateSigned, 2)
+	rebroadcast := signedEntry(t, j, StateSigned, 5)
+	notSent := NewEntry(common.HexToAddress("0xEOA"), common.Address{}, common.HexToAddress("0x1111"), nil, nil)
+	_ = j.Put(notSent)
+	remote := NewEntry(common.HexToAddress("0xEOA"), common.Address{}, common.HexToAddress("0x2222"), nil, nil)
+	remote.State = StateSigned
+	_ = j.Put(remote)
+
+	pending, err := Recover(context.Background(), j, client)
+	if err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+
+	expect := map[string]State{
+		mined.ID:       StateMined,
+		reverted.ID:    StateFailed,
+		replaced.ID:    StateFailed,
+		rebroadcast.ID: StateSent,
+		notSent.ID:     StateFailed,
+		remote.ID:      StateSigned,
+	}
+	for id, want := range expect {
+		if got, _ := j.Get(id); got.State != want {
+			t.Errorf("entry %s: expected %s, got %s (%s)", id, want, got.State, got.Error)
+		}
+	}
+	if got, _ := j.Get(replaced.ID); got.Error != ErrReplaced.Error() {
+		t.Errorf("expected ErrReplaced, got %q", got.Error)
+	}
+	if len(client.sent) != 1 || client.sent[0].Hash() != rebroadcast.TxHash {
+		t.Errorf("expected only the unmined signed tx to be rebroadcast, got %d", len(client.sent))
+	}
+	if len(pending) != 2 {
+		t.Errorf("expected 2 entries still pending, got %d", len(pending))
+	}
+}
+
+func TestRecover_AlreadyKnownIsSent(t *testing.T) {
+	j := NewMemoryJournal()
+	client := &mockClient{sendErr: errors.New("already known")}
+	e := signedEntry(t, j, StateSigned, 0)
+
+	if _, err := Recover(context.Background(), j, client); err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	if got, _ := j.Get(e.ID); got.State != StateSent {
+		t.Errorf("expected sent, got %s", got.State)
+	}
+}
+
+func TestJournals_GetByHashAndPrune(t *testing.T) {
+	file, err := OpenFileJournal(filepath.Join(t.TempDir(), "journal.json"))
+	if err != nil {
+		t.Fatalf("OpenFileJournal: %v", err)
+	}
+	for name, j := range map[string]interface {
+		Journal
+		HashIndex
+		Pruner
+	}{"memory": NewMemoryJournal(), "file": file} {
+		mined := signedEntry(t, j, StateMined, 1)
+		sent := signedEntry(t, j, StateSent, 2)
+
+		if got, err := j.GetByHash(sent.TxHash); err != nil || got.ID != sent.ID {
+			t.Errorf("%s: GetByHash: %v", name, err)
+		}
+		replacement := common.HexToHash("0xbeef")
+		_ = j.Update(sent.ID, func(e *Entry) { e.TxHash = replacement })
+		if _, err := j.GetByHash(sent.TxHash); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: expected the replaced hash unindexed, got %v", name, err)
+		}
+		if got, err := FindByHash(j, replacement); err != nil || got.ID != sent.ID {
+			t.Errorf("%s: FindByHash: %v", name, err)
+		}
+
+		if n, err := j.Prune(time.Hour); err != nil || n != 0 {
+			t.Errorf("%s: expected recent entries kept, pruned %d: %v", name, n, err)
+		}
+		if n, err := j.Prune(0); err != nil || n != 1 {
+			t.Errorf("%s: expected the mined entry pruned, pruned %d: %v", name, n, err)
+		}
+		if _, err := j.Get(mined.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: mined entry still journaled", name)
+		}
+		if _, err := j.GetByHash(mined.TxHash); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: mined entry still indexed", name)
+		}
+		if _, err := j.Get(sent.ID); err != nil {
+			t.Errorf("%s: pending entry pruned: %v", name, err)
+		}
+	}
+}
+
+func TestFileJournal_Retention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	j, err := OpenFileJournal(path, WithRetention(time.Nanosecond))
+	if err != nil {
+		t.Fatalf("OpenFileJournal: %v", err)
+	}
+	mined := signedEntry(t, j, StateMined, 1)
+	time.Sleep(time.Millisecond)
+	sent := signedEntry(t, j, StateSent, 2)
+
+	reopened, err := OpenFileJournal(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if _, err := reopened.Get(mined.ID); !errors.Is(err, ErrNotFound) {
+		t.Error("expected the mined entry dropped past the retention")
+	}
+	if _, err := reopened.Get(sent.ID); err != nil {
+		t.Errorf("pending entry dropped: %v", err)
+	}
+}
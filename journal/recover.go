@@ -0,0 +1,117 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/retry"
+)
+
+var (
+	// ErrNotSent is recorded on entries that were never signed, so nothing was broadcast
+	ErrNotSent = errors.New("transaction was never signed")
+	// ErrReplaced is recorded on entries whose nonce was used by another transaction
+	ErrReplaced = errors.New("nonce was used by another transaction")
+	// ErrReverted is recorded on entries whose transaction was mined but reverted
+	ErrReverted = errors.New("transaction reverted")
+)
+
+// Client is the chain access Recover needs
+type Client interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// Recover reconciles the pending entries of j against the chain, typically on startup:
+//   - an entry with a receipt becomes StateMined, or StateFailed if it reverted;
+//   - a signed transaction whose nonce was used by another transaction becomes StateFailed (ErrReplaced);
+//   - any other signed transaction is rebroadcast and stays StateSent until it is mined;
+//   - an entry that was only built becomes StateFailed (ErrNotSent) and can be retried.
+//
+// Entries handed to a remote signer that never reported a hash cannot be reconciled and are left as they are.
+// Recover returns the entries still pending afterwards.
+func Recover(ctx context.Context, j Journal, client Client) ([]*Entry, error) {
+	pending, err := Pending(j)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending entries: %w", err)
+	}
+
+	var errs []error
+	for _, e := range pending {
+		if _, err := Reconcile(ctx, j, client, e.ID); err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", e.ID, err))
+		}
+	}
+
+	stillPending, err := Pending(j)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list pending entries: %w", err))
+	}
+	return stillPending, errors.Join(errs...)
+}
+
+// Reconcile applies the rules of Recover to one entry and returns it updated
+func Reconcile(ctx context.Context, j Journal, client Client, id string) (*Entry, error) {
+	e, err := j.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !e.State.IsFinal() {
+		if err := reconcile(ctx, j, client, e); err != nil {
+			return nil, err
+		}
+	}
+	return j.Get(id)
+}
+
+func reconcile(ctx context.Context, j Journal, client Client, e *Entry) error {
+	if e.TxHash != (common.Hash{}) {
+		receipt, err := client.TransactionReceipt(ctx, e.TxHash)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("failed to get receipt: %w", err)
+		}
+		if receipt != nil {
+			return j.Update(e.ID, func(e *Entry) {
+				if receipt.Status == types.ReceiptStatusSuccessful {
+					e.State = StateMined
+					e.Error = ""
+				} else {
+					e.Fail(ErrReverted)
+				}
+			})
+		}
+	}
+
+	if len(e.RawTx) == 0 {
+		if e.State == StateBuilt {
+			return j.Update(e.ID, func(e *Entry) { e.Fail(ErrNotSent) })
+		}
+		return nil
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(e.RawTx); err != nil {
+		return fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+	nonce, err := client.NonceAt(ctx, e.From, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+	if nonce > tx.Nonce() {
+		return j.Update(e.ID, func(e *Entry) { e.Fail(ErrReplaced) })
+	}
+
+	if err := retry.Broadcast(ctx, retry.DefaultPolicy, client, tx); err != nil {
+		return fmt.Errorf("failed to rebroadcast transaction: %w", err)
+	}
+	return j.Update(e.ID, func(e *Entry) {
+		e.State = StateSent
+		e.TxHash = tx.Hash()
+	})
+}
@@ -0,0 +1,511 @@
+// Package multiclient implements ethclient.EthClientInterface over several RPC endpoints: reads fail over
+// between healthy endpoints (optionally requiring a quorum), signed transactions are broadcast to every
+// endpoint, and endpoints on another chain or with an inconsistent head are dropped.
+package multiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ivanzzeth/ethclient"
+)
+
+var (
+	// ErrNoEndpoints is returned when every endpoint is dropped
+	ErrNoEndpoints = errors.New("no usable RPC endpoint")
+	// ErrNoQuorum is returned when not enough endpoints agree on a read
+	ErrNoQuorum = errors.New("RPC endpoints did not reach quorum")
+)
+
+// Endpoint is one RPC endpoint of a Client
+type Endpoint struct {
+	// Name identifies the endpoint in errors and status, e.g. its host
+	Name   string
+	Client ethclient.EthClientInterface
+}
+
+// EndpointStatus describes the health of an endpoint
+type EndpointStatus struct {
+	Name string
+	// Healthy is false while the endpoint is cooling down after consecutive failures
+	Healthy  bool
+	Failures int
+	LastErr  error
+	// Head is the block number seen by the last health check
+	Head uint64
+	// Dropped is the reason the endpoint is not used, empty if it is used
+	Dropped string
+}
+
+type endpoint struct {
+	name   string
+	client ethclient.EthClientInterface
+
+	failures       int
+	lastErr        error
+	unhealthyUntil time.Time
+	head           uint64
+	dropped        string
+	// wrongChain drops the endpoint for good; other drops are lifted by a consistent health check
+	wrongChain bool
+}
+
+// Client is an ethclient.EthClientInterface over several endpoints. It is safe for concurrent use.
+type Client struct {
+	endpoints []*endpoint
+
+	chainID     *big.Int
+	quorum      int
+	maxFailures int
+	cooldown    time.Duration
+	maxLag      uint64
+
+	mu sync.Mutex
+}
+
+var _ ethclient.EthClientInterface = (*Client)(nil)
+
+// Option configures optional fields on Client
+type Option func(c *Client)
+
+// WithChainID sets the expected chain id. By default it is the chain id reported by most endpoints.
+func WithChainID(chainID *big.Int) Option {
+	return func(c *Client) {
+		c.chainID = chainID
+	}
+}
+
+// WithQuorum requires n endpoints to return the same result for state reads (balances, nonces, code,
+// storage, eth_call) and receipts. Reads at the latest block are pinned to a block every endpoint has.
+// Default: 1, i.e. the first healthy endpoint answering wins.
+func WithQuorum(n int) Option {
+	return func(c *Client) {
+		c.quorum = n
+	}
+}
+
+// WithMaxFailures sets after how many consecutive failures an endpoint cools down (default: 3)
+func WithMaxFailures(n int) Option {
+	return func(c *Client) {
+		c.maxFailures = n
+	}
+}
+
+// WithCooldown sets how long a failing endpoint is tried only as a last resort (default: 30 seconds)
+func WithCooldown(d time.Duration) Option {
+	return func(c *Client) {
+		c.cooldown = d
+	}
+}
+
+// WithMaxLag sets how many blocks an endpoint's head may lag the highest head before it is dropped (default: 10)
+func WithMaxLag(blocks uint64) Option {
+	return func(c *Client) {
+		c.maxLag = blocks
+	}
+}
+
+// NewClient creates a Client over endpoints and runs a first health check
+func NewClient(ctx context.Context, endpoints []Endpoint, opts ...Option) (*Client, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	c := &Client{quorum: 1, maxFailures: 3, cooldown: 30 * time.Second, maxLag: 10}
+	for _, opt := range opts {
+		opt(c)
+	}
+	for _, e := range endpoints {
+		c.endpoints = append(c.endpoints, &endpoint{name: e.Name, client: e.Client})
+	}
+	if c.quorum < 1 || c.quorum > len(c.endpoints) {
+		return nil, fmt.Errorf("quorum %d out of range for %d endpoints", c.quorum, len(c.endpoints))
+	}
+
+	if err := c.CheckHealth(ctx); err != nil {
+		return nil, err
+	}
+	if c.chainID == nil {
+		return nil, fmt.Errorf("failed to get chain id from any endpoint: %w", ErrNoEndpoints)
+	}
+	return c, nil
+}
+
+// Dial connects to every URL and creates a Client over them
+func Dial(ctx context.Context, urls []string, opts ...Option) (*Client, error) {
+	endpoints := make([]Endpoint, 0, len(urls))
+	for _, url := range urls {
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial %s: %w", url, err)
+		}
+		endpoints = append(endpoints, Endpoint{Name: url, Client: client})
+	}
+	return NewClient(ctx, endpoints, opts...)
+}
+
+// Status returns the health of every endpoint
+func (c *Client) Status() []EndpointStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	status := make([]EndpointStatus, 0, len(c.endpoints))
+	for _, ep := range c.endpoints {
+		status = append(status, EndpointStatus{
+			Name:     ep.name,
+			Healthy:  !now.Before(ep.unhealthyUntil),
+			Failures: ep.failures,
+			LastErr:  ep.lastErr,
+			Head:     ep.head,
+			Dropped:  ep.dropped,
+		})
+	}
+	return status
+}
+
+// StartHealthChecks runs CheckHealth every interval until ctx is done
+func (c *Client) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = c.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+// headInfo is what a health check learns from one endpoint
+type headInfo struct {
+	chainID *big.Int
+	head    uint64
+	hash    common.Hash
+	err     error
+}
+
+// CheckHealth checks every endpoint and drops inconsistent ones:
+//   - an endpoint on another chain is dropped for good;
+//   - an endpoint whose head lags the highest head by more than MaxLag blocks, or whose block hash at
+//     a common height differs from most endpoints, is dropped until a later check finds it consistent.
+//
+// It returns ErrNoEndpoints if no endpoint is left.
+func (c *Client) CheckHealth(ctx context.Context) error {
+	candidates := c.checkable()
+	infos := make([]headInfo, len(candidates))
+	parallel(candidates, func(i int, ep *endpoint) {
+		info := &infos[i]
+		if info.chainID, info.err = ep.client.ChainID(ctx); info.err != nil {
+			return
+		}
+		info.head, info.err = ep.client.BlockNumber(ctx)
+	})
+
+	c.mu.Lock()
+	expected := c.chainID
+	if expected == nil {
+		expected = majorityChainID(infos)
+		c.chainID = expected
+	}
+	var maxHead uint64
+	for i, ep := range candidates {
+		info := infos[i]
+		if info.err != nil {
+			c.reportLocked(ep, info.err)
+			continue
+		}
+		if expected != nil && info.chainID.Cmp(expected) != 0 {
+			ep.wrongChain = true
+			ep.dropped = fmt.Sprintf("chain id %s, expected %s", info.chainID, expected)
+			continue
+		}
+		ep.head = info.head
+		maxHead = max(maxHead, info.head)
+	}
+
+	// Endpoints close enough to the highest head must agree on the block hash at the lowest of their heads
+	var consistent []*endpoint
+	commonHead := maxHead
+	for i, ep := range candidates {
+		if infos[i].err != nil || ep.wrongChain {
+			continue
+		}
+		if maxHead-ep.head > c.maxLag {
+			ep.dropped = fmt.Sprintf("head %d lags %d by more than %d blocks", ep.head, maxHead, c.maxLag)
+			continue
+		}
+		consistent = append(consistent, ep)
+		commonHead = min(commonHead, ep.head)
+	}
+	c.mu.Unlock()
+
+	hashes := make([]headInfo, len(consistent))
+	parallel(consistent, func(i int, ep *endpoint) {
+		header, err := ep.client.HeaderByNumber(ctx, new(big.Int).SetUint64(commonHead))
+		if err == nil && header == nil {
+			err = ethereum.NotFound
+		}
+		if err != nil {
+			hashes[i].err = err
+			return
+		}
+		hashes[i].hash = header.Hash()
+	})
+	majority := majorityHash(hashes)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, ep := range consistent {
+		switch {
+		case hashes[i].err != nil:
+			c.reportLocked(ep, hashes[i].err)
+		case hashes[i].hash != majority:
+			ep.dropped = fmt.Sprintf("block %d hash %s differs from %s", commonHead, hashes[i].hash.Hex(), majority.Hex())
+		default:
+			ep.dropped = ""
+		}
+	}
+	for _, ep := range c.endpoints {
+		if ep.dropped == "" {
+			return nil
+		}
+	}
+	return ErrNoEndpoints
+}
+
+// checkable returns the endpoints that may become usable again
+func (c *Client) checkable() []*endpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var eps []*endpoint
+	for _, ep := range c.endpoints {
+		if !ep.wrongChain {
+			eps = append(eps, ep)
+		}
+	}
+	return eps
+}
+
+// usable returns the endpoints that are not dropped: healthy ones first, then the ones cooling down,
+// each group ordered by consecutive failures
+func (c *Client) usable() ([]*endpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	var eps []*endpoint
+	for _, ep := range c.endpoints {
+		if ep.dropped == "" {
+			eps = append(eps, ep)
+		}
+	}
+	if len(eps) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	sort.SliceStable(eps, func(a, b int) bool {
+		coolingA, coolingB := now.Before(eps[a].unhealthyUntil), now.Before(eps[b].unhealthyUntil)
+		if coolingA != coolingB {
+			return !coolingA
+		}
+		return eps[a].failures < eps[b].failures
+	})
+	return eps, nil
+}
+
+// report records the outcome of a call on ep. Errors that are the answer to the call
+// (reverts, not found) do not count against the endpoint.
+func (c *Client) report(ep *endpoint, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reportLocked(ep, err)
+}
+
+func (c *Client) reportLocked(ep *endpoint, err error) {
+	if err == nil || isResultErr(err) || errors.Is(err, ethereum.NotFound) {
+		ep.failures = 0
+		return
+	}
+	ep.failures++
+	ep.lastErr = err
+	if ep.failures >= c.maxFailures {
+		ep.unhealthyUntil = time.Now().Add(c.cooldown)
+	}
+}
+
+// isResultErr reports whether err is the node's answer to the call rather than a failure of the endpoint
+func isResultErr(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"revert", "insufficient funds", "nonce too low", "already known", "replacement transaction underpriced", "gas required exceeds"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func parallel(eps []*endpoint, fn func(i int, ep *endpoint)) {
+	var wg sync.WaitGroup
+	for i, ep := range eps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(i, ep)
+		}()
+	}
+	wg.Wait()
+}
+
+func majorityChainID(infos []headInfo) *big.Int {
+	counts := make(map[string]int)
+	var best *big.Int
+	for _, info := range infos {
+		if info.err != nil {
+			continue
+		}
+		key := info.chainID.String()
+		counts[key]++
+		if best == nil || counts[key] > counts[best.String()] {
+			best = info.chainID
+		}
+	}
+	return best
+}
+
+func majorityHash(infos []headInfo) common.Hash {
+	counts := make(map[common.Hash]int)
+	var best common.Hash
+	for _, info := range infos {
+		if info.err != nil {
+			continue
+		}
+		counts[info.hash]++
+		if counts[info.hash] > counts[best] {
+			best = info.hash
+		}
+	}
+	return best
+}
+
+// read calls fn on the usable endpoints in order until one answers. A not-found answer is retried on
+// the next endpoint, since a lagging endpoint may not have the block or transaction yet.
+func read[T any](ctx context.Context, c *Client, fn func(client ethclient.EthClientInterface) (T, error)) (T, error) {
+	var zero T
+	eps, err := c.usable()
+	if err != nil {
+		return zero, err
+	}
+
+	var errs []error
+	notFound := false
+	for _, ep := range eps {
+		result, err := fn(ep.client)
+		c.report(ep, err)
+		if err == nil {
+			return result, nil
+		}
+		if errors.Is(err, ethereum.NotFound) {
+			notFound = true
+			continue
+		}
+		if isResultErr(err) {
+			return zero, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", ep.name, err))
+	}
+	if notFound {
+		return zero, ethereum.NotFound
+	}
+	return zero, errors.Join(errs...)
+}
+
+// quorumRead calls fn on every usable endpoint and returns the result at least quorum endpoints agree on,
+// comparing results by key. With a quorum of 1 it is read.
+func quorumRead[T any](ctx context.Context, c *Client, fn func(client ethclient.EthClientInterface) (T, error), key func(T) string) (T, error) {
+	if c.quorum <= 1 {
+		return read(ctx, c, fn)
+	}
+	var zero T
+	eps, err := c.usable()
+	if err != nil {
+		return zero, err
+	}
+
+	results := make([]T, len(eps))
+	errs := make([]error, len(eps))
+	parallel(eps, func(i int, ep *endpoint) {
+		results[i], errs[i] = fn(ep.client)
+		c.report(ep, errs[i])
+	})
+
+	votes := make(map[string]int)
+	var resultErrs []error
+	for i := range eps {
+		if errs[i] != nil {
+			resultErrs = append(resultErrs, fmt.Errorf("%s: %w", eps[i].name, errs[i]))
+			continue
+		}
+		k := key(results[i])
+		votes[k]++
+		if votes[k] >= c.quorum {
+			return results[i], nil
+		}
+	}
+	return zero, fmt.Errorf("%w: need %d of %d endpoints: %w", ErrNoQuorum, c.quorum, len(eps), errors.Join(resultErrs...))
+}
+
+// pinBlock returns blockNumber, or under a quorum the lowest head among usable endpoints,
+// so that reads at the latest block compare the same state
+func (c *Client) pinBlock(ctx context.Context, blockNumber *big.Int) (*big.Int, error) {
+	if blockNumber != nil || c.quorum <= 1 {
+		return blockNumber, nil
+	}
+	eps, err := c.usable()
+	if err != nil {
+		return nil, err
+	}
+
+	heads := make([]uint64, len(eps))
+	errs := make([]error, len(eps))
+	parallel(eps, func(i int, ep *endpoint) {
+		heads[i], errs[i] = ep.client.BlockNumber(ctx)
+		c.report(ep, errs[i])
+	})
+
+	var pinned *big.Int
+	answered := 0
+	for i := range eps {
+		if errs[i] != nil {
+			continue
+		}
+		answered++
+		if pinned == nil || heads[i] < pinned.Uint64() {
+			pinned = new(big.Int).SetUint64(heads[i])
+		}
+	}
+	if answered < c.quorum {
+		return nil, fmt.Errorf("%w: only %d endpoints report a head", ErrNoQuorum, answered)
+	}
+	return pinned, nil
+}
@@ -0,0 +1,228 @@
+package multiclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ivanzzeth/ethclient"
+)
+
+// fakeClient is an endpoint with a chain id, a head and per-block balances of a single account.
+// Methods not overridden panic through the nil embedded interface.
+type fakeClient struct {
+	ethclient.EthClientInterface
+
+	mu       sync.Mutex
+	chainID  int64
+	head     uint64
+	fork     bool
+	down     bool
+	balances map[uint64]int64
+	receipt  *types.Receipt
+	sendErr  error
+	sent     int
+	calls    int
+}
+
+var errDown = errors.New("connection refused")
+
+func (f *fakeClient) ChainID(ctx context.Context) (*big.Int, error) {
+	if f.down {
+		return nil, errDown
+	}
+	return big.NewInt(f.chainID), nil
+}
+
+func (f *fakeClient) BlockNumber(ctx context.Context) (uint64, error) {
+	if f.down {
+		return 0, errDown
+	}
+	return f.head, nil
+}
+
+func (f *fakeClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if f.down {
+		return nil, errDown
+	}
+	header := &types.Header{Number: number, Difficulty: big.NewInt(0)}
+	if f.fork {
+		header.Extra = []byte("fork")
+	}
+	return header, nil
+}
+
+func (f *fakeClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	f.mu.Lock()
+	f.calls++
+	f.mu.Unlock()
+	if f.down {
+		return nil, errDown
+	}
+	block := f.head
+	if blockNumber != nil {
+		block = blockNumber.Uint64()
+	}
+	return big.NewInt(f.balances[block]), nil
+}
+
+func (f *fakeClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if f.down {
+		return nil, errDown
+	}
+	if f.receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	return f.receipt, nil
+}
+
+func (f *fakeClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	f.mu.Lock()
+	f.sent++
+	f.mu.Unlock()
+	if f.down {
+		return errDown
+	}
+	return f.sendErr
+}
+
+func newTestClient(t *testing.T, clients []*fakeClient, opts ...Option) *Client {
+	t.Helper()
+	endpoints := make([]Endpoint, len(clients))
+	for i, f := range clients {
+		endpoints[i] = Endpoint{Name: string(rune('a' + i)), Client: f}
+	}
+	c, err := NewClient(context.Background(), endpoints, opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+func dropped(c *Client) map[string]string {
+	reasons := make(map[string]string)
+	for _, s := range c.Status() {
+		reasons[s.Name] = s.Dropped
+	}
+	return reasons
+}
+
+func TestCheckHealthDropsInconsistentEndpoints(t *testing.T) {
+	wrongChain := &fakeClient{chainID: 1, head: 100}
+	lagging := &fakeClient{chainID: 137, head: 50}
+	forked := &fakeClient{chainID: 137, head: 100, fork: true}
+	c := newTestClient(t, []*fakeClient{
+		{chainID: 137, head: 100},
+		{chainID: 137, head: 99},
+		wrongChain, lagging, forked,
+	})
+
+	reasons := dropped(c)
+	if reasons["a"] != "" || reasons["b"] != "" {
+		t.Fatalf("consistent endpoints dropped: %v", reasons)
+	}
+	for _, name := range []string{"c", "d", "e"} {
+		if reasons[name] == "" {
+			t.Errorf("endpoint %s not dropped", name)
+		}
+	}
+	if id, _ := c.ChainID(context.Background()); id.Int64() != 137 {
+		t.Errorf("chain id = %s, want majority 137", id)
+	}
+
+	// A lagging endpoint that catches up is used again, one on another chain is not
+	lagging.head = 100
+	wrongChain.chainID = 137
+	if err := c.CheckHealth(context.Background()); err != nil {
+		t.Fatalf("CheckHealth: %v", err)
+	}
+	reasons = dropped(c)
+	if reasons["d"] != "" {
+		t.Errorf("caught up endpoint still dropped: %s", reasons["d"])
+	}
+	if reasons["c"] == "" {
+		t.Error("endpoint on another chain was used again")
+	}
+}
+
+func TestReadFailsOver(t *testing.T) {
+	flaky := &fakeClient{chainID: 137, head: 10}
+	healthy := &fakeClient{chainID: 137, head: 10, balances: map[uint64]int64{10: 42}}
+	c := newTestClient(t, []*fakeClient{flaky, healthy}, WithMaxFailures(1))
+
+	flaky.down = true
+	balance, err := c.BalanceAt(context.Background(), common.Address{}, nil)
+	if err != nil || balance.Int64() != 42 {
+		t.Fatalf("BalanceAt = %v, %v; want 42", balance, err)
+	}
+
+	// The failing endpoint cools down and is no longer tried first
+	if _, err := c.BalanceAt(context.Background(), common.Address{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("flaky endpoint called %d times, want 1", flaky.calls)
+	}
+	if status := c.Status(); status[0].Healthy || !status[1].Healthy {
+		t.Errorf("status = %+v", status)
+	}
+}
+
+func TestReadRetriesNotFound(t *testing.T) {
+	behind := &fakeClient{chainID: 137, head: 10}
+	ahead := &fakeClient{chainID: 137, head: 10, receipt: &types.Receipt{Status: types.ReceiptStatusSuccessful}}
+	c := newTestClient(t, []*fakeClient{behind, ahead})
+
+	if _, err := c.TransactionReceipt(context.Background(), common.Hash{}); err != nil {
+		t.Fatalf("receipt known to one endpoint not found: %v", err)
+	}
+
+	ahead.receipt = nil
+	if _, err := c.TransactionReceipt(context.Background(), common.Hash{}); !errors.Is(err, ethereum.NotFound) {
+		t.Fatalf("err = %v, want NotFound", err)
+	}
+}
+
+func TestQuorumRead(t *testing.T) {
+	a := &fakeClient{chainID: 137, head: 11, balances: map[uint64]int64{10: 5, 11: 6}}
+	b := &fakeClient{chainID: 137, head: 10, balances: map[uint64]int64{10: 5}}
+	liar := &fakeClient{chainID: 137, head: 10, balances: map[uint64]int64{10: 1000}}
+	c := newTestClient(t, []*fakeClient{a, b, liar}, WithQuorum(2))
+
+	// The latest block is pinned to the lowest head, where a and b agree
+	balance, err := c.BalanceAt(context.Background(), common.Address{}, nil)
+	if err != nil || balance.Int64() != 5 {
+		t.Fatalf("BalanceAt = %v, %v; want 5", balance, err)
+	}
+
+	b.balances[10] = 7
+	if _, err := c.BalanceAt(context.Background(), common.Address{}, big.NewInt(10)); !errors.Is(err, ErrNoQuorum) {
+		t.Fatalf("err = %v, want ErrNoQuorum", err)
+	}
+}
+
+func TestSendTransactionBroadcastsToAll(t *testing.T) {
+	down := &fakeClient{chainID: 137, head: 1}
+	known := &fakeClient{chainID: 137, head: 1}
+	c := newTestClient(t, []*fakeClient{down, known})
+
+	down.down = true
+	known.sendErr = errors.New("already known")
+	tx := types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(1)})
+	if err := c.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("SendTransaction: %v", err)
+	}
+	if down.sent != 1 || known.sent != 1 {
+		t.Errorf("sent to %d and %d endpoints, want both", down.sent, known.sent)
+	}
+
+	known.sendErr = errors.New("nonce too low")
+	if err := c.SendTransaction(context.Background(), tx); err == nil || err.Error() != "nonce too low" {
+		t.Fatalf("err = %v, want nonce too low", err)
+	}
+}
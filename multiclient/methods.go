@@ -0,0 +1,296 @@
+package multiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ivanzzeth/ethclient"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/retry"
+)
+
+func bigKey(v *big.Int) string {
+	return v.String()
+}
+
+func bytesKey(v []byte) string {
+	return hexutil.Encode(v)
+}
+
+func uint64Key(v uint64) string {
+	return fmt.Sprint(v)
+}
+
+// receiptKey compares receipts by where and how the transaction was mined
+func receiptKey(r *types.Receipt) string {
+	return fmt.Sprintf("%s/%d", r.BlockHash.Hex(), r.Status)
+}
+
+// SendTransaction broadcasts tx to every usable endpoint. It succeeds if any endpoint accepts it;
+// endpoints that already know the transaction count as accepting it.
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	eps, err := c.usable()
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(eps))
+	parallel(eps, func(i int, ep *endpoint) {
+		errs[i] = ep.client.SendTransaction(ctx, tx)
+		c.report(ep, errs[i])
+	})
+
+	var failures []error
+	for i, err := range errs {
+		if err == nil || retry.IsAlreadyKnown(err) {
+			return nil
+		}
+		failures = append(failures, fmt.Errorf("%s: %w", eps[i].name, err))
+	}
+	// Every endpoint rejecting the transaction for the same reason (e.g. nonce too low) is the answer
+	for _, err := range errs {
+		if isResultErr(err) {
+			return err
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// SubscribeNewHead subscribes to new heads on the first usable endpoint accepting the subscription
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (ethereum.Subscription, error) {
+		return client.SubscribeNewHead(ctx, ch)
+	})
+}
+
+// SubscribeFilterLogs subscribes to logs on the first usable endpoint accepting the subscription
+func (c *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (ethereum.Subscription, error) {
+		return client.SubscribeFilterLogs(ctx, q, ch)
+	})
+}
+
+// ChainID returns the chain id all usable endpoints agree on
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return new(big.Int).Set(c.chainID), nil
+}
+
+// BalanceAt returns the balance of account, under a quorum if one is set
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	blockNumber, err := c.pinBlock(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return quorumRead(ctx, c, func(client ethclient.EthClientInterface) (*big.Int, error) {
+		return client.BalanceAt(ctx, account, blockNumber)
+	}, bigKey)
+}
+
+// CallContract executes a message call, under a quorum if one is set
+func (c *Client) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	blockNumber, err := c.pinBlock(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return quorumRead(ctx, c, func(client ethclient.EthClientInterface) ([]byte, error) {
+		return client.CallContract(ctx, call, blockNumber)
+	}, bytesKey)
+}
+
+// CodeAt returns the code of account, under a quorum if one is set
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	blockNumber, err := c.pinBlock(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return quorumRead(ctx, c, func(client ethclient.EthClientInterface) ([]byte, error) {
+		return client.CodeAt(ctx, account, blockNumber)
+	}, bytesKey)
+}
+
+// NonceAt returns the nonce of account, under a quorum if one is set
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	blockNumber, err := c.pinBlock(ctx, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+	return quorumRead(ctx, c, func(client ethclient.EthClientInterface) (uint64, error) {
+		return client.NonceAt(ctx, account, blockNumber)
+	}, uint64Key)
+}
+
+// StorageAt returns a storage slot of account, under a quorum if one is set
+func (c *Client) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	blockNumber, err := c.pinBlock(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return quorumRead(ctx, c, func(client ethclient.EthClientInterface) ([]byte, error) {
+		return client.StorageAt(ctx, account, key, blockNumber)
+	}, bytesKey)
+}
+
+// TransactionReceipt returns the receipt of a mined transaction, under a quorum if one is set
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := quorumRead(ctx, c, func(client ethclient.EthClientInterface) (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, txHash)
+	}, receiptKey)
+	if errors.Is(err, ErrNoQuorum) {
+		// Not mined on enough endpoints yet
+		return nil, fmt.Errorf("%w: %w", ethereum.NotFound, err)
+	}
+	return receipt, err
+}
+
+// The remaining reads are answered by the first usable endpoint, failing over to the next one on errors
+
+func (c *Client) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (*types.Block, error) {
+		return client.BlockByHash(ctx, hash)
+	})
+}
+
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (*types.Block, error) {
+		return client.BlockByNumber(ctx, number)
+	})
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
+}
+
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (*types.Header, error) {
+		return client.HeaderByHash(ctx, hash)
+	})
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, number)
+	})
+}
+
+func (c *Client) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (uint, error) {
+		return client.TransactionCount(ctx, blockHash)
+	})
+}
+
+func (c *Client) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (*types.Transaction, error) {
+		return client.TransactionInBlock(ctx, blockHash, index)
+	})
+}
+
+func (c *Client) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	type result struct {
+		tx        *types.Transaction
+		isPending bool
+	}
+	r, err := read(ctx, c, func(client ethclient.EthClientInterface) (result, error) {
+		tx, isPending, err := client.TransactionByHash(ctx, txHash)
+		return result{tx, isPending}, err
+	})
+	return r.tx, r.isPending, err
+}
+
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) ([]types.Log, error) {
+		return client.FilterLogs(ctx, q)
+	})
+}
+
+func (c *Client) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (uint64, error) {
+		return client.EstimateGas(ctx, call)
+	})
+}
+
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (*big.Int, error) {
+		return client.SuggestGasPrice(ctx)
+	})
+}
+
+func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (*big.Int, error) {
+		return client.SuggestGasTipCap(ctx)
+	})
+}
+
+func (c *Client) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (*ethereum.FeeHistory, error) {
+		return client.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+func (c *Client) PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (*big.Int, error) {
+		return client.PendingBalanceAt(ctx, account)
+	})
+}
+
+func (c *Client) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) ([]byte, error) {
+		return client.PendingStorageAt(ctx, account, key)
+	})
+}
+
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) ([]byte, error) {
+		return client.PendingCodeAt(ctx, account)
+	})
+}
+
+// PendingNonceAt returns the highest pending nonce reported by the usable endpoints, since a transaction
+// broadcast to all of them may not have reached every mempool yet
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	eps, err := c.usable()
+	if err != nil {
+		return 0, err
+	}
+
+	nonces := make([]uint64, len(eps))
+	errs := make([]error, len(eps))
+	parallel(eps, func(i int, ep *endpoint) {
+		nonces[i], errs[i] = ep.client.PendingNonceAt(ctx, account)
+		c.report(ep, errs[i])
+	})
+
+	var nonce uint64
+	answered := 0
+	for i := range eps {
+		if errs[i] == nil {
+			answered++
+			nonce = max(nonce, nonces[i])
+		}
+	}
+	if answered == 0 {
+		return 0, errors.Join(errs...)
+	}
+	return nonce, nil
+}
+
+func (c *Client) PendingTransactionCount(ctx context.Context) (uint, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) (uint, error) {
+		return client.PendingTransactionCount(ctx)
+	})
+}
+
+func (c *Client) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
+	return read(ctx, c, func(client ethclient.EthClientInterface) ([]byte, error) {
+		return client.PendingCallContract(ctx, call)
+	})
+}
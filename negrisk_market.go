@@ -0,0 +1,230 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NegRiskQuestion is a question of a neg-risk market, a binary condition prepared by the NegRiskAdapter
+type NegRiskQuestion struct {
+	Index       uint8
+	QuestionId  [32]byte
+	ConditionId [32]byte
+	// YesPositionId and NoPositionId are the token IDs of the question's outcomes
+	YesPositionId *big.Int
+	NoPositionId  *big.Int
+	// Resolved reports whether the question's condition has payouts; Outcome is then true when YES won
+	Resolved bool
+	Outcome  bool
+	// Metadata is the data of the QuestionPrepared event, nil unless read with WithMarketMetadata
+	Metadata []byte
+}
+
+// NegRiskMarket is a neg-risk market and its questions, read from the NegRiskAdapter
+type NegRiskMarket struct {
+	MarketId [32]byte
+	// Data is the packed MarketData of the adapter
+	Data    [32]byte
+	Oracle  common.Address
+	FeeBips *big.Int
+	// Determined reports whether a question of the market resolved YES; Result is then its index
+	Determined bool
+	Result     *big.Int
+	Questions  []NegRiskQuestion
+	// Metadata is the data of the MarketPrepared event, nil unless read with WithMarketMetadata
+	Metadata []byte
+}
+
+type negRiskMarketConfig struct {
+	blockNumber *big.Int
+	metadata    bool
+	fromBlock   uint64
+	blockSpan   uint64
+}
+
+// NegRiskMarketOption configures neg-risk market reads
+type NegRiskMarketOption func(c *negRiskMarketConfig)
+
+// WithMarketBlock reads the market at a specific block (default: latest)
+func WithMarketBlock(blockNumber *big.Int) NegRiskMarketOption {
+	return func(c *negRiskMarketConfig) {
+		c.blockNumber = blockNumber
+	}
+}
+
+// WithMarketMetadata reads the market and question metadata from the MarketPrepared and QuestionPrepared
+// events, scanning from fromBlock (e.g. the adapter's deployment block) until they are all found
+func WithMarketMetadata(fromBlock uint64) NegRiskMarketOption {
+	return func(c *negRiskMarketConfig) {
+		c.metadata = true
+		c.fromBlock = fromBlock
+	}
+}
+
+// WithMarketBlockSpan sets how many blocks a log query of WithMarketMetadata covers (default: 10000)
+func WithMarketBlockSpan(blocks uint64) NegRiskMarketOption {
+	return func(c *negRiskMarketConfig) {
+		c.blockSpan = blocks
+	}
+}
+
+// NegRiskMarketId returns the ID of the market of a neg-risk question, like NegRiskIdLib.getMarketId
+func NegRiskMarketId(questionId [32]byte) [32]byte {
+	marketId := questionId
+	marketId[31] = 0
+	return marketId
+}
+
+// getNegRiskMarket reads a market, its questions and their resolution from the NegRiskAdapter and the ConditionalTokens
+func (r *portfolioReader) getNegRiskMarket(ctx context.Context, marketId [32]byte, opts ...NegRiskMarketOption) (*NegRiskMarket, error) {
+	cfg := &negRiskMarketConfig{blockSpan: 10000}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.blockSpan == 0 {
+		cfg.blockSpan = 10000
+	}
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: cfg.blockNumber}
+
+	market := &NegRiskMarket{MarketId: marketId}
+	var err error
+	if market.Data, err = r.negRiskAdapter.GetMarketData(callOpts, marketId); err != nil {
+		return nil, fmt.Errorf("failed to get data of market %x: %w", marketId, err)
+	}
+	if market.Data == ([32]byte{}) {
+		return nil, fmt.Errorf("market %x is not prepared", marketId)
+	}
+	if market.Oracle, err = r.negRiskAdapter.GetOracle(callOpts, marketId); err != nil {
+		return nil, fmt.Errorf("failed to get oracle of market %x: %w", marketId, err)
+	}
+	if market.FeeBips, err = r.negRiskAdapter.GetFeeBips(callOpts, marketId); err != nil {
+		return nil, fmt.Errorf("failed to get fee of market %x: %w", marketId, err)
+	}
+	if market.Determined, err = r.negRiskAdapter.GetDetermined(callOpts, marketId); err != nil {
+		return nil, fmt.Errorf("failed to get determination of market %x: %w", marketId, err)
+	}
+	if market.Determined {
+		if market.Result, err = r.negRiskAdapter.GetResult(callOpts, marketId); err != nil {
+			return nil, fmt.Errorf("failed to get result of market %x: %w", marketId, err)
+		}
+	}
+	count, err := r.negRiskAdapter.GetQuestionCount(callOpts, marketId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question count of market %x: %w", marketId, err)
+	}
+
+	for i := 0; i < int(count.Int64()); i++ {
+		question, err := r.readNegRiskQuestion(callOpts, NegRiskQuestionId(marketId, uint8(i)))
+		if err != nil {
+			return nil, err
+		}
+		market.Questions = append(market.Questions, question)
+	}
+
+	if cfg.metadata {
+		if err := r.readNegRiskMetadata(ctx, market, cfg); err != nil {
+			return nil, err
+		}
+	}
+	return market, nil
+}
+
+// readNegRiskQuestion reads the condition, positions and resolution of a question
+func (r *portfolioReader) readNegRiskQuestion(opts *bind.CallOpts, questionId [32]byte) (NegRiskQuestion, error) {
+	question := NegRiskQuestion{
+		Index:         questionId[31],
+		QuestionId:    questionId,
+		YesPositionId: ComputeNegRiskPositionId(r.config.NegRiskAdapter, r.config.NegRiskWrappedCollateral, questionId, true),
+		NoPositionId:  ComputeNegRiskPositionId(r.config.NegRiskAdapter, r.config.NegRiskWrappedCollateral, questionId, false),
+	}
+	var err error
+	if question.ConditionId, err = r.negRiskAdapter.GetConditionId(opts, questionId); err != nil {
+		return question, fmt.Errorf("failed to get condition of question %x: %w", questionId, err)
+	}
+	denominator, err := r.ctf.PayoutDenominator(opts, question.ConditionId)
+	if err != nil {
+		return question, fmt.Errorf("failed to get payout denominator of condition %x: %w", question.ConditionId, err)
+	}
+	if denominator.Sign() == 0 {
+		return question, nil
+	}
+	yes, err := r.ctf.PayoutNumerators(opts, question.ConditionId, big.NewInt(0))
+	if err != nil {
+		return question, fmt.Errorf("failed to get YES payout of condition %x: %w", question.ConditionId, err)
+	}
+	question.Resolved = true
+	question.Outcome = yes.Sign() > 0
+	return question, nil
+}
+
+// readNegRiskMetadata fills the metadata of market and its questions from the adapter's events, scanning
+// block spans from cfg.fromBlock until every event is found or the latest block is reached
+func (r *portfolioReader) readNegRiskMetadata(ctx context.Context, market *NegRiskMarket, cfg *negRiskMarketConfig) error {
+	toBlock := uint64(0)
+	if cfg.blockNumber != nil {
+		toBlock = cfg.blockNumber.Uint64()
+	} else {
+		head, err := r.backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get latest block: %w", err)
+		}
+		toBlock = head.Number.Uint64()
+	}
+
+	marketIds := [][32]byte{market.MarketId}
+	missing := len(market.Questions) + 1
+	for start := cfg.fromBlock; start <= toBlock && missing > 0; start += cfg.blockSpan {
+		end := min(start+cfg.blockSpan-1, toBlock)
+		filterOpts := &bind.FilterOpts{Start: start, End: &end, Context: ctx}
+
+		markets, err := r.negRiskAdapter.FilterMarketPrepared(filterOpts, marketIds, nil)
+		if err != nil {
+			return fmt.Errorf("failed to filter MarketPrepared logs of blocks %d-%d: %w", start, end, err)
+		}
+		for markets.Next() {
+			if market.Metadata == nil {
+				market.Metadata = markets.Event.Data
+				missing--
+			}
+		}
+		err = markets.Error()
+		markets.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read MarketPrepared logs of blocks %d-%d: %w", start, end, err)
+		}
+
+		questions, err := r.negRiskAdapter.FilterQuestionPrepared(filterOpts, marketIds, nil)
+		if err != nil {
+			return fmt.Errorf("failed to filter QuestionPrepared logs of blocks %d-%d: %w", start, end, err)
+		}
+		for questions.Next() {
+			index := questions.Event.Index.Int64()
+			if index < int64(len(market.Questions)) && market.Questions[index].Metadata == nil {
+				market.Questions[index].Metadata = questions.Event.Data
+				missing--
+			}
+		}
+		err = questions.Error()
+		questions.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read QuestionPrepared logs of blocks %d-%d: %w", start, end, err)
+		}
+	}
+	return nil
+}
+
+// GetNegRiskMarket reads a neg-risk market from the NegRiskAdapter: its questions, their condition and position
+// IDs, and the resolution state
+func (b *ContractInterface) GetNegRiskMarket(ctx context.Context, marketId [32]byte, opts ...NegRiskMarketOption) (*NegRiskMarket, error) {
+	return b.portfolio.getNegRiskMarket(ctx, marketId, opts...)
+}
+
+// GetNegRiskMarket reads a neg-risk market from the NegRiskAdapter: its questions, their condition and position
+// IDs, and the resolution state
+func (v *ContractInterfaceV2) GetNegRiskMarket(ctx context.Context, marketId [32]byte, opts ...NegRiskMarketOption) (*NegRiskMarket, error) {
+	return v.portfolio.getNegRiskMarket(ctx, marketId, opts...)
+}
@@ -0,0 +1,99 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	negriskadapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-adapter"
+)
+
+// addPrepared adds a MarketPrepared log (questionIndex < 0) or a QuestionPrepared log of marketId at block
+func (c *portfolioChain) addPrepared(marketId [32]byte, questionIndex int, data string, block uint64) {
+	c.t.Helper()
+	parsed, err := negriskadapter.NegRiskAdapterMetaData.GetAbi()
+	if err != nil {
+		c.t.Fatalf("GetAbi: %v", err)
+	}
+	event := parsed.Events["MarketPrepared"]
+	topics := []common.Hash{event.ID, marketId, common.BytesToHash(c.market(marketId).oracle.Bytes())}
+	packed, err := event.Inputs.NonIndexed().Pack(big.NewInt(c.market(marketId).feeBips), []byte(data))
+	if questionIndex >= 0 {
+		event = parsed.Events["QuestionPrepared"]
+		topics = []common.Hash{event.ID, marketId, NegRiskQuestionId(marketId, uint8(questionIndex))}
+		packed, err = event.Inputs.NonIndexed().Pack(big.NewInt(int64(questionIndex)), []byte(data))
+	}
+	if err != nil {
+		c.t.Fatalf("Pack: %v", err)
+	}
+	c.logs = append(c.logs, types.Log{Address: MATIC_CONTRACTS.NegRiskAdapter, Topics: topics, Data: packed, BlockNumber: block})
+}
+
+func TestGetNegRiskMarket(t *testing.T) {
+	c := MATIC_CONTRACTS
+	chain := newPortfolioChain(t)
+	chain.head = 100_000
+	chain.markets[testMarketId] = &testNegRiskMarket{oracle: common.HexToAddress("0x0A"), questions: 3, feeBips: 100, determined: true, result: 1}
+	for i, payouts := range [][]int64{{0, 1}, {1, 0}} {
+		chain.payouts[ComputeConditionId(c.NegRiskAdapter, NegRiskQuestionId(testMarketId, uint8(i)), 2)] = payouts
+	}
+	reader, err := newPortfolioReader(c, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+
+	market, err := reader.getNegRiskMarket(context.Background(), testMarketId)
+	if err != nil {
+		t.Fatalf("getNegRiskMarket: %v", err)
+	}
+	if market.Oracle != common.HexToAddress("0x0A") || market.FeeBips.Int64() != 100 || !market.Determined || market.Result.Int64() != 1 || len(market.Questions) != 3 {
+		t.Fatalf("market %+v", market)
+	}
+	q := market.Questions[2]
+	questionId := NegRiskQuestionId(testMarketId, 2)
+	if q.Index != 2 || q.QuestionId != questionId || q.ConditionId != ComputeConditionId(c.NegRiskAdapter, questionId, 2) ||
+		q.YesPositionId.Cmp(ComputeNegRiskPositionId(c.NegRiskAdapter, c.NegRiskWrappedCollateral, questionId, true)) != 0 {
+		t.Errorf("question %+v", q)
+	}
+	if market.Questions[0].Outcome || !market.Questions[0].Resolved || !market.Questions[1].Outcome || market.Questions[2].Resolved {
+		t.Errorf("resolution of questions %+v", market.Questions)
+	}
+	if market.Metadata != nil || chain.logQueries != 0 {
+		t.Error("metadata read without WithMarketMetadata")
+	}
+	if NegRiskMarketId(questionId) != testMarketId {
+		t.Errorf("market of question %x", questionId)
+	}
+
+	if _, err := reader.getNegRiskMarket(context.Background(), [32]byte{0xEF}); err == nil {
+		t.Error("expected an unprepared market to fail")
+	}
+}
+
+func TestGetNegRiskMarket_Metadata(t *testing.T) {
+	chain := newPortfolioChain(t)
+	chain.head = 100_000
+	chain.markets[testMarketId] = &testNegRiskMarket{oracle: common.HexToAddress("0x0A"), questions: 2}
+	chain.addPrepared(testMarketId, -1, "market", 5)
+	chain.addPrepared(testMarketId, 0, "first", 6)
+	chain.addPrepared([32]byte{0xCD}, 0, "other market", 7)
+	chain.addPrepared(testMarketId, 1, "second", 25)
+	reader, err := newPortfolioReader(MATIC_CONTRACTS, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+
+	market, err := reader.getNegRiskMarket(context.Background(), testMarketId, WithMarketMetadata(0), WithMarketBlockSpan(10))
+	if err != nil {
+		t.Fatalf("getNegRiskMarket: %v", err)
+	}
+	if string(market.Metadata) != "market" || string(market.Questions[0].Metadata) != "first" || string(market.Questions[1].Metadata) != "second" {
+		t.Errorf("metadata %q, %q, %q", market.Metadata, market.Questions[0].Metadata, market.Questions[1].Metadata)
+	}
+	// The scan stops with the span of block 25 instead of running to the head
+	if chain.logQueries != 6 {
+		t.Errorf("%d log queries, want 6", chain.logQueries)
+	}
+}
@@ -0,0 +1,200 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+// NegRiskRedemption is the redemption of a determined neg-risk condition, built from the account's balances
+type NegRiskRedemption struct {
+	ConditionId [32]byte
+	// Amounts are the YES and NO balances, in NegRiskAdapter.redeemPositions order
+	Amounts []*big.Int
+}
+
+// indexSets returns the index sets of the outcomes with a balance: 1 for YES, 2 for NO
+func (r NegRiskRedemption) indexSets() []*big.Int {
+	var indexSets []*big.Int
+	for i, amount := range r.Amounts {
+		if amount.Sign() > 0 {
+			indexSets = append(indexSets, IndexSet(i))
+		}
+	}
+	return indexSets
+}
+
+// NegRiskRedeemReport is the outcome of redeeming neg-risk conditions from on-chain balances
+type NegRiskRedeemReport struct {
+	Account common.Address
+	// Redeemed are the redemptions sent. If redeeming fails, they only hold the ones sent before the failure.
+	Redeemed []NegRiskRedemption
+	// Undetermined are the conditions skipped because their question is not resolved yet
+	Undetermined [][32]byte
+	// TxHashes are the redemption transactions: one per condition for an EOA, a single batch for a Safe
+	TxHashes []common.Hash
+}
+
+// negRiskRedeemCallBuilder builds the call redeeming a neg-risk redemption
+type negRiskRedeemCallBuilder func(redemption NegRiskRedemption) (contractCall, error)
+
+// negRiskRedemptions reads the YES and NO balances of account for the determined conditions of conditionIds
+// through NegRiskAdapter.balanceOfBatch. Conditions without a balance are left out, undetermined ones are
+// returned separately.
+func (r *portfolioReader) negRiskRedemptions(ctx context.Context, account common.Address, conditionIds [][32]byte, opts ...PortfolioOption) (redemptions []NegRiskRedemption, undetermined [][32]byte, err error) {
+	cfg := newPortfolioConfig(opts)
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: cfg.blockNumber}
+
+	var determined [][32]byte
+	var positionIds []*big.Int
+	for _, conditionId := range conditionIds {
+		denominator, err := r.ctf.PayoutDenominator(callOpts, conditionId)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get payout denominator of condition %x: %w", conditionId, err)
+		}
+		if denominator.Sign() == 0 {
+			undetermined = append(undetermined, conditionId)
+			continue
+		}
+		// The YES and NO position IDs of NegRiskAdapter.getPositionId
+		ids, err := ComputeOutcomePositionIds(r.config.NegRiskWrappedCollateral, conditionId, 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		determined = append(determined, conditionId)
+		positionIds = append(positionIds, ids...)
+	}
+
+	balances, err := batchBalances(r.negRiskAdapter.BalanceOfBatch, callOpts, account, positionIds, cfg.batchSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, conditionId := range determined {
+		yes, no := balances[2*i], balances[2*i+1]
+		if yes.Sign() == 0 && no.Sign() == 0 {
+			continue
+		}
+		redemptions = append(redemptions, NegRiskRedemption{ConditionId: conditionId, Amounts: []*big.Int{yes, no}})
+	}
+	return redemptions, undetermined, nil
+}
+
+// redeemNegRiskFromBalances redeems the balances account holds of the determined conditions of conditionIds.
+// A Safe (safeSigner set) redeems them in one MultiSend transaction, an EOA sends a transaction per condition.
+func (r *portfolioReader) redeemNegRiskFromBalances(ctx context.Context, e *txExecutor, safeSigner signer.SafeTradingSigner, chainID *big.Int, account common.Address, conditionIds [][32]byte, build negRiskRedeemCallBuilder, opts ...PortfolioOption) (*NegRiskRedeemReport, error) {
+	redemptions, undetermined, err := r.negRiskRedemptions(ctx, account, conditionIds, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]contractCall, len(redemptions))
+	for i, redemption := range redemptions {
+		if calls[i], err = build(redemption); err != nil {
+			return nil, fmt.Errorf("failed to build redemption of condition %x: %w", redemption.ConditionId, err)
+		}
+	}
+	txHashes, err := e.executeMultiSend(ctx, safeSigner, chainID, calls)
+
+	// On a failure only the redemptions of the transactions sent are reported
+	report := &NegRiskRedeemReport{
+		Account:      account,
+		Redeemed:     redemptions[:sentCalls(safeSigner, len(calls), txHashes, err)],
+		Undetermined: undetermined,
+		TxHashes:     txHashes,
+	}
+	if err != nil {
+		return report, fmt.Errorf("failed to redeem: %w", err)
+	}
+	return report, nil
+}
+
+// GetNegRiskRedemptions returns the YES and NO balances account can redeem of the determined neg-risk conditions
+// of conditionIds, and the conditions that are not determined yet
+func (b *ContractInterface) GetNegRiskRedemptions(ctx context.Context, account common.Address, conditionIds [][32]byte, opts ...PortfolioOption) ([]NegRiskRedemption, [][32]byte, error) {
+	return b.portfolio.negRiskRedemptions(ctx, account, conditionIds, opts...)
+}
+
+// RedeemNegRiskFromBalances redeems the configured account's YES and NO balances of the determined neg-risk
+// conditions of conditionIds for USDC.e, skipping undetermined conditions
+func (b *ContractInterface) RedeemNegRiskFromBalances(ctx context.Context, conditionIds [][32]byte, opts ...PortfolioOption) (*NegRiskRedeemReport, error) {
+	switch b.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		return b.RedeemNegRiskFromBalancesForSafe(ctx, b.getSafeTradingSigner(), b.chainID, conditionIds, opts...)
+	case SignatureTypeEOA:
+		return b.RedeemNegRiskFromBalancesForEOA(ctx, b.getEOATradingSigner(), conditionIds, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported signature type: %v", b.signatureType)
+	}
+}
+
+// RedeemNegRiskFromBalancesForEOA redeems the neg-risk balances of an EOA for USDC.e, one transaction per condition
+func (b *ContractInterface) RedeemNegRiskFromBalancesForEOA(ctx context.Context, eoaSigner signer.EOATradingSigner, conditionIds [][32]byte, opts ...PortfolioOption) (*NegRiskRedeemReport, error) {
+	owner, err := b.executor.eoaAddress(eoaSigner)
+	if err != nil {
+		return nil, err
+	}
+	return b.portfolio.redeemNegRiskFromBalances(ctx, b.executor, nil, nil, owner, conditionIds, b.negRiskRedemptionCall, opts...)
+}
+
+// RedeemNegRiskFromBalancesForSafe redeems the neg-risk balances of a Safe for USDC.e in one transaction
+func (b *ContractInterface) RedeemNegRiskFromBalancesForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, conditionIds [][32]byte, opts ...PortfolioOption) (*NegRiskRedeemReport, error) {
+	safeAddr, err := b.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return b.portfolio.redeemNegRiskFromBalances(ctx, b.executor, safeSigner, chainID, safeAddr, conditionIds, b.negRiskRedemptionCall, opts...)
+}
+
+func (b *ContractInterface) negRiskRedemptionCall(redemption NegRiskRedemption) (contractCall, error) {
+	return b.redeemNegRiskCallForCollateral(CollateralUSDCE, redemption.ConditionId, redemption.Amounts)
+}
+
+// GetNegRiskRedemptions returns the YES and NO balances account can redeem of the determined neg-risk conditions
+// of conditionIds, and the conditions that are not determined yet
+func (v *ContractInterfaceV2) GetNegRiskRedemptions(ctx context.Context, account common.Address, conditionIds [][32]byte, opts ...PortfolioOption) ([]NegRiskRedemption, [][32]byte, error) {
+	return v.portfolio.negRiskRedemptions(ctx, account, conditionIds, opts...)
+}
+
+// RedeemNegRiskFromBalances redeems the configured account's YES and NO balances of the determined neg-risk
+// conditions of conditionIds for pUSD, skipping undetermined conditions
+func (v *ContractInterfaceV2) RedeemNegRiskFromBalances(ctx context.Context, conditionIds [][32]byte, opts ...PortfolioOption) (*NegRiskRedeemReport, error) {
+	switch v.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		s, err := v.getSafeTradingSignerOrErr()
+		if err != nil {
+			return nil, err
+		}
+		return v.RedeemNegRiskFromBalancesForSafe(ctx, s, v.chainID, conditionIds, opts...)
+	case SignatureTypeEOA:
+		return v.RedeemNegRiskFromBalancesForEOA(ctx, conditionIds, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported signature type: %v", v.signatureType)
+	}
+}
+
+// RedeemNegRiskFromBalancesForEOA redeems the neg-risk balances of the EOA for pUSD, one transaction per condition
+func (v *ContractInterfaceV2) RedeemNegRiskFromBalancesForEOA(ctx context.Context, conditionIds [][32]byte, opts ...PortfolioOption) (*NegRiskRedeemReport, error) {
+	eoa, err := v.getEOAAddress()
+	if err != nil {
+		return nil, err
+	}
+	return v.portfolio.redeemNegRiskFromBalances(ctx, v.executor, nil, nil, eoa, conditionIds, v.negRiskRedemptionCall, opts...)
+}
+
+// RedeemNegRiskFromBalancesForSafe redeems the neg-risk balances of a Safe for pUSD in one transaction
+func (v *ContractInterfaceV2) RedeemNegRiskFromBalancesForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, conditionIds [][32]byte, opts ...PortfolioOption) (*NegRiskRedeemReport, error) {
+	safeAddr, err := v.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return v.portfolio.redeemNegRiskFromBalances(ctx, v.executor, safeSigner, chainID, safeAddr, conditionIds, v.negRiskRedemptionCall, opts...)
+}
+
+// negRiskRedemptionCall redeems through the NegRiskCtfCollateralAdapter, which takes the held index sets
+func (v *ContractInterfaceV2) negRiskRedemptionCall(redemption NegRiskRedemption) (contractCall, error) {
+	return buildNegRiskAdapterRedeemCall(v.config.NegRiskCtfCollateralAdapter, redemption.ConditionId, redemption.indexSets())
+}
@@ -0,0 +1,72 @@
+package polymarketcontracts
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestRedeemNegRiskFromBalancesForEOA(t *testing.T) {
+	c := MATIC_CONTRACTS
+	chain := newPortfolioChain(t)
+	both, no, empty, open := [32]byte{0x01}, [32]byte{0x02}, [32]byte{0x03}, [32]byte{0x04}
+	chain.payouts[both] = []int64{1, 0}
+	chain.payouts[no] = []int64{0, 1}
+	chain.payouts[empty] = []int64{1, 0}
+	for _, conditionId := range [][32]byte{both, no, open} {
+		ids, _ := ComputeOutcomePositionIds(c.NegRiskWrappedCollateral, conditionId, 2)
+		chain.balances[ids[1].String()] = 2e6
+		if conditionId == both {
+			chain.balances[ids[0].String()] = 5e6
+		}
+	}
+	reader, err := newPortfolioReader(c, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	b, eoa := newV1ExtTestCI()
+	b.portfolio = reader
+
+	report, err := b.RedeemNegRiskFromBalancesForEOA(context.Background(), &mockEOASigner{addr: testSafe}, [][32]byte{both, no, empty, open})
+	if err != nil {
+		t.Fatalf("RedeemNegRiskFromBalancesForEOA: %v", err)
+	}
+	if chain.balanceBatches != 1 {
+		t.Errorf("%d BalanceOfBatch calls, want 1", chain.balanceBatches)
+	}
+	if len(report.Undetermined) != 1 || report.Undetermined[0] != open {
+		t.Errorf("undetermined %x, want the open condition", report.Undetermined)
+	}
+	if len(report.Redeemed) != 2 || report.Redeemed[0].Amounts[0].Int64() != 5e6 || report.Redeemed[0].Amounts[1].Int64() != 2e6 ||
+		report.Redeemed[1].ConditionId != no || report.Redeemed[1].Amounts[0].Sign() != 0 {
+		t.Errorf("redeemed %+v", report.Redeemed)
+	}
+	if eoa.calls != 2 || len(report.TxHashes) != 2 {
+		t.Errorf("%d transactions, report %+v", eoa.calls, report)
+	}
+	want, _ := buildRedeemNegRiskCall(c.NegRiskAdapter, no, []*big.Int{big.NewInt(0), big.NewInt(2e6)})
+	if eoa.lastTo != c.NegRiskAdapter || !bytes.Equal(eoa.lastData, want.Calldata) {
+		t.Errorf("last redemption to %s: %x", eoa.lastTo.Hex(), eoa.lastData)
+	}
+
+	// Only the redemptions sent before a failure are reported
+	b.executor.txSender = &failAfterSender{addrSender: addrSender{addr: testSafe}, ok: 1}
+	report, err = b.RedeemNegRiskFromBalancesForEOA(context.Background(), &mockEOASigner{addr: testSafe}, [][32]byte{both, no, empty, open})
+	if err == nil || len(report.Redeemed) != 1 || report.Redeemed[0].ConditionId != both || len(report.TxHashes) != 1 {
+		t.Errorf("got %v, report %+v", err, report)
+	}
+}
+
+func TestV2NegRiskRedemptionCall_HeldIndexSets(t *testing.T) {
+	v := newV2TestInstance(nil)
+	redemption := NegRiskRedemption{ConditionId: testCondID, Amounts: []*big.Int{big.NewInt(0), big.NewInt(3)}}
+	call, err := v.negRiskRedemptionCall(redemption)
+	if err != nil {
+		t.Fatalf("negRiskRedemptionCall: %v", err)
+	}
+	want, _ := buildNegRiskAdapterRedeemCall(MATIC_CONTRACTS.NegRiskCtfCollateralAdapter, testCondID, []*big.Int{big.NewInt(2)})
+	if call.Target != MATIC_CONTRACTS.NegRiskCtfCollateralAdapter || !bytes.Equal(call.Calldata, want.Calldata) {
+		t.Errorf("call %+v", call)
+	}
+}
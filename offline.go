@@ -0,0 +1,34 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ivanzzeth/ethsig/eip712"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/offline"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+// exportSafeTx adds a Safe transaction to the bundle of exporter instead of signing and executing it.
+// It returns a zero hash, like every exported transaction.
+func exportSafeTx(ctx context.Context, exporter *offline.ExportingSender, typedData eip712.TypedData, safeAddr, to common.Address, value *big.Int, data []byte, operation SafeOperation, safeTxGas, baseGas, gasPrice *big.Int, gasToken, refundReceiver common.Address, nonce *big.Int, opts ...sender.SendOption) (common.Hash, error) {
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	return common.Hash{}, exporter.ExportSafeTransaction(ctx, &offline.SafeTx{
+		Safe:           safeAddr,
+		To:             to,
+		Value:          (*hexutil.Big)(value),
+		Data:           common.CopyBytes(data),
+		Operation:      uint8(operation),
+		SafeTxGas:      (*hexutil.Big)(safeTxGas),
+		BaseGas:        (*hexutil.Big)(baseGas),
+		GasPrice:       (*hexutil.Big)(gasPrice),
+		GasToken:       gasToken,
+		RefundReceiver: refundReceiver,
+		Nonce:          (*hexutil.Big)(nonce),
+		TypedData:      typedData,
+	}, opts...)
+}
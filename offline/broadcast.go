@@ -0,0 +1,100 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/retry"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
+)
+
+// BroadcastClient is the chain access needed to broadcast a signed bundle and wait for it
+type BroadcastClient interface {
+	revert.Replayer
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+type broadcastConfig struct {
+	pollInterval time.Duration
+}
+
+// BroadcastOption configures Broadcast
+type BroadcastOption func(c *broadcastConfig)
+
+// WithReceiptPollInterval sets how often receipts are polled (default: 2 seconds)
+func WithReceiptPollInterval(d time.Duration) BroadcastOption {
+	return func(c *broadcastConfig) {
+		c.pollInterval = d
+	}
+}
+
+// Broadcast submits the signed transactions of the bundle in order and waits for their receipts.
+// It can be resumed: transactions that are already mined are not sent again, and nodes reporting a
+// transaction as already known count as sent. A reverted transaction stops the broadcast with an
+// error decoded by package revert; the receipts gathered so far are returned with it.
+func Broadcast(ctx context.Context, client BroadcastClient, bundle *Bundle, opts ...BroadcastOption) ([]*types.Receipt, error) {
+	cfg := &broadcastConfig{pollInterval: 2 * time.Second}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	bundle.mu.Lock()
+	txs := append([]*Transaction(nil), bundle.Transactions...)
+	bundle.mu.Unlock()
+
+	for i, t := range txs {
+		if !t.IsSigned() {
+			return nil, fmt.Errorf("transaction %d from %s is not signed", i, t.Tx.From.Hex())
+		}
+	}
+
+	receipts := make([]*types.Receipt, 0, len(txs))
+	for i, t := range txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(t.Signed); err != nil {
+			return receipts, fmt.Errorf("transaction %d: failed to decode signed transaction: %w", i, err)
+		}
+
+		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return receipts, fmt.Errorf("transaction %d: failed to get receipt: %w", i, err)
+		}
+		if receipt == nil {
+			if err := retry.Broadcast(ctx, retry.DefaultPolicy, client, tx); err != nil {
+				return receipts, fmt.Errorf("transaction %d: failed to send transaction: %w", i, err)
+			}
+			if receipt, err = waitReceipt(ctx, client, tx.Hash(), cfg.pollInterval); err != nil {
+				return receipts, fmt.Errorf("transaction %d: %w", i, err)
+			}
+		}
+
+		receipts = append(receipts, receipt)
+		if err := revert.ReceiptError(ctx, client, receipt); err != nil {
+			return receipts, fmt.Errorf("transaction %d: %w", i, err)
+		}
+	}
+	return receipts, nil
+}
+
+func waitReceipt(ctx context.Context, client BroadcastClient, txHash common.Hash, pollInterval time.Duration) (*types.Receipt, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == nil && receipt != nil {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("tx %s not mined: %w", txHash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
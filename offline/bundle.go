@@ -0,0 +1,195 @@
+// Package offline implements air-gapped signing: an online machine exports unsigned transactions and Safe
+// typed data to a portable JSON bundle, an offline machine signs it, and the online machine broadcasts it later.
+package offline
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ivanzzeth/ethsig/eip712"
+)
+
+// BundleVersion is the version of the bundle format written by this package
+const BundleVersion = 1
+
+// UnsignedTx is everything needed to sign a transaction without network access
+type UnsignedTx struct {
+	From  common.Address `json:"from"`
+	Nonce hexutil.Uint64 `json:"nonce"`
+	Gas   hexutil.Uint64 `json:"gas"`
+	// GasPrice is set for legacy transactions, GasFeeCap and GasTipCap for EIP-1559 transactions
+	GasPrice  *hexutil.Big   `json:"gasPrice,omitempty"`
+	GasFeeCap *hexutil.Big   `json:"maxFeePerGas,omitempty"`
+	GasTipCap *hexutil.Big   `json:"maxPriorityFeePerGas,omitempty"`
+	To        common.Address `json:"to"`
+	Value     *hexutil.Big   `json:"value"`
+	Data      hexutil.Bytes  `json:"data,omitempty"`
+}
+
+// Transaction returns the unsigned transaction for chainID
+func (u *UnsignedTx) Transaction(chainID *big.Int) *types.Transaction {
+	to := u.To
+	value := new(big.Int)
+	if u.Value != nil {
+		value = u.Value.ToInt()
+	}
+	if u.GasFeeCap != nil {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     uint64(u.Nonce),
+			GasTipCap: u.GasTipCap.ToInt(),
+			GasFeeCap: u.GasFeeCap.ToInt(),
+			Gas:       uint64(u.Gas),
+			To:        &to,
+			Value:     value,
+			Data:      u.Data,
+		})
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    uint64(u.Nonce),
+		GasPrice: u.GasPrice.ToInt(),
+		Gas:      uint64(u.Gas),
+		To:       &to,
+		Value:    value,
+		Data:     u.Data,
+	})
+}
+
+// SafeTx is a Safe transaction to be signed by its owner. The outer execTransaction is built
+// once the typed data is signed, since it carries the signature.
+type SafeTx struct {
+	Safe           common.Address `json:"safe"`
+	To             common.Address `json:"to"`
+	Value          *hexutil.Big   `json:"value"`
+	Data           hexutil.Bytes  `json:"data,omitempty"`
+	Operation      uint8          `json:"operation"`
+	SafeTxGas      *hexutil.Big   `json:"safeTxGas"`
+	BaseGas        *hexutil.Big   `json:"baseGas"`
+	GasPrice       *hexutil.Big   `json:"gasPrice"`
+	GasToken       common.Address `json:"gasToken"`
+	RefundReceiver common.Address `json:"refundReceiver"`
+	Nonce          *hexutil.Big   `json:"nonce"`
+
+	TypedData eip712.TypedData `json:"typedData"`
+	Signature hexutil.Bytes    `json:"signature,omitempty"`
+}
+
+// Transaction is one bundle entry: a plain transaction, or a Safe transaction and the outer transaction executing it
+type Transaction struct {
+	Tx   UnsignedTx `json:"tx"`
+	Safe *SafeTx    `json:"safe,omitempty"`
+
+	// Signed is the RLP-encoded signed transaction, set by SignBundle
+	Signed hexutil.Bytes `json:"signed,omitempty"`
+	Hash   common.Hash   `json:"hash,omitempty"`
+}
+
+// IsSigned reports whether the transaction was signed
+func (t *Transaction) IsSigned() bool {
+	return len(t.Signed) > 0
+}
+
+// Bundle is a portable set of transactions for one chain, in the order they must be broadcast.
+// It is safe for concurrent use by multiple goroutines.
+type Bundle struct {
+	Version      int            `json:"version"`
+	ChainID      *hexutil.Big   `json:"chainId"`
+	Transactions []*Transaction `json:"transactions"`
+
+	mu         sync.Mutex
+	nonces     map[common.Address]uint64
+	safeNonces map[common.Address]*big.Int
+}
+
+// NewBundle creates an empty bundle for chainID
+func NewBundle(chainID *big.Int) *Bundle {
+	return &Bundle{Version: BundleVersion, ChainID: (*hexutil.Big)(new(big.Int).Set(chainID))}
+}
+
+// ReadBundleFile reads a bundle written by WriteFile
+func ReadBundleFile(path string) (*Bundle, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	b := &Bundle{}
+	if err := json.Unmarshal(raw, b); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+	if b.Version != BundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+	if b.ChainID == nil {
+		return nil, fmt.Errorf("bundle has no chain id")
+	}
+	return b, nil
+}
+
+// WriteFile writes the bundle as indented JSON
+func (b *Bundle) WriteFile(path string) error {
+	b.mu.Lock()
+	raw, err := json.MarshalIndent(b, "", "  ")
+	b.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of transactions in the bundle
+func (b *Bundle) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.Transactions)
+}
+
+func (b *Bundle) add(tx *Transaction) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.Transactions = append(b.Transactions, tx)
+}
+
+// nextNonce returns the nonce for the next transaction of from: the chain's pending nonce,
+// or one more than the last nonce exported for from if that is higher
+func (b *Bundle) nextNonce(from common.Address, pending uint64) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.nonces == nil {
+		b.nonces = make(map[common.Address]uint64)
+	}
+	nonce := pending
+	if next, ok := b.nonces[from]; ok && next > nonce {
+		nonce = next
+	}
+	b.nonces[from] = nonce + 1
+	return nonce
+}
+
+// NextSafeNonce returns the Safe nonce for the next Safe transaction of safe exported to this bundle:
+// the Safe's current nonce, or one more than the last one exported if that is higher
+func (b *Bundle) NextSafeNonce(safe common.Address, current *big.Int) *big.Int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.safeNonces == nil {
+		b.safeNonces = make(map[common.Address]*big.Int)
+	}
+	nonce := new(big.Int).Set(current)
+	if next, ok := b.safeNonces[safe]; ok && next.Cmp(nonce) > 0 {
+		nonce.Set(next)
+	}
+	b.safeNonces[safe] = new(big.Int).Add(nonce, big.NewInt(1))
+	return nonce
+}
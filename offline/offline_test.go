@@ -0,0 +1,175 @@
+package offline
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ivanzzeth/ethsig"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+var testChainID = big.NewInt(137)
+
+type mockClient struct {
+	pendingNonce uint64
+	receipts     map[common.Hash]*types.Receipt
+	sent         []*types.Transaction
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{receipts: make(map[common.Hash]*types.Receipt)}
+}
+
+func (c *mockClient) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return big.NewInt(100), nil
+}
+func (c *mockClient) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	return c.pendingNonce, nil
+}
+func (c *mockClient) EstimateGas(_ context.Context, _ ethereum.CallMsg) (uint64, error) {
+	return 50000, nil
+}
+
+// SendTransaction mines the transaction immediately
+func (c *mockClient) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	c.sent = append(c.sent, tx)
+	c.receipts[tx.Hash()] = &types.Receipt{TxHash: tx.Hash(), Status: types.ReceiptStatusSuccessful}
+	return nil
+}
+func (c *mockClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if r, ok := c.receipts[txHash]; ok {
+		return r, nil
+	}
+	return nil, ethereum.NotFound
+}
+func (c *mockClient) TransactionByHash(_ context.Context, _ common.Hash) (*types.Transaction, bool, error) {
+	return nil, false, ethereum.NotFound
+}
+func (c *mockClient) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func newOfflineSigner(t *testing.T) *ethsig.EthPrivateKeySigner {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return ethsig.NewEthPrivateKeySigner(key)
+}
+
+func TestExportSignBroadcast(t *testing.T) {
+	ctx := context.Background()
+	offlineSigner := newOfflineSigner(t)
+	client := newMockClient()
+	client.pendingNonce = 7
+
+	bundle := NewBundle(testChainID)
+	exporter := NewExportingSender(client, offlineSigner.GetAddress(), bundle)
+	to := common.HexToAddress("0x1111")
+
+	hash, err := exporter.SendEthereumTransactionWithContext(ctx, to, []byte{0x01}, big.NewInt(0))
+	if err != nil || hash != (common.Hash{}) {
+		t.Fatalf("expected a zero hash, got %s, %v", hash.Hex(), err)
+	}
+	if _, err := exporter.SendEthereumTransactionWithContext(ctx, to, []byte{0x02}, big.NewInt(0), sender.WithFeeCaps(big.NewInt(300), big.NewInt(30))); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if len(client.sent) != 0 {
+		t.Fatal("exporting must not broadcast")
+	}
+
+	// Move the bundle across the air gap
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	if err := bundle.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	bundle, err = ReadBundleFile(path)
+	if err != nil {
+		t.Fatalf("ReadBundleFile: %v", err)
+	}
+	first, second := bundle.Transactions[0].Tx, bundle.Transactions[1].Tx
+	if first.Nonce != 7 || second.Nonce != 8 || first.Gas != 50000 || first.GasPrice.ToInt().Int64() != 130 {
+		t.Errorf("unexpected unsigned transactions: %+v %+v", first, second)
+	}
+
+	if _, err := Broadcast(ctx, client, bundle); err == nil {
+		t.Fatal("expected an error broadcasting an unsigned bundle")
+	}
+	signed, err := SignBundle(bundle, offlineSigner)
+	if err != nil || signed != 2 {
+		t.Fatalf("SignBundle: signed %d, %v", signed, err)
+	}
+	if signed, _ := SignBundle(bundle, offlineSigner); signed != 0 {
+		t.Errorf("expected signed transactions to be skipped, signed %d", signed)
+	}
+
+	receipts, err := Broadcast(ctx, client, bundle, WithReceiptPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Broadcast: %v", err)
+	}
+	if len(receipts) != 2 || len(client.sent) != 2 {
+		t.Fatalf("expected 2 receipts and 2 broadcasts, got %d and %d", len(receipts), len(client.sent))
+	}
+	if client.sent[1].Type() != types.DynamicFeeTxType || client.sent[0].Nonce() != 7 {
+		t.Errorf("unexpected broadcast transactions")
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(testChainID), client.sent[0])
+	if err != nil || from != offlineSigner.GetAddress() {
+		t.Errorf("expected transactions signed by %s, got %s", offlineSigner.GetAddress().Hex(), from.Hex())
+	}
+
+	// Resuming does not send mined transactions again
+	if _, err := Broadcast(ctx, client, bundle); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if len(client.sent) != 2 {
+		t.Errorf("expected no new broadcast on resume, got %d", len(client.sent))
+	}
+}
+
+func TestSignBundle_SkipsOtherAccounts(t *testing.T) {
+	bundle := NewBundle(testChainID)
+	exporter := NewExportingSender(newMockClient(), common.HexToAddress("0xother"), bundle)
+	if _, err := exporter.SendEthereumTransaction(common.HexToAddress("0x1111"), nil, big.NewInt(0)); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	signed, err := SignBundle(bundle, newOfflineSigner(t))
+	if err != nil || signed != 0 {
+		t.Fatalf("expected nothing signed, got %d, %v", signed, err)
+	}
+}
+
+func TestNextSafeNonce(t *testing.T) {
+	bundle := NewBundle(testChainID)
+	safe := common.HexToAddress("0x5afe")
+
+	if n := bundle.NextSafeNonce(safe, big.NewInt(3)); n.Int64() != 3 {
+		t.Errorf("expected 3, got %s", n)
+	}
+	if n := bundle.NextSafeNonce(safe, big.NewInt(3)); n.Int64() != 4 {
+		t.Errorf("expected 4, got %s", n)
+	}
+	if n := bundle.NextSafeNonce(safe, big.NewInt(9)); n.Int64() != 9 {
+		t.Errorf("expected the chain nonce 9 when higher, got %s", n)
+	}
+}
+
+func TestAsExportingSender(t *testing.T) {
+	exporter := NewExportingSender(newMockClient(), common.Address{}, NewBundle(testChainID))
+	if got, ok := AsExportingSender(sender.AsContextTransactionSender(exporter)); !ok || got != exporter {
+		t.Error("expected to find the exporting sender")
+	}
+	var plain sender.TransactionSender = &ExportingSender{}
+	if _, ok := AsExportingSender(plain); !ok {
+		t.Error("expected a direct ExportingSender to be found")
+	}
+}
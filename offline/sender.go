@@ -0,0 +1,192 @@
+package offline
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+// Client is the chain access needed to fill in unsigned transactions
+type Client interface {
+	ethereum.GasPricer
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+}
+
+// ExportingSender is a sender.ContextTransactionSender that never signs or broadcasts: every transaction
+// is filled in (nonce, gas, fees) and appended to a Bundle. Send methods return a zero hash.
+//
+// Used as the transaction sender of a Safe trading signer, Safe executions are exported as typed data
+// to be signed together with the outer transaction (see SignBundle).
+type ExportingSender struct {
+	client    Client
+	from      common.Address
+	bundle    *Bundle
+	gasPricer sender.GasPricer
+}
+
+// ExportingSenderOption configures optional fields on ExportingSender
+type ExportingSenderOption func(s *ExportingSender)
+
+// WithGasPricer sets the GasPricer choosing fees when none are passed per call (default: sender.NewDefaultGasPricer)
+func WithGasPricer(pricer sender.GasPricer) ExportingSenderOption {
+	return func(s *ExportingSender) {
+		s.gasPricer = pricer
+	}
+}
+
+// NewExportingSender creates an ExportingSender exporting transactions from the offline account from to bundle
+func NewExportingSender(client Client, from common.Address, bundle *Bundle, opts ...ExportingSenderOption) *ExportingSender {
+	s := &ExportingSender{client: client, from: from, bundle: bundle}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.gasPricer == nil {
+		s.gasPricer = sender.NewDefaultGasPricer(client)
+	}
+	return s
+}
+
+// GetAddress returns the offline account
+func (s *ExportingSender) GetAddress() common.Address {
+	return s.from
+}
+
+// Bundle returns the bundle transactions are exported to
+func (s *ExportingSender) Bundle() *Bundle {
+	return s.bundle
+}
+
+// SendEthereumTransaction exports a transaction to the bundle
+func (s *ExportingSender) SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error) {
+	return s.SendEthereumTransactionWithContext(context.Background(), to, data, value)
+}
+
+// SendEthereumTransactionWithContext exports a transaction to the bundle, honoring per-call options.
+// Gas is estimated against current chain state, so a transaction depending on an earlier one of the same
+// bundle (e.g. on an approval) needs WithGasLimit and WithNoEstimate.
+func (s *ExportingSender) SendEthereumTransactionWithContext(ctx context.Context, to common.Address, data []byte, value *big.Int, opts ...sender.SendOption) (common.Hash, error) {
+	o := sender.ApplySendOptions(opts...)
+	if err := o.Validate(); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid send options: %w", err)
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	tx, err := s.unsignedTx(ctx, to, data, value, o)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if !o.NoEstimate {
+		estimated, err := s.client.EstimateGas(ctx, ethereum.CallMsg{From: s.from, To: &to, Value: value, Data: data})
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to estimate gas: %w", revert.Wrap(&to, err))
+		}
+		if tx.Gas == 0 {
+			tx.Gas = hexutil.Uint64(estimated)
+		}
+	}
+	if err := s.assignNonce(ctx, tx, o); err != nil {
+		return common.Hash{}, err
+	}
+
+	s.bundle.add(&Transaction{Tx: *tx})
+	return common.Hash{}, nil
+}
+
+// ExportSafeTransaction exports a Safe transaction whose outer execTransaction is sent by this sender.
+// safeTx.Nonce must come from Bundle.NextSafeNonce. Without WithGasLimit, the outer gas limit is derived
+// from safeTx.SafeTxGas, since execTransaction cannot be estimated before the typed data is signed.
+func (s *ExportingSender) ExportSafeTransaction(ctx context.Context, safeTx *SafeTx, opts ...sender.SendOption) error {
+	o := sender.ApplySendOptions(opts...)
+	if err := o.Validate(); err != nil {
+		return fmt.Errorf("invalid send options: %w", err)
+	}
+
+	tx, err := s.unsignedTx(ctx, safeTx.Safe, nil, big.NewInt(0), o)
+	if err != nil {
+		return err
+	}
+	if tx.Gas == 0 {
+		tx.Gas = hexutil.Uint64(safeExecGas(safeTx))
+	}
+	if err := s.assignNonce(ctx, tx, o); err != nil {
+		return err
+	}
+
+	s.bundle.add(&Transaction{Tx: *tx, Safe: safeTx})
+	return nil
+}
+
+// unsignedTx fills in the fees of a transaction; gas and nonce are left to the caller
+func (s *ExportingSender) unsignedTx(ctx context.Context, to common.Address, data []byte, value *big.Int, o *sender.SendOptions) (*UnsignedTx, error) {
+	tx := &UnsignedTx{
+		From:  s.from,
+		Gas:   hexutil.Uint64(o.GasLimit),
+		To:    to,
+		Value: (*hexutil.Big)(value),
+		Data:  common.CopyBytes(data),
+	}
+
+	gasPrice, gasFeeCap, gasTipCap := o.GasPrice, o.GasFeeCap, o.GasTipCap
+	if gasPrice == nil && gasFeeCap == nil {
+		fees, err := s.gasPricer.GasFees(ctx, o.Urgency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to price gas: %w", err)
+		}
+		gasPrice, gasFeeCap, gasTipCap = fees.GasPrice, fees.GasFeeCap, fees.GasTipCap
+	}
+	if gasFeeCap != nil {
+		tx.GasFeeCap, tx.GasTipCap = (*hexutil.Big)(gasFeeCap), (*hexutil.Big)(gasTipCap)
+	} else {
+		tx.GasPrice = (*hexutil.Big)(gasPrice)
+	}
+	return tx, nil
+}
+
+func (s *ExportingSender) assignNonce(ctx context.Context, tx *UnsignedTx, o *sender.SendOptions) error {
+	if o.Nonce != nil {
+		tx.Nonce = hexutil.Uint64(*o.Nonce)
+		return nil
+	}
+	pending, err := s.client.PendingNonceAt(ctx, s.from)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+	tx.Nonce = hexutil.Uint64(s.bundle.nextNonce(s.from, pending))
+	return nil
+}
+
+// safeExecGas is a conservative gas limit for execTransaction: the Safe requires
+// gasleft() >= max(safeTxGas * 64 / 63, safeTxGas + 2500) + 500, on top of the intrinsic
+// gas, calldata and its own signature checks and events.
+func safeExecGas(safeTx *SafeTx) uint64 {
+	const safeOverhead = 60_000
+	safeTxGas := safeTx.SafeTxGas.ToInt().Uint64()
+	inner := max(safeTxGas*64/63, safeTxGas+2500) + 500
+	calldata := uint64(len(safeTx.Data)+65+13*32) * params.TxDataNonZeroGasEIP2028
+	return params.TxGas + calldata + safeOverhead + inner
+}
+
+// AsExportingSender returns the ExportingSender behind s, looking through wrapping senders
+func AsExportingSender(s sender.TransactionSender) (*ExportingSender, bool) {
+	for s != nil {
+		if e, ok := s.(*ExportingSender); ok {
+			return e, true
+		}
+		u, ok := s.(sender.Unwrapper)
+		if !ok {
+			break
+		}
+		s = u.TransactionSender()
+	}
+	return nil, false
+}
@@ -0,0 +1,129 @@
+package offline
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ivanzzeth/ethsig"
+	"github.com/ivanzzeth/ethsig/eip712"
+	gnosissafel2 "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/gnosis-safe-l2"
+)
+
+// Signer signs bundle transactions offline
+type Signer interface {
+	ethsig.AddressGetter
+	ethsig.TypedDataSigner
+	ethsig.TransactionSigner
+}
+
+// SignBundle signs, without network access, every unsigned transaction of the bundle sent by signer.
+// For Safe transactions the typed data is checked against the transaction fields and signed first,
+// then the outer execTransaction is built with the signature and signed. It returns how many
+// transactions were signed.
+func SignBundle(bundle *Bundle, signer Signer) (int, error) {
+	bundle.mu.Lock()
+	defer bundle.mu.Unlock()
+
+	chainID := bundle.ChainID.ToInt()
+	from := signer.GetAddress()
+	signed := 0
+	for i, t := range bundle.Transactions {
+		if t.IsSigned() || t.Tx.From != from {
+			continue
+		}
+		if t.Safe != nil {
+			data, err := signSafeTx(chainID, t, signer)
+			if err != nil {
+				return signed, fmt.Errorf("transaction %d: %w", i, err)
+			}
+			t.Tx.Data = data
+		}
+
+		tx, err := signer.SignTransactionWithChainID(t.Tx.Transaction(chainID), chainID)
+		if err != nil {
+			return signed, fmt.Errorf("transaction %d: failed to sign transaction: %w", i, err)
+		}
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return signed, fmt.Errorf("transaction %d: failed to encode signed transaction: %w", i, err)
+		}
+		t.Signed, t.Hash = raw, tx.Hash()
+		signed++
+	}
+	return signed, nil
+}
+
+// signSafeTx signs the Safe typed data of t and returns the execTransaction calldata carrying the signature
+func signSafeTx(chainID *big.Int, t *Transaction, signer Signer) ([]byte, error) {
+	s := t.Safe
+	if t.Tx.To != s.Safe || t.Tx.Value.ToInt().Sign() != 0 {
+		return nil, fmt.Errorf("outer transaction does not call Safe %s", s.Safe.Hex())
+	}
+	if err := checkTypedData(chainID, s); err != nil {
+		return nil, err
+	}
+
+	signature, err := signer.SignTypedData(s.TypedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign Safe transaction: %w", err)
+	}
+	hash, _, err := eip712.TypedDataAndHash(s.TypedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute typed data hash: %w", err)
+	}
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("unexpected Safe signature length %d", len(signature))
+	}
+	sig := common.CopyBytes(signature)
+	sig[64] = ethsig.DenormalizeV(sig[64])
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != signer.GetAddress() {
+		return nil, fmt.Errorf("Safe signature does not recover to %s", signer.GetAddress().Hex())
+	}
+	s.Signature = signature
+
+	safeABI, err := gnosissafel2.GnosisSafeL2MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe ABI: %w", err)
+	}
+	data, err := safeABI.Pack("execTransaction", s.To, s.Value.ToInt(), []byte(s.Data), s.Operation,
+		s.SafeTxGas.ToInt(), s.BaseGas.ToInt(), s.GasPrice.ToInt(), s.GasToken, s.RefundReceiver, signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack execTransaction: %w", err)
+	}
+	return data, nil
+}
+
+// checkTypedData makes sure the typed data that gets signed describes the Safe transaction that gets executed
+func checkTypedData(chainID *big.Int, s *SafeTx) error {
+	td := s.TypedData
+	if td.PrimaryType != "SafeTx" {
+		return fmt.Errorf("unexpected typed data primary type %q", td.PrimaryType)
+	}
+	if td.Domain.ChainId != chainID.String() || !strings.EqualFold(td.Domain.VerifyingContract, s.Safe.Hex()) {
+		return fmt.Errorf("typed data domain does not match Safe %s on chain %s", s.Safe.Hex(), chainID)
+	}
+
+	expected := map[string]string{
+		"to":             s.To.Hex(),
+		"value":          s.Value.ToInt().String(),
+		"data":           fmt.Sprintf("0x%x", []byte(s.Data)),
+		"operation":      fmt.Sprintf("%d", s.Operation),
+		"safeTxGas":      s.SafeTxGas.ToInt().String(),
+		"baseGas":        s.BaseGas.ToInt().String(),
+		"gasPrice":       s.GasPrice.ToInt().String(),
+		"gasToken":       s.GasToken.Hex(),
+		"refundReceiver": s.RefundReceiver.Hex(),
+		"nonce":          s.Nonce.ToInt().String(),
+	}
+	for field, want := range expected {
+		got, _ := td.Message[field].(string)
+		if !strings.EqualFold(got, want) {
+			return fmt.Errorf("typed data field %s is %q, transaction has %q", field, got, want)
+		}
+	}
+	return nil
+}
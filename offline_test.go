@@ -0,0 +1,72 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ivanzzeth/ethsig"
+	gnosissafel2 "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/gnosis-safe-l2"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/offline"
+)
+
+type exportClient struct{}
+
+func (exportClient) SuggestGasPrice(context.Context) (*big.Int, error)              { return big.NewInt(100), nil }
+func (exportClient) PendingNonceAt(context.Context, common.Address) (uint64, error) { return 4, nil }
+func (exportClient) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error)  { return 50000, nil }
+
+func exportTestSafeTx(t *testing.T, owner common.Address, bundle *offline.Bundle) common.Address {
+	t.Helper()
+	safeAddr := common.HexToAddress("0x5afe")
+	exporter := offline.NewExportingSender(exportClient{}, owner, bundle)
+
+	nonce := bundle.NextSafeNonce(safeAddr, big.NewInt(2))
+	zero := big.NewInt(0)
+	typedData := BuildSafeTransactionTypedData(big.NewInt(137), safeAddr, testCTF, zero, []byte{0xAB}, SafeOperationCall, big.NewInt(80000), zero, zero, common.Address{}, common.Address{}, nonce)
+	if _, err := exportSafeTx(context.Background(), exporter, typedData, safeAddr, testCTF, zero, []byte{0xAB}, SafeOperationCall, big.NewInt(80000), zero, zero, common.Address{}, common.Address{}, nonce); err != nil {
+		t.Fatalf("exportSafeTx: %v", err)
+	}
+	return safeAddr
+}
+
+func TestExportSafeTx_SignOffline(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	offlineSigner := ethsig.NewEthPrivateKeySigner(key)
+	bundle := offline.NewBundle(big.NewInt(137))
+	safeAddr := exportTestSafeTx(t, offlineSigner.GetAddress(), bundle)
+
+	entry := bundle.Transactions[0]
+	if entry.Safe == nil || entry.Tx.To != safeAddr || entry.Tx.Nonce != 4 || entry.Tx.Gas == 0 || len(entry.Tx.Data) != 0 {
+		t.Fatalf("unexpected exported Safe transaction: %+v", entry.Tx)
+	}
+
+	if signed, err := offline.SignBundle(bundle, offlineSigner); err != nil || signed != 1 {
+		t.Fatalf("SignBundle: signed %d, %v", signed, err)
+	}
+
+	safeABI, _ := gnosissafel2.GnosisSafeL2MetaData.GetAbi()
+	args, err := safeABI.Methods["execTransaction"].Inputs.Unpack(entry.Tx.Data[4:])
+	if err != nil {
+		t.Fatalf("unpack execTransaction: %v", err)
+	}
+	if args[0].(common.Address) != testCTF || string(args[9].([]byte)) != string(entry.Safe.Signature) {
+		t.Error("execTransaction must carry the Safe call and its signature")
+	}
+}
+
+func TestExportSafeTx_RejectsTamperedBundle(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	offlineSigner := ethsig.NewEthPrivateKeySigner(key)
+	bundle := offline.NewBundle(big.NewInt(137))
+	exportTestSafeTx(t, offlineSigner.GetAddress(), bundle)
+
+	// The executed call no longer matches what the typed data shows the signer
+	bundle.Transactions[0].Safe.To = common.HexToAddress("0xbad")
+	if _, err := offline.SignBundle(bundle, offlineSigner); err == nil {
+		t.Fatal("expected SignBundle to reject typed data that does not match the Safe transaction")
+	}
+}
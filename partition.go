@@ -0,0 +1,141 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	conditional_tokens "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/conditional-tokens"
+)
+
+// ErrInvalidPartition is returned for a partition that is empty, overlapping or incomplete, or has
+// index sets outside the condition's outcomes
+var ErrInvalidPartition = errors.New("invalid partition")
+
+// ErrAdapterNotBinary is returned for pUSD splits, merges and redeems of regular markets that are not along the
+// two outcomes of a binary condition: the CtfCollateralAdapter ignores the partition and always uses [1, 2]
+var ErrAdapterNotBinary = errors.New("CtfCollateralAdapter only supports the two outcomes of a binary condition")
+
+type positionConfig struct {
+	partition []*big.Int
+}
+
+// PositionOption configures the partition of Split and Merge and the index sets of Redeem
+type PositionOption func(c *positionConfig)
+
+// WithPartition splits or merges along custom index sets, e.g. {A} and {B, C} of a three-outcome condition
+// is WithPartition(IndexSet(0), IndexSet(1, 2)). For Redeem it selects the index sets to redeem.
+// Default: every outcome on its own.
+func WithPartition(indexSets ...*big.Int) PositionOption {
+	return func(c *positionConfig) {
+		c.partition = indexSets
+	}
+}
+
+// IndexSet returns the index set of outcome slots
+func IndexSet(slots ...int) *big.Int {
+	indexSet := new(big.Int)
+	for _, slot := range slots {
+		indexSet.SetBit(indexSet, slot, 1)
+	}
+	return indexSet
+}
+
+// FullPartition returns the partition of a condition into its single outcomes: 1, 2, 4, ...
+func FullPartition(outcomeSlotCount int) []*big.Int {
+	partition := make([]*big.Int, outcomeSlotCount)
+	for i := range partition {
+		partition[i] = IndexSet(i)
+	}
+	return partition
+}
+
+// ValidatePartition checks that partition has disjoint, non-empty index sets of a condition with
+// outcomeSlotCount outcomes. A complete partition must also cover every outcome and have at least two
+// index sets, as splitting and merging collateral requires.
+func ValidatePartition(partition []*big.Int, outcomeSlotCount int, complete bool) error {
+	if len(partition) == 0 {
+		return fmt.Errorf("%w: no index sets", ErrInvalidPartition)
+	}
+	fullIndexSet := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(outcomeSlotCount)), big.NewInt(1))
+	union := new(big.Int)
+	for _, indexSet := range partition {
+		if indexSet == nil || indexSet.Sign() <= 0 || indexSet.Cmp(fullIndexSet) > 0 {
+			return fmt.Errorf("%w: index set %v is not a non-empty subset of %d outcomes", ErrInvalidPartition, indexSet, outcomeSlotCount)
+		}
+		if new(big.Int).And(union, indexSet).Sign() != 0 {
+			return fmt.Errorf("%w: index set %v overlaps another", ErrInvalidPartition, indexSet)
+		}
+		union.Or(union, indexSet)
+	}
+	if complete && (union.Cmp(fullIndexSet) != 0 || len(partition) < 2) {
+		return fmt.Errorf("%w: index sets %v do not split all %d outcomes", ErrInvalidPartition, partition, outcomeSlotCount)
+	}
+	return nil
+}
+
+// validateAdapterPartition checks that partition is [1, 2] in any order, or when complete is false, that the
+// index sets to redeem are outcomes of a binary condition, the only ones the CtfCollateralAdapter handles
+func validateAdapterPartition(partition []*big.Int, complete bool) error {
+	if err := ValidatePartition(partition, 2, complete); err != nil {
+		return fmt.Errorf("%w: got index sets %v", ErrAdapterNotBinary, partition)
+	}
+	return nil
+}
+
+// resolvePartition returns the partition of opts, or the full partition of the condition, validated
+// against its outcome slot count
+func resolvePartition(ctx context.Context, ctf *conditional_tokens.ConditionalTokens, conditionId [32]byte, complete bool, opts []PositionOption) ([]*big.Int, error) {
+	cfg := &positionConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	slotCount, err := ctf.GetOutcomeSlotCount(&bind.CallOpts{Context: ctx}, conditionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outcome slot count of condition %x: %w", conditionId, err)
+	}
+	if slotCount.Sign() == 0 {
+		return nil, fmt.Errorf("condition %x is not prepared", conditionId)
+	}
+	outcomeSlotCount := int(slotCount.Int64())
+
+	partition := cfg.partition
+	if partition == nil {
+		partition = FullPartition(outcomeSlotCount)
+	}
+	if err := ValidatePartition(partition, outcomeSlotCount, complete); err != nil {
+		return nil, err
+	}
+	return partition, nil
+}
+
+// completeSets returns how many complete sets of partition account holds, the most it can merge: the minimum
+// balance of the partition's positions backed by collateral
+func (r *portfolioReader) completeSets(ctx context.Context, account, collateral common.Address, conditionId [32]byte, partition []*big.Int) (*big.Int, error) {
+	positionIds := make([]*big.Int, len(partition))
+	for i, indexSet := range partition {
+		collectionId, err := ComputeCollectionId([32]byte{}, conditionId, indexSet)
+		if err != nil {
+			return nil, err
+		}
+		positionIds[i] = ComputePositionId(collateral, collectionId)
+	}
+	balances, err := r.balancesOf(&bind.CallOpts{Context: ctx}, account, positionIds, len(positionIds))
+	if err != nil {
+		return nil, err
+	}
+	var sets *big.Int
+	for _, balance := range balances {
+		if sets == nil || balance.Cmp(sets) < 0 {
+			sets = balance
+		}
+	}
+	if sets == nil || sets.Sign() == 0 {
+		return nil, fmt.Errorf("%s holds no complete sets of condition %x to merge", account.Hex(), conditionId)
+	}
+	return sets, nil
+}
@@ -0,0 +1,186 @@
+package polymarketcontracts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	collateral_token "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/collateral-token"
+	conditional_tokens "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/conditional-tokens"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/erc20"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+func TestValidatePartition(t *testing.T) {
+	tests := []struct {
+		name      string
+		partition []*big.Int
+		complete  bool
+		wantErr   bool
+	}{
+		{"full", FullPartition(3), true, false},
+		{"A vs BC", []*big.Int{IndexSet(0), IndexSet(1, 2)}, true, false},
+		{"overlapping", []*big.Int{IndexSet(0, 1), IndexSet(1, 2)}, true, true},
+		{"incomplete", []*big.Int{IndexSet(0), IndexSet(1)}, true, true},
+		{"incomplete redeem", []*big.Int{IndexSet(2)}, false, false},
+		{"single full set", []*big.Int{IndexSet(0, 1, 2)}, true, true},
+		{"outside the condition", []*big.Int{IndexSet(0), IndexSet(1, 2, 3)}, true, true},
+		{"empty index set", []*big.Int{IndexSet(), IndexSet(0, 1, 2)}, false, true},
+		{"no index sets", nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePartition(tt.partition, 3, tt.complete)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrInvalidPartition)) {
+				t.Errorf("got %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestV2Split_BinaryConditionsOnly(t *testing.T) {
+	chain := newPortfolioChain(t)
+	binary, ternary := [32]byte{0x02}, [32]byte{0x03}
+	chain.slots[binary], chain.slots[ternary] = 2, 3
+	ctf, err := conditional_tokens.NewConditionalTokens(MATIC_CONTRACTS.ConditionalTokens, chain)
+	if err != nil {
+		t.Fatalf("NewConditionalTokens: %v", err)
+	}
+	mock := &mockTransactionSender{}
+	v := newV2TestInstance(mock)
+	v.conditionalTokens = ctf
+	amount := big.NewInt(1e6)
+
+	if _, err := v.Split(context.Background(), binary, amount); err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	want, _ := buildAdapterSplitCall(MATIC_CONTRACTS.CtfCollateralAdapter, binary, FullPartition(2), amount)
+	if !bytes.Equal(mock.lastData, want.Calldata) {
+		t.Error("Split did not use the full partition of the binary condition")
+	}
+	if _, err := v.Merge(context.Background(), binary, amount, WithPartition(IndexSet(1), IndexSet(0))); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if _, err := v.Redeem(context.Background(), binary, WithPartition(IndexSet(1))); err != nil {
+		t.Fatalf("Redeem: %v", err)
+	}
+
+	// The adapter would split, merge and redeem [1, 2] whatever the partition
+	if _, err := v.Split(context.Background(), ternary, amount); !errors.Is(err, ErrAdapterNotBinary) {
+		t.Errorf("three outcomes: got %v, want ErrAdapterNotBinary", err)
+	}
+	if _, err := v.Merge(context.Background(), ternary, amount, WithPartition(IndexSet(0), IndexSet(1, 2))); !errors.Is(err, ErrAdapterNotBinary) {
+		t.Errorf("custom partition: got %v, want ErrAdapterNotBinary", err)
+	}
+	if _, err := v.Redeem(context.Background(), ternary); !errors.Is(err, ErrAdapterNotBinary) {
+		t.Errorf("redeem of three outcomes: got %v, want ErrAdapterNotBinary", err)
+	}
+	if _, err := v.Split(context.Background(), binary, amount, WithPartition(IndexSet(0))); !errors.Is(err, ErrInvalidPartition) {
+		t.Errorf("incomplete partition: got %v", err)
+	}
+	if _, err := v.Redeem(context.Background(), [32]byte{0x04}); err == nil {
+		t.Error("expected an unprepared condition to fail")
+	}
+	if mock.calls != 3 {
+		t.Errorf("%d transactions, want 3", mock.calls)
+	}
+}
+
+func TestV2MaxAmounts_ResolvedOnChain(t *testing.T) {
+	c := MATIC_CONTRACTS
+	chain := newPortfolioChain(t)
+	chain.tokens[c.CollateralToken] = 8e6
+	ids, _ := ComputeOutcomePositionIds(c.Collateral, testCondID, 2)
+	chain.balances[ids[0].String()] = 5e6
+	chain.balances[ids[1].String()] = 3e6
+	reader, err := newPortfolioReader(c, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	pUSD, err := collateral_token.NewCollateralToken(c.CollateralToken, chain)
+	if err != nil {
+		t.Fatalf("NewCollateralToken: %v", err)
+	}
+	eoa := &addrSender{addr: testSafe}
+	v := newV2TestInstance(nil)
+	v.executor.txSender = eoa
+	v.portfolio = reader
+	v.collateralToken = pUSD
+	partition := FullPartition(2)
+
+	if _, err := v.SplitPositionForEOA(context.Background(), testCondID, partition, nil); err != nil {
+		t.Fatalf("SplitPositionForEOA: %v", err)
+	}
+	want, _ := buildAdapterSplitCall(c.CtfCollateralAdapter, testCondID, partition, big.NewInt(8e6))
+	if !bytes.Equal(eoa.lastData, want.Calldata) {
+		t.Error("split did not use the entire pUSD balance")
+	}
+
+	if _, err := v.MergePositionsForEOA(context.Background(), testCondID, partition, nil); err != nil {
+		t.Fatalf("MergePositionsForEOA: %v", err)
+	}
+	want, _ = buildAdapterMergeCall(c.CtfCollateralAdapter, testCondID, partition, big.NewInt(3e6))
+	if !bytes.Equal(eoa.lastData, want.Calldata) {
+		t.Error("merge did not use the smaller outcome balance")
+	}
+
+	chain.balances = map[string]int64{}
+	if _, err := v.MergePositionsForEOA(context.Background(), testCondID, partition, nil); err == nil || eoa.calls != 2 {
+		t.Errorf("got %v after %d transactions, want no merge without complete sets", err, eoa.calls)
+	}
+	chain.tokens[c.CollateralToken] = 0
+	if _, err := v.SplitPositionForEOA(context.Background(), testCondID, partition, nil); err == nil || eoa.calls != 2 {
+		t.Errorf("got %v after %d transactions, want no split without pUSD", err, eoa.calls)
+	}
+}
+
+func TestMergePositionsNegRiskForSafe_MaxAmount(t *testing.T) {
+	c := MATIC_CONTRACTS
+	chain := newPortfolioChain(t)
+	chain.tokens[c.Collateral] = 4e6
+	ids, _ := ComputeOutcomePositionIds(c.NegRiskWrappedCollateral, testCondID, 2)
+	chain.balances[ids[0].String()] = 2e6
+	chain.balances[ids[1].String()] = 7e6
+	reader, err := newPortfolioReader(c, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	usdce, err := erc20.NewErc20(c.Collateral, chain)
+	if err != nil {
+		t.Fatalf("NewErc20: %v", err)
+	}
+	var sent [][]byte
+	b := &ContractInterface{
+		contractConfig:     c,
+		portfolio:          reader,
+		collateralContract: usdce,
+		executor: &txExecutor{
+			getSafeAddr: func(common.Address) (common.Address, error) { return testSafe, nil },
+			execSafeTx: func(_ context.Context, _ signer.SafeTradingSigner, _ *big.Int, _, _ common.Address, _ *big.Int, data []byte, _ SafeOperation, _ *big.Int, _ ...sender.SendOption) (common.Hash, error) {
+				sent = append(sent, data)
+				return common.HexToHash("0x01"), nil
+			},
+		},
+	}
+
+	if _, err := b.MergePositionsNegRiskForSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), testCondID, nil); err != nil {
+		t.Fatalf("MergePositionsNegRiskForSafe: %v", err)
+	}
+	if _, err := b.SplitPositionNegRiskForSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), testCondID, nil); err != nil {
+		t.Fatalf("SplitPositionNegRiskForSafe: %v", err)
+	}
+	merge, _ := buildMergeNegRiskCall(c.NegRiskAdapter, testCondID, big.NewInt(2e6))
+	split, _ := buildSplitNegRiskCall(c.NegRiskAdapter, testCondID, big.NewInt(4e6))
+	if len(sent) != 2 || !bytes.Equal(sent[0], merge.Calldata) || !bytes.Equal(sent[1], split.Calldata) {
+		t.Error("max amounts not resolved from the Safe's balances")
+	}
+
+	chain.tokens[c.Collateral] = 0
+	if _, err := b.SplitPositionNegRiskForSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), testCondID, nil); err == nil || len(sent) != 2 {
+		t.Errorf("got %v after %d transactions, want no split without USDC.e", err, len(sent))
+	}
+}
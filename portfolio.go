@@ -0,0 +1,391 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	conditional_tokens "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/conditional-tokens"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/exchange"
+	negrisk "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk"
+	negriskadapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-adapter"
+)
+
+// PortfolioCondition is a condition whose outcome tokens are read into a portfolio
+type PortfolioCondition struct {
+	ConditionId [32]byte
+	// NegRisk marks neg-risk conditions, whose positions are backed by the NegRisk adapter's wrapped collateral
+	NegRisk bool
+	// OutcomeLabels names outcomes by slot (default: "Yes" and "No" for binary conditions)
+	OutcomeLabels []string
+}
+
+// OutcomeHolding is an account's balance of one outcome of a condition
+type OutcomeHolding struct {
+	// IndexSet is the outcome's index set, 1 << slot
+	IndexSet   *big.Int
+	Label      string
+	PositionId *big.Int
+	Balance    *big.Int
+	// Payout is the collateral Balance redeems for; zero until the condition is resolved
+	Payout *big.Int
+}
+
+// ConditionHoldings are an account's outcome tokens of one condition
+type ConditionHoldings struct {
+	ConditionId [32]byte
+	NegRisk     bool
+	// Collateral backs the positions: USDC.e, or the wrapped collateral of neg-risk conditions
+	Collateral common.Address
+	Outcomes   []OutcomeHolding
+
+	// Resolved is set once the condition's payouts are reported
+	Resolved          bool
+	PayoutNumerators  []*big.Int
+	PayoutDenominator *big.Int
+	// RedeemableValue is the collateral redeeming every outcome returns now
+	RedeemableValue *big.Int
+}
+
+// HasBalance reports whether the account holds any outcome token of the condition
+func (h *ConditionHoldings) HasBalance() bool {
+	for _, outcome := range h.Outcomes {
+		if outcome.Balance.Sign() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Portfolio is an account's outcome tokens across conditions
+type Portfolio struct {
+	Account    common.Address
+	Conditions []*ConditionHoldings
+	// RedeemableValue is the collateral redeeming every resolved condition returns now
+	RedeemableValue *big.Int
+}
+
+type portfolioConfig struct {
+	batchSize   int
+	blockNumber *big.Int
+	blockSpan   uint64
+}
+
+// PortfolioOption configures portfolio reads and condition discovery
+type PortfolioOption func(c *portfolioConfig)
+
+// WithPortfolioBatchSize sets how many positions a BalanceOfBatch call queries (default: 200)
+func WithPortfolioBatchSize(n int) PortfolioOption {
+	return func(c *portfolioConfig) {
+		c.batchSize = n
+	}
+}
+
+// WithPortfolioBlock reads the portfolio at a specific block (default: latest)
+func WithPortfolioBlock(blockNumber *big.Int) PortfolioOption {
+	return func(c *portfolioConfig) {
+		c.blockNumber = blockNumber
+	}
+}
+
+// WithDiscoveryBlockSpan sets how many blocks a log query of condition discovery covers (default: 10000)
+func WithDiscoveryBlockSpan(blocks uint64) PortfolioOption {
+	return func(c *portfolioConfig) {
+		c.blockSpan = blocks
+	}
+}
+
+func newPortfolioConfig(opts []PortfolioOption) *portfolioConfig {
+	c := &portfolioConfig{batchSize: 200, blockSpan: 10000}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.batchSize <= 0 {
+		c.batchSize = 200
+	}
+	if c.blockSpan == 0 {
+		c.blockSpan = 10000
+	}
+	return c
+}
+
+// portfolioReader reads outcome-token holdings; it is shared by ContractInterface and ContractInterfaceV2
+type portfolioReader struct {
+	config          *ContractConfig
+	backend         bind.ContractBackend
+	ctf             *conditional_tokens.ConditionalTokens
+	exchange        *exchange.Exchange
+	negRiskExchange *negrisk.NegRisk
+	negRiskAdapter  *negriskadapter.NegRiskAdapter
+}
+
+func newPortfolioReader(config *ContractConfig, backend bind.ContractBackend) (*portfolioReader, error) {
+	ctf, err := conditional_tokens.NewConditionalTokens(config.ConditionalTokens, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ConditionalTokens binding: %w", err)
+	}
+	exchangeContract, err := exchange.NewExchange(config.Exchange, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Exchange binding: %w", err)
+	}
+	negRiskExchange, err := negrisk.NewNegRisk(config.NegRiskExchange, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NegRisk binding: %w", err)
+	}
+	negRiskAdapter, err := negriskadapter.NewNegRiskAdapter(config.NegRiskAdapter, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NegRiskAdapter binding: %w", err)
+	}
+	return &portfolioReader{config: config, backend: backend, ctf: ctf, exchange: exchangeContract, negRiskExchange: negRiskExchange, negRiskAdapter: negRiskAdapter}, nil
+}
+
+// collateralOf returns the collateral backing the positions of a condition
+func (r *portfolioReader) collateralOf(negRisk bool) common.Address {
+	if negRisk {
+		return r.config.NegRiskWrappedCollateral
+	}
+	return r.config.Collateral
+}
+
+// getPortfolio derives the position IDs of conditions, queries the balances of account in batches
+// and values them at the reported payouts
+func (r *portfolioReader) getPortfolio(ctx context.Context, account common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*Portfolio, error) {
+	cfg := newPortfolioConfig(opts)
+	callOpts := &bind.CallOpts{Context: ctx, BlockNumber: cfg.blockNumber}
+	portfolio := &Portfolio{Account: account, RedeemableValue: new(big.Int)}
+
+	var positionIds []*big.Int
+	for _, condition := range conditions {
+		holdings, err := r.readCondition(callOpts, condition)
+		if err != nil {
+			return nil, err
+		}
+		for _, outcome := range holdings.Outcomes {
+			positionIds = append(positionIds, outcome.PositionId)
+		}
+		portfolio.Conditions = append(portfolio.Conditions, holdings)
+	}
+
+	balances, err := r.balancesOf(callOpts, account, positionIds, cfg.batchSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, holdings := range portfolio.Conditions {
+		for i := range holdings.Outcomes {
+			outcome := &holdings.Outcomes[i]
+			outcome.Balance, balances = balances[0], balances[1:]
+			if holdings.Resolved {
+				outcome.Payout.Mul(outcome.Balance, holdings.PayoutNumerators[i])
+				outcome.Payout.Div(outcome.Payout, holdings.PayoutDenominator)
+				holdings.RedeemableValue.Add(holdings.RedeemableValue, outcome.Payout)
+			}
+		}
+		portfolio.RedeemableValue.Add(portfolio.RedeemableValue, holdings.RedeemableValue)
+	}
+	return portfolio, nil
+}
+
+// readCondition reads the outcomes and resolution of a condition, leaving balances unset
+func (r *portfolioReader) readCondition(opts *bind.CallOpts, condition PortfolioCondition) (*ConditionHoldings, error) {
+	slotCount, err := r.ctf.GetOutcomeSlotCount(opts, condition.ConditionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outcome slot count of condition %x: %w", condition.ConditionId, err)
+	}
+	if slotCount.Sign() == 0 {
+		return nil, fmt.Errorf("condition %x is not prepared", condition.ConditionId)
+	}
+	outcomeSlotCount := int(slotCount.Int64())
+
+	holdings := &ConditionHoldings{
+		ConditionId:     condition.ConditionId,
+		NegRisk:         condition.NegRisk,
+		Collateral:      r.collateralOf(condition.NegRisk),
+		RedeemableValue: new(big.Int),
+	}
+	positionIds, err := ComputeOutcomePositionIds(holdings.Collateral, condition.ConditionId, outcomeSlotCount)
+	if err != nil {
+		return nil, err
+	}
+	for i, positionId := range positionIds {
+		holdings.Outcomes = append(holdings.Outcomes, OutcomeHolding{
+			IndexSet:   new(big.Int).Lsh(big.NewInt(1), uint(i)),
+			Label:      outcomeLabel(condition.OutcomeLabels, i, outcomeSlotCount),
+			PositionId: positionId,
+			Payout:     new(big.Int),
+		})
+	}
+
+	holdings.PayoutDenominator, err = r.ctf.PayoutDenominator(opts, condition.ConditionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout denominator of condition %x: %w", condition.ConditionId, err)
+	}
+	if holdings.PayoutDenominator.Sign() == 0 {
+		return holdings, nil
+	}
+	holdings.Resolved = true
+	for i := 0; i < outcomeSlotCount; i++ {
+		numerator, err := r.ctf.PayoutNumerators(opts, condition.ConditionId, big.NewInt(int64(i)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get payout numerator %d of condition %x: %w", i, condition.ConditionId, err)
+		}
+		holdings.PayoutNumerators = append(holdings.PayoutNumerators, numerator)
+	}
+	return holdings, nil
+}
+
+// balanceOfBatch is the ERC-1155 balanceOfBatch of a binding
+type balanceOfBatch func(opts *bind.CallOpts, owners []common.Address, ids []*big.Int) ([]*big.Int, error)
+
+// balancesOf queries the balances of account for positionIds, batchSize positions per call
+func (r *portfolioReader) balancesOf(opts *bind.CallOpts, account common.Address, positionIds []*big.Int, batchSize int) ([]*big.Int, error) {
+	return batchBalances(r.ctf.BalanceOfBatch, opts, account, positionIds, batchSize)
+}
+
+// batchBalances queries the balances of account for positionIds through query, batchSize positions per call
+func batchBalances(query balanceOfBatch, opts *bind.CallOpts, account common.Address, positionIds []*big.Int, batchSize int) ([]*big.Int, error) {
+	balances := make([]*big.Int, 0, len(positionIds))
+	for start := 0; start < len(positionIds); start += batchSize {
+		end := min(start+batchSize, len(positionIds))
+		owners := make([]common.Address, end-start)
+		for i := range owners {
+			owners[i] = account
+		}
+		batch, err := query(opts, owners, positionIds[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balances of positions %d-%d: %w", start, end-1, err)
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("got %d balances for %d positions", len(batch), end-start)
+		}
+		balances = append(balances, batch...)
+	}
+	return balances, nil
+}
+
+// discoverConditions finds the conditions of every outcome token transferred to account between
+// fromBlock and toBlock (nil = latest). Token IDs are mapped to conditions through the V1 exchange
+// registries, so tokens of markets never registered on an exchange are not found.
+func (r *portfolioReader) discoverConditions(ctx context.Context, account common.Address, fromBlock uint64, toBlock *uint64, opts ...PortfolioOption) ([]PortfolioCondition, error) {
+	cfg := newPortfolioConfig(opts)
+	if toBlock == nil {
+		head, err := r.backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest block: %w", err)
+		}
+		latest := head.Number.Uint64()
+		toBlock = &latest
+	}
+
+	var tokenIds []*big.Int
+	for start := fromBlock; start <= *toBlock; start += cfg.blockSpan {
+		end := min(start+cfg.blockSpan-1, *toBlock)
+		filterOpts := &bind.FilterOpts{Start: start, End: &end, Context: ctx}
+
+		singles, err := r.ctf.FilterTransferSingle(filterOpts, nil, nil, []common.Address{account})
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter TransferSingle logs of blocks %d-%d: %w", start, end, err)
+		}
+		for singles.Next() {
+			tokenIds = append(tokenIds, singles.Event.Id)
+		}
+		err = singles.Error()
+		singles.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TransferSingle logs of blocks %d-%d: %w", start, end, err)
+		}
+
+		batches, err := r.ctf.FilterTransferBatch(filterOpts, nil, nil, []common.Address{account})
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter TransferBatch logs of blocks %d-%d: %w", start, end, err)
+		}
+		for batches.Next() {
+			tokenIds = append(tokenIds, batches.Event.Ids...)
+		}
+		err = batches.Error()
+		batches.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TransferBatch logs of blocks %d-%d: %w", start, end, err)
+		}
+	}
+
+	callOpts := &bind.CallOpts{Context: ctx}
+	seen := map[string]bool{}
+	var conditions []PortfolioCondition
+	for _, tokenId := range tokenIds {
+		if seen[tokenId.String()] {
+			continue
+		}
+		condition, ok, err := r.conditionOfToken(callOpts, tokenId)
+		if err != nil {
+			return nil, err
+		}
+		seen[tokenId.String()] = true
+		if !ok {
+			continue
+		}
+		// Skip the registry lookups of the condition's other outcomes
+		siblings, err := ComputeOutcomePositionIds(r.collateralOf(condition.NegRisk), condition.ConditionId, 2)
+		if err != nil {
+			return nil, err
+		}
+		for _, sibling := range siblings {
+			seen[sibling.String()] = true
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// conditionOfToken looks tokenId up in the exchange registries
+func (r *portfolioReader) conditionOfToken(opts *bind.CallOpts, tokenId *big.Int) (PortfolioCondition, bool, error) {
+	conditionId, err := r.exchange.GetConditionId(opts, tokenId)
+	if err != nil {
+		return PortfolioCondition{}, false, fmt.Errorf("failed to look up token %s on Exchange: %w", tokenId, err)
+	}
+	if conditionId != ([32]byte{}) {
+		return PortfolioCondition{ConditionId: conditionId}, true, nil
+	}
+	conditionId, err = r.negRiskExchange.GetConditionId(opts, tokenId)
+	if err != nil {
+		return PortfolioCondition{}, false, fmt.Errorf("failed to look up token %s on NegRiskExchange: %w", tokenId, err)
+	}
+	if conditionId != ([32]byte{}) {
+		return PortfolioCondition{ConditionId: conditionId, NegRisk: true}, true, nil
+	}
+	return PortfolioCondition{}, false, nil
+}
+
+// outcomeLabel returns the label of outcome slot i
+func outcomeLabel(labels []string, i, outcomeSlotCount int) string {
+	if i < len(labels) {
+		return labels[i]
+	}
+	if outcomeSlotCount == 2 && len(labels) == 0 {
+		return []string{"Yes", "No"}[i]
+	}
+	return fmt.Sprintf("Outcome %d", i)
+}
+
+// GetPortfolio returns the outcome tokens account holds of conditions, with their resolution and redeemable value
+func (b *ContractInterface) GetPortfolio(ctx context.Context, account common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*Portfolio, error) {
+	return b.portfolio.getPortfolio(ctx, account, conditions, opts...)
+}
+
+// DiscoverPortfolioConditions finds the conditions of outcome tokens transferred to account between
+// fromBlock and toBlock (nil = latest), for GetPortfolio
+func (b *ContractInterface) DiscoverPortfolioConditions(ctx context.Context, account common.Address, fromBlock uint64, toBlock *uint64, opts ...PortfolioOption) ([]PortfolioCondition, error) {
+	return b.portfolio.discoverConditions(ctx, account, fromBlock, toBlock, opts...)
+}
+
+// GetPortfolio returns the outcome tokens account holds of conditions, with their resolution and redeemable value
+func (v *ContractInterfaceV2) GetPortfolio(ctx context.Context, account common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*Portfolio, error) {
+	return v.portfolio.getPortfolio(ctx, account, conditions, opts...)
+}
+
+// DiscoverPortfolioConditions finds the conditions of outcome tokens transferred to account between
+// fromBlock and toBlock (nil = latest), for GetPortfolio
+func (v *ContractInterfaceV2) DiscoverPortfolioConditions(ctx context.Context, account common.Address, fromBlock uint64, toBlock *uint64, opts ...PortfolioOption) ([]PortfolioCondition, error) {
+	return v.portfolio.discoverConditions(ctx, account, fromBlock, toBlock, opts...)
+}
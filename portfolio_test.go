@@ -0,0 +1,296 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	conditional_tokens "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/conditional-tokens"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/erc20"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/exchange"
+	negriskadapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-adapter"
+)
+
+// portfolioChain answers the ConditionalTokens, NegRiskAdapter, collateral and exchange registry calls of the portfolio reader.
+// Other backend methods panic through the nil embedded interface.
+type portfolioChain struct {
+	bind.ContractBackend
+	t *testing.T
+
+	slots    map[[32]byte]int64
+	payouts  map[[32]byte][]int64 // payout numerators of resolved conditions; the denominator is their sum
+	balances map[string]int64     // position ID -> balance of the account
+	registry map[common.Address]map[string][32]byte
+	logs     []types.Log
+	head     uint64
+
+	markets   map[[32]byte]*testNegRiskMarket
+	tokens    map[common.Address]int64 // ERC-20 balance of the account by token
+	approvals map[common.Address]bool  // ConditionalTokens operator -> approved for every owner
+
+	balanceBatches int
+	logQueries     int
+}
+
+func newPortfolioChain(t *testing.T) *portfolioChain {
+	return &portfolioChain{
+		t:         t,
+		slots:     map[[32]byte]int64{},
+		payouts:   map[[32]byte][]int64{},
+		balances:  map[string]int64{},
+		registry:  map[common.Address]map[string][32]byte{MATIC_CONTRACTS.Exchange: {}, MATIC_CONTRACTS.NegRiskExchange: {}},
+		markets:   map[[32]byte]*testNegRiskMarket{},
+		approvals: map[common.Address]bool{},
+		tokens:    map[common.Address]int64{},
+	}
+}
+
+// testNegRiskMarket is the NegRiskAdapter state of a market
+type testNegRiskMarket struct {
+	oracle     common.Address
+	questions  int64
+	feeBips    int64
+	determined bool
+	result     int64
+}
+
+// market returns the state of marketId, zero for unprepared markets
+func (c *portfolioChain) market(marketId [32]byte) *testNegRiskMarket {
+	if m, ok := c.markets[marketId]; ok {
+		return m
+	}
+	return &testNegRiskMarket{}
+}
+
+func (c *portfolioChain) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	c.t.Helper()
+	var parsed *abi.ABI
+	var err error
+	switch {
+	case msg.To != nil && *msg.To == MATIC_CONTRACTS.ConditionalTokens:
+		parsed, err = conditional_tokens.ConditionalTokensMetaData.GetAbi()
+	case msg.To != nil && *msg.To == MATIC_CONTRACTS.NegRiskAdapter:
+		parsed, err = negriskadapter.NegRiskAdapterMetaData.GetAbi()
+	case msg.To != nil && slices.Contains([]common.Address{MATIC_CONTRACTS.Collateral, MATIC_CONTRACTS.CollateralToken}, *msg.To):
+		parsed, err = erc20.Erc20MetaData.GetAbi()
+	default:
+		parsed, err = exchange.ExchangeMetaData.GetAbi()
+	}
+	if err != nil {
+		c.t.Fatalf("GetAbi: %v", err)
+	}
+	method, err := parsed.MethodById(msg.Data[:4])
+	if err != nil {
+		c.t.Fatalf("MethodById: %v", err)
+	}
+	args, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		c.t.Fatalf("Unpack %s: %v", method.Name, err)
+	}
+
+	var out []interface{}
+	switch method.Name {
+	case "getOutcomeSlotCount":
+		out = []interface{}{big.NewInt(c.slots[args[0].([32]byte)])}
+	case "payoutDenominator":
+		denominator := int64(0)
+		for _, numerator := range c.payouts[args[0].([32]byte)] {
+			denominator += numerator
+		}
+		out = []interface{}{big.NewInt(denominator)}
+	case "payoutNumerators":
+		out = []interface{}{big.NewInt(c.payouts[args[0].([32]byte)][args[1].(*big.Int).Int64()])}
+	case "balanceOfBatch":
+		c.balanceBatches++
+		var balances []*big.Int
+		for _, id := range args[1].([]*big.Int) {
+			balances = append(balances, big.NewInt(c.balances[id.String()]))
+		}
+		out = []interface{}{balances}
+	case "balanceOf":
+		out = []interface{}{big.NewInt(c.tokens[*msg.To])}
+	case "getMarketData":
+		m := c.market(args[0].([32]byte))
+		var data [32]byte
+		if m.oracle != (common.Address{}) {
+			data[0] = byte(m.questions)
+			copy(data[12:], m.oracle.Bytes())
+		}
+		out = []interface{}{data}
+	case "getDetermined":
+		out = []interface{}{c.market(args[0].([32]byte)).determined}
+	case "getResult":
+		out = []interface{}{big.NewInt(c.market(args[0].([32]byte)).result)}
+	case "getOracle":
+		out = []interface{}{c.market(args[0].([32]byte)).oracle}
+	case "getQuestionCount":
+		out = []interface{}{big.NewInt(c.market(args[0].([32]byte)).questions)}
+	case "getFeeBips":
+		out = []interface{}{big.NewInt(c.market(args[0].([32]byte)).feeBips)}
+	case "isApprovedForAll":
+		out = []interface{}{c.approvals[args[1].(common.Address)]}
+	case "getConditionId":
+		if *msg.To == MATIC_CONTRACTS.NegRiskAdapter {
+			out = []interface{}{ComputeConditionId(MATIC_CONTRACTS.NegRiskAdapter, args[0].([32]byte), 2)}
+			break
+		}
+		out = []interface{}{c.registry[*msg.To][args[0].(*big.Int).String()]}
+	default:
+		c.t.Fatalf("unexpected call of %s", method.Name)
+	}
+	return method.Outputs.Pack(out...)
+}
+
+func (c *portfolioChain) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
+	return &types.Header{Number: new(big.Int).SetUint64(c.head)}, nil
+}
+
+func (c *portfolioChain) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	c.logQueries++
+	var logs []types.Log
+	for _, log := range c.logs {
+		if log.BlockNumber >= query.FromBlock.Uint64() && log.BlockNumber <= query.ToBlock.Uint64() && matchTopics(log, query.Topics) {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+// matchTopics reports whether log matches the topic filter of a log query
+func matchTopics(log types.Log, topics [][]common.Hash) bool {
+	for i, alternatives := range topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		if i >= len(log.Topics) || !slices.Contains(alternatives, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// addTransfer adds a TransferSingle (one ID) or TransferBatch log to account at block
+func (c *portfolioChain) addTransfer(account common.Address, block uint64, ids ...*big.Int) {
+	c.t.Helper()
+	parsed, err := conditional_tokens.ConditionalTokensMetaData.GetAbi()
+	if err != nil {
+		c.t.Fatalf("GetAbi: %v", err)
+	}
+	event := parsed.Events["TransferBatch"]
+	values := make([]*big.Int, len(ids))
+	for i := range values {
+		values[i] = big.NewInt(1)
+	}
+	data, err := event.Inputs.NonIndexed().Pack(ids, values)
+	if len(ids) == 1 {
+		event = parsed.Events["TransferSingle"]
+		data, err = event.Inputs.NonIndexed().Pack(ids[0], values[0])
+	}
+	if err != nil {
+		c.t.Fatalf("Pack: %v", err)
+	}
+	c.logs = append(c.logs, types.Log{
+		Address:     MATIC_CONTRACTS.ConditionalTokens,
+		Topics:      []common.Hash{event.ID, {}, {}, common.BytesToHash(account.Bytes())},
+		Data:        data,
+		BlockNumber: block,
+	})
+}
+
+func TestGetPortfolio(t *testing.T) {
+	c := MATIC_CONTRACTS
+	chain := newPortfolioChain(t)
+	binary := [32]byte{0x01}
+	negRisk := [32]byte{0x02}
+	ternary := [32]byte{0x03}
+	chain.slots[binary], chain.slots[negRisk], chain.slots[ternary] = 2, 2, 3
+	chain.payouts[binary] = []int64{1, 0}
+	chain.payouts[ternary] = []int64{1, 1, 0}
+
+	binaryIds, _ := ComputeOutcomePositionIds(c.Collateral, binary, 2)
+	negRiskIds, _ := ComputeOutcomePositionIds(c.NegRiskWrappedCollateral, negRisk, 2)
+	ternaryIds, _ := ComputeOutcomePositionIds(c.Collateral, ternary, 3)
+	chain.balances[binaryIds[0].String()] = 5e6
+	chain.balances[binaryIds[1].String()] = 3e6
+	chain.balances[negRiskIds[1].String()] = 7e6
+	chain.balances[ternaryIds[0].String()] = 3
+	chain.balances[ternaryIds[2].String()] = 4e6
+
+	reader, err := newPortfolioReader(c, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	portfolio, err := reader.getPortfolio(context.Background(), testSafe, []PortfolioCondition{
+		{ConditionId: binary},
+		{ConditionId: negRisk, NegRisk: true},
+		{ConditionId: ternary, OutcomeLabels: []string{"A", "B", "C"}},
+	}, WithPortfolioBatchSize(3))
+	if err != nil {
+		t.Fatalf("getPortfolio: %v", err)
+	}
+	if chain.balanceBatches != 3 {
+		t.Errorf("%d BalanceOfBatch calls for 7 positions in batches of 3, want 3", chain.balanceBatches)
+	}
+
+	b := portfolio.Conditions[0]
+	if !b.Resolved || b.Collateral != c.Collateral || b.Outcomes[0].Label != "Yes" || b.Outcomes[1].Label != "No" ||
+		b.Outcomes[0].Payout.Int64() != 5e6 || b.Outcomes[1].Payout.Sign() != 0 || b.RedeemableValue.Int64() != 5e6 {
+		t.Errorf("binary condition: %+v", b)
+	}
+	n := portfolio.Conditions[1]
+	if n.Resolved || n.Collateral != c.NegRiskWrappedCollateral || n.Outcomes[1].Balance.Int64() != 7e6 || n.RedeemableValue.Sign() != 0 || !n.HasBalance() {
+		t.Errorf("neg-risk condition: %+v", n)
+	}
+	// Payouts round down per outcome, like redeemPositions
+	tr := portfolio.Conditions[2]
+	if tr.Outcomes[2].Label != "C" || tr.Outcomes[2].IndexSet.Int64() != 4 || tr.Outcomes[0].Payout.Int64() != 1 || tr.RedeemableValue.Int64() != 1 {
+		t.Errorf("ternary condition: %+v", tr)
+	}
+	if portfolio.RedeemableValue.Int64() != 5e6+1 {
+		t.Errorf("redeemable value %s", portfolio.RedeemableValue)
+	}
+
+	if _, err := reader.getPortfolio(context.Background(), testSafe, []PortfolioCondition{{ConditionId: [32]byte{0x04}}}); err == nil {
+		t.Error("expected an unprepared condition to fail")
+	}
+}
+
+func TestDiscoverPortfolioConditions(t *testing.T) {
+	c := MATIC_CONTRACTS
+	chain := newPortfolioChain(t)
+	chain.head = 250
+	regular := [32]byte{0x01}
+	negRisk := [32]byte{0x02}
+	regularIds, _ := ComputeOutcomePositionIds(c.Collateral, regular, 2)
+	negRiskIds, _ := ComputeOutcomePositionIds(c.NegRiskWrappedCollateral, negRisk, 2)
+	for _, id := range regularIds {
+		chain.registry[c.Exchange][id.String()] = regular
+	}
+	chain.registry[c.NegRiskExchange][negRiskIds[0].String()] = negRisk
+
+	chain.addTransfer(testSafe, 10, regularIds[0])
+	chain.addTransfer(testSafe, 120, regularIds[1], negRiskIds[0], big.NewInt(42))
+	chain.addTransfer(testSafe, 300, negRiskIds[1]) // after the head
+
+	reader, err := newPortfolioReader(c, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	conditions, err := reader.discoverConditions(context.Background(), testSafe, 0, nil, WithDiscoveryBlockSpan(100))
+	if err != nil {
+		t.Fatalf("discoverConditions: %v", err)
+	}
+	if len(conditions) != 2 || conditions[0].ConditionId != regular || conditions[0].NegRisk || conditions[1].ConditionId != negRisk || !conditions[1].NegRisk {
+		t.Errorf("got %+v", conditions)
+	}
+	// Blocks 0-250 in spans of 100, each queried for TransferSingle and TransferBatch
+	if chain.logQueries != 6 {
+		t.Errorf("%d log queries, want 6", chain.logQueries)
+	}
+}
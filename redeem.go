@@ -0,0 +1,200 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+// RedeemReport is the outcome of redeeming the resolved positions of an account
+type RedeemReport struct {
+	Account common.Address
+	// Regular and NegRisk are the redeemed conditions, with the balances held before redeeming.
+	// If redeeming fails, they only hold the conditions of the transactions sent before the failure.
+	Regular []*ConditionHoldings
+	NegRisk []*ConditionHoldings
+	// TxHashes are the redemption transactions: one per condition for an EOA, a single batch for a Safe
+	TxHashes []common.Hash
+	// CollateralRecovered is the collateral paid out for the redeemed balances at the reported payouts
+	CollateralRecovered *big.Int
+}
+
+// redeemCallBuilder builds the call redeeming the balances of holdings
+type redeemCallBuilder func(holdings *ConditionHoldings) (contractCall, error)
+
+// findRedeemable returns the resolved conditions account holds outcome tokens of
+func (r *portfolioReader) findRedeemable(ctx context.Context, account common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) ([]*ConditionHoldings, error) {
+	portfolio, err := r.getPortfolio(ctx, account, conditions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var redeemable []*ConditionHoldings
+	for _, holdings := range portfolio.Conditions {
+		if holdings.Resolved && holdings.HasBalance() {
+			redeemable = append(redeemable, holdings)
+		}
+	}
+	return redeemable, nil
+}
+
+// redeemResolved redeems every resolved condition account holds outcome tokens of. A Safe (safeSigner set)
+// redeems them in one MultiSend transaction, an EOA sends a transaction per condition.
+func (r *portfolioReader) redeemResolved(ctx context.Context, e *txExecutor, safeSigner signer.SafeTradingSigner, chainID *big.Int, account common.Address, conditions []PortfolioCondition, buildRegular, buildNegRisk redeemCallBuilder, opts ...PortfolioOption) (*RedeemReport, error) {
+	redeemable, err := r.findRedeemable(ctx, account, conditions, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]contractCall, len(redeemable))
+	for i, holdings := range redeemable {
+		build := buildRegular
+		if holdings.NegRisk {
+			build = buildNegRisk
+		}
+		if calls[i], err = build(holdings); err != nil {
+			return nil, fmt.Errorf("failed to build redemption of condition %x: %w", holdings.ConditionId, err)
+		}
+	}
+	txHashes, sendErr := e.executeMultiSend(ctx, safeSigner, chainID, calls)
+
+	// On a failure only the conditions of the transactions sent are reported
+	report := &RedeemReport{Account: account, TxHashes: txHashes, CollateralRecovered: new(big.Int)}
+	for _, holdings := range redeemable[:sentCalls(safeSigner, len(calls), txHashes, sendErr)] {
+		if holdings.NegRisk {
+			report.NegRisk = append(report.NegRisk, holdings)
+		} else {
+			report.Regular = append(report.Regular, holdings)
+		}
+		report.CollateralRecovered.Add(report.CollateralRecovered, holdings.RedeemableValue)
+	}
+	if sendErr != nil {
+		return report, fmt.Errorf("failed to redeem: %w", sendErr)
+	}
+	return report, nil
+}
+
+// sentCalls returns how many of n calls executeMultiSend sent before failing with err:
+// an EOA sends them in order, one transaction each, a Safe all of them or none
+func sentCalls(safeSigner signer.SafeTradingSigner, n int, txHashes []common.Hash, err error) int {
+	switch {
+	case err == nil:
+		return n
+	case safeSigner == nil:
+		return len(txHashes)
+	default:
+		return 0
+	}
+}
+
+// heldIndexSets returns the index sets of the outcomes with a balance
+func heldIndexSets(holdings *ConditionHoldings) []*big.Int {
+	var indexSets []*big.Int
+	for _, outcome := range holdings.Outcomes {
+		if outcome.Balance.Sign() > 0 {
+			indexSets = append(indexSets, outcome.IndexSet)
+		}
+	}
+	return indexSets
+}
+
+// negRiskRedeemAmounts returns the YES and NO balances of a neg-risk condition, in NegRiskAdapter.redeemPositions order
+func negRiskRedeemAmounts(holdings *ConditionHoldings) []*big.Int {
+	amounts := make([]*big.Int, len(holdings.Outcomes))
+	for i, outcome := range holdings.Outcomes {
+		amounts[i] = outcome.Balance
+	}
+	return amounts
+}
+
+// FindRedeemable returns the resolved conditions account holds outcome tokens of
+func (b *ContractInterface) FindRedeemable(ctx context.Context, account common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) ([]*ConditionHoldings, error) {
+	return b.portfolio.findRedeemable(ctx, account, conditions, opts...)
+}
+
+// RedeemResolved redeems every resolved condition of conditions the configured account holds outcome tokens of, for USDC.e
+func (b *ContractInterface) RedeemResolved(ctx context.Context, conditions []PortfolioCondition, opts ...PortfolioOption) (*RedeemReport, error) {
+	switch b.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		return b.RedeemResolvedForSafe(ctx, b.getSafeTradingSigner(), b.chainID, conditions, opts...)
+	case SignatureTypeEOA:
+		return b.RedeemResolvedForEOA(ctx, b.getEOATradingSigner(), conditions, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported signature type: %v", b.signatureType)
+	}
+}
+
+// RedeemResolvedForEOA redeems the resolved positions of an EOA for USDC.e, one transaction per condition
+func (b *ContractInterface) RedeemResolvedForEOA(ctx context.Context, eoaSigner signer.EOATradingSigner, conditions []PortfolioCondition, opts ...PortfolioOption) (*RedeemReport, error) {
+	owner, err := b.executor.eoaAddress(eoaSigner)
+	if err != nil {
+		return nil, err
+	}
+	return b.portfolio.redeemResolved(ctx, b.executor, nil, nil, owner, conditions, b.redeemCall, b.redeemNegRiskCall, opts...)
+}
+
+// RedeemResolvedForSafe redeems the resolved positions of a Safe for USDC.e in one transaction
+func (b *ContractInterface) RedeemResolvedForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, conditions []PortfolioCondition, opts ...PortfolioOption) (*RedeemReport, error) {
+	safeAddr, err := b.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return b.portfolio.redeemResolved(ctx, b.executor, safeSigner, chainID, safeAddr, conditions, b.redeemCall, b.redeemNegRiskCall, opts...)
+}
+
+func (b *ContractInterface) redeemCall(holdings *ConditionHoldings) (contractCall, error) {
+	return b.redeemCallForCollateral(CollateralUSDCE, holdings.ConditionId, heldIndexSets(holdings))
+}
+
+func (b *ContractInterface) redeemNegRiskCall(holdings *ConditionHoldings) (contractCall, error) {
+	return b.redeemNegRiskCallForCollateral(CollateralUSDCE, holdings.ConditionId, negRiskRedeemAmounts(holdings))
+}
+
+// FindRedeemable returns the resolved conditions account holds outcome tokens of
+func (v *ContractInterfaceV2) FindRedeemable(ctx context.Context, account common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) ([]*ConditionHoldings, error) {
+	return v.portfolio.findRedeemable(ctx, account, conditions, opts...)
+}
+
+// RedeemResolved redeems every resolved condition of conditions the configured account holds outcome tokens of, for pUSD
+func (v *ContractInterfaceV2) RedeemResolved(ctx context.Context, conditions []PortfolioCondition, opts ...PortfolioOption) (*RedeemReport, error) {
+	switch v.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		s, err := v.getSafeTradingSignerOrErr()
+		if err != nil {
+			return nil, err
+		}
+		return v.RedeemResolvedForSafe(ctx, s, v.chainID, conditions, opts...)
+	case SignatureTypeEOA:
+		return v.RedeemResolvedForEOA(ctx, conditions, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported signature type: %v", v.signatureType)
+	}
+}
+
+// RedeemResolvedForEOA redeems the resolved positions of the EOA for pUSD, one transaction per condition
+func (v *ContractInterfaceV2) RedeemResolvedForEOA(ctx context.Context, conditions []PortfolioCondition, opts ...PortfolioOption) (*RedeemReport, error) {
+	eoa, err := v.getEOAAddress()
+	if err != nil {
+		return nil, err
+	}
+	return v.portfolio.redeemResolved(ctx, v.executor, nil, nil, eoa, conditions, v.redeemCall, v.redeemNegRiskCall, opts...)
+}
+
+// RedeemResolvedForSafe redeems the resolved positions of a Safe for pUSD in one transaction
+func (v *ContractInterfaceV2) RedeemResolvedForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, conditions []PortfolioCondition, opts ...PortfolioOption) (*RedeemReport, error) {
+	safeAddr, err := v.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return v.portfolio.redeemResolved(ctx, v.executor, safeSigner, chainID, safeAddr, conditions, v.redeemCall, v.redeemNegRiskCall, opts...)
+}
+
+func (v *ContractInterfaceV2) redeemCall(holdings *ConditionHoldings) (contractCall, error) {
+	return buildAdapterRedeemCall(v.config.CtfCollateralAdapter, holdings.ConditionId, heldIndexSets(holdings))
+}
+
+func (v *ContractInterfaceV2) redeemNegRiskCall(holdings *ConditionHoldings) (contractCall, error) {
+	return buildNegRiskAdapterRedeemCall(v.config.NegRiskCtfCollateralAdapter, holdings.ConditionId, heldIndexSets(holdings))
+}
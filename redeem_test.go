@@ -0,0 +1,154 @@
+package polymarketcontracts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+// newRedeemChain returns a chain where the account holds a winning regular position, a winning neg-risk
+// position and a position of an unresolved condition
+func newRedeemChain(t *testing.T) (*portfolioChain, []PortfolioCondition) {
+	c := MATIC_CONTRACTS
+	chain := newPortfolioChain(t)
+	regular, negRisk, open := [32]byte{0x01}, [32]byte{0x02}, [32]byte{0x03}
+	chain.slots[regular], chain.slots[negRisk], chain.slots[open] = 2, 2, 2
+	chain.payouts[regular] = []int64{1, 0}
+	chain.payouts[negRisk] = []int64{0, 1}
+
+	regularIds, _ := ComputeOutcomePositionIds(c.Collateral, regular, 2)
+	negRiskIds, _ := ComputeOutcomePositionIds(c.NegRiskWrappedCollateral, negRisk, 2)
+	openIds, _ := ComputeOutcomePositionIds(c.Collateral, open, 2)
+	chain.balances[regularIds[0].String()] = 5e6
+	chain.balances[negRiskIds[1].String()] = 2e6
+	chain.balances[openIds[0].String()] = 9e6
+
+	return chain, []PortfolioCondition{{ConditionId: regular}, {ConditionId: negRisk, NegRisk: true}, {ConditionId: open}}
+}
+
+func TestRedeemResolvedForSafe_BatchesRedemptions(t *testing.T) {
+	chain, conditions := newRedeemChain(t)
+	reader, err := newPortfolioReader(MATIC_CONTRACTS, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	var sent []contractCall
+	b := &ContractInterface{
+		contractConfig: MATIC_CONTRACTS,
+		portfolio:      reader,
+		executor: &txExecutor{
+			getSafeAddr: func(common.Address) (common.Address, error) { return testSafe, nil },
+			execSafeTx: func(_ context.Context, _ signer.SafeTradingSigner, _ *big.Int, _, to common.Address, value *big.Int, data []byte, op SafeOperation, _ *big.Int, _ ...sender.SendOption) (common.Hash, error) {
+				sent = append(sent, contractCall{Target: to, Calldata: data, Value: value, Operation: op})
+				return common.HexToHash("0x01"), nil
+			},
+		},
+	}
+
+	report, err := b.RedeemResolvedForSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), conditions)
+	if err != nil {
+		t.Fatalf("RedeemResolvedForSafe: %v", err)
+	}
+	if report.Account != testSafe || len(report.Regular) != 1 || len(report.NegRisk) != 1 || report.CollateralRecovered.Int64() != 7e6 || len(report.TxHashes) != 1 {
+		t.Errorf("report %+v", report)
+	}
+
+	redeem, _ := buildRedeemPositionsCall(MATIC_CONTRACTS.ConditionalTokens, MATIC_CONTRACTS.Collateral, conditions[0].ConditionId, []*big.Int{big.NewInt(1)})
+	redeemNegRisk, _ := buildRedeemNegRiskCall(MATIC_CONTRACTS.NegRiskAdapter, conditions[1].ConditionId, []*big.Int{big.NewInt(0), big.NewInt(2e6)})
+	if len(sent) != 1 || sent[0].Target != SafeMultiSendCallOnly || sent[0].Operation != SafeOperationDelegateCall ||
+		!bytes.Equal(sent[0].Calldata, packMultiSend(t, redeem, redeemNegRisk)) {
+		t.Errorf("sent %+v", sent)
+	}
+}
+
+func TestRedeemResolvedForEOA_SendsPerCondition(t *testing.T) {
+	chain, conditions := newRedeemChain(t)
+	reader, err := newPortfolioReader(MATIC_CONTRACTS, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	eoa := &addrSender{addr: testSafe}
+	v := newV2TestInstance(nil)
+	v.executor.txSender = eoa
+	v.portfolio = reader
+
+	report, err := v.RedeemResolvedForEOA(context.Background(), conditions)
+	if err != nil {
+		t.Fatalf("RedeemResolvedForEOA: %v", err)
+	}
+	if eoa.calls != 2 || len(report.TxHashes) != 2 || report.CollateralRecovered.Int64() != 7e6 {
+		t.Errorf("%d transactions, report %+v", eoa.calls, report)
+	}
+	if eoa.lastTo != MATIC_CONTRACTS.NegRiskCtfCollateralAdapter {
+		t.Errorf("last redemption sent to %s, want NegRiskCtfCollateralAdapter", eoa.lastTo.Hex())
+	}
+
+	// Nothing left to redeem once the balances are gone
+	chain.balances = map[string]int64{}
+	if report, err = v.RedeemResolvedForEOA(context.Background(), conditions); err != nil || len(report.TxHashes) != 0 || eoa.calls != 2 {
+		t.Errorf("got %+v, %v after %d transactions", report, err, eoa.calls)
+	}
+}
+
+// failAfterSender sends ok transactions, then fails
+type failAfterSender struct {
+	addrSender
+	ok int
+}
+
+func (s *failAfterSender) SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error) {
+	if s.calls >= s.ok {
+		s.calls++
+		return common.Hash{}, errors.New("boom")
+	}
+	return s.addrSender.SendEthereumTransaction(to, data, value)
+}
+
+func TestRedeemResolvedForEOA_ReportsOnlySentRedemptions(t *testing.T) {
+	chain, conditions := newRedeemChain(t)
+	reader, err := newPortfolioReader(MATIC_CONTRACTS, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	eoa := &failAfterSender{addrSender: addrSender{addr: testSafe, mockTransactionSender: mockTransactionSender{retHash: common.HexToHash("0x01")}}, ok: 1}
+	v := newV2TestInstance(nil)
+	v.executor.txSender = eoa
+	v.portfolio = reader
+
+	report, err := v.RedeemResolvedForEOA(context.Background(), conditions)
+	if err == nil {
+		t.Fatal("expected the second redemption to fail")
+	}
+	if len(report.TxHashes) != 1 || len(report.Regular) != 1 || len(report.NegRisk) != 0 || report.CollateralRecovered.Int64() != 5e6 {
+		t.Errorf("report %+v, want only the regular condition redeemed", report)
+	}
+}
+
+func TestBuildMultiSendCall(t *testing.T) {
+	approve, _ := buildERC20ApproveCall(testCollateral, testAdapter, big.NewInt(1))
+	redeem, _ := buildRedeemNegRiskCall(testNRAdapter, testCondID, []*big.Int{big.NewInt(1), big.NewInt(0)})
+	batch, err := buildMultiSendCall(SafeMultiSendCallOnly, []contractCall{redeem, approve})
+	if err != nil {
+		t.Fatalf("buildMultiSendCall: %v", err)
+	}
+	if !bytes.Equal(batch.Calldata, packMultiSend(t, redeem, approve)) || batch.Operation != SafeOperationDelegateCall {
+		t.Errorf("batch %+v", batch)
+	}
+	// The redeem is low urgency, the approval is not
+	if batch.Urgency != sender.UrgencyNormal {
+		t.Errorf("urgency %s, want normal", batch.Urgency)
+	}
+
+	if _, err := buildMultiSendCall(SafeMultiSendCallOnly, []contractCall{batch}); err == nil {
+		t.Error("expected a nested delegate call to fail")
+	}
+	if _, err := (&txExecutor{}).executeEOA(context.Background(), batch); err == nil {
+		t.Error("expected an EOA delegate call to fail")
+	}
+}
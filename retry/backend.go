@@ -0,0 +1,115 @@
+package retry
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Backend is a bind.ContractBackend retrying transient errors of the backend it wraps. Contract bindings
+// created with it retry their reads. A broadcast that a node reports as already known succeeds.
+type Backend struct {
+	backend bind.ContractBackend
+	policy  Policy
+}
+
+var _ bind.ContractBackend = (*Backend)(nil)
+
+// NewBackend wraps backend, retrying its calls according to policy
+func NewBackend(backend bind.ContractBackend, policy Policy) *Backend {
+	return &Backend{backend: backend, policy: policy}
+}
+
+func (b *Backend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return DoValue(ctx, b.policy, func() ([]byte, error) {
+		return b.backend.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+func (b *Backend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return DoValue(ctx, b.policy, func() ([]byte, error) {
+		return b.backend.CallContract(ctx, call, blockNumber)
+	})
+}
+
+func (b *Backend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return DoValue(ctx, b.policy, func() (*types.Header, error) {
+		return b.backend.HeaderByNumber(ctx, number)
+	})
+}
+
+func (b *Backend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return DoValue(ctx, b.policy, func() ([]byte, error) {
+		return b.backend.PendingCodeAt(ctx, account)
+	})
+}
+
+func (b *Backend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return DoValue(ctx, b.policy, func() (uint64, error) {
+		return b.backend.PendingNonceAt(ctx, account)
+	})
+}
+
+func (b *Backend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return DoValue(ctx, b.policy, func() (*big.Int, error) {
+		return b.backend.SuggestGasPrice(ctx)
+	})
+}
+
+func (b *Backend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return DoValue(ctx, b.policy, func() (*big.Int, error) {
+		return b.backend.SuggestGasTipCap(ctx)
+	})
+}
+
+func (b *Backend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return DoValue(ctx, b.policy, func() (uint64, error) {
+		return b.backend.EstimateGas(ctx, call)
+	})
+}
+
+// SendTransaction broadcasts tx, retrying transient errors. Sending the same signed transaction again is safe.
+func (b *Backend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return Broadcast(ctx, b.policy, b.backend, tx)
+}
+
+func (b *Backend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return DoValue(ctx, b.policy, func() ([]types.Log, error) {
+		return b.backend.FilterLogs(ctx, query)
+	})
+}
+
+func (b *Backend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return DoValue(ctx, b.policy, func() (ethereum.Subscription, error) {
+		return b.backend.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
+
+// Broadcast sends a signed transaction through client, retrying transient errors. A node reporting the
+// transaction as already known counts as success, which also covers a retry after an attempt that reached
+// the node but timed out. If such an attempt was mined before the retry, the node reports the nonce as
+// too low: when client can look up receipts, the transaction's own receipt then counts as success.
+func Broadcast(ctx context.Context, p Policy, client ethereum.TransactionSender, tx *types.Transaction) error {
+	attempts := 0
+	err := Do(ctx, p, func() error {
+		attempts++
+		return client.SendTransaction(ctx, tx)
+	})
+	if err == nil || IsAlreadyKnown(err) {
+		return nil
+	}
+	if reader, ok := client.(receiptReader); ok && attempts > 1 && Classify(err) == ClassNonceTooLow {
+		if receipt, rerr := reader.TransactionReceipt(ctx, tx.Hash()); rerr == nil && receipt != nil {
+			return nil
+		}
+	}
+	return err
+}
+
+type receiptReader interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
@@ -0,0 +1,189 @@
+// Package retry classifies RPC and transaction errors and retries transient ones with jittered
+// exponential backoff. Reverts and other fatal errors are never retried.
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
+)
+
+// Class is the kind of failure an error reports
+type Class int
+
+const (
+	// ClassFatal errors will fail again: bad arguments, insufficient funds, unknown errors
+	ClassFatal Class = iota
+	// ClassTransient errors are RPC hiccups worth retrying: timeouts, rate limits, dropped connections
+	ClassTransient
+	// ClassNonceTooLow means the nonce is already used by a mined transaction
+	ClassNonceTooLow
+	// ClassUnderpriced means the fees are too low for the node or for replacing a pending transaction
+	ClassUnderpriced
+	// ClassReverted means the call reverted; it is never retried
+	ClassReverted
+	// ClassAlreadyKnown means the node already has the transaction being broadcast, i.e. the broadcast succeeded
+	ClassAlreadyKnown
+)
+
+func (c Class) String() string {
+	switch c {
+	case ClassTransient:
+		return "transient"
+	case ClassNonceTooLow:
+		return "nonce too low"
+	case ClassUnderpriced:
+		return "underpriced"
+	case ClassReverted:
+		return "reverted"
+	case ClassAlreadyKnown:
+		return "already known"
+	default:
+		return "fatal"
+	}
+}
+
+var (
+	alreadyKnownMessages = []string{"already known", "known transaction", "already imported"}
+	nonceTooLowMessages  = []string{"nonce too low", "nonce is too low", "invalid nonce"}
+	underpricedMessages  = []string{"underpriced", "fee too low", "less than block base fee", "gas price too low", "tip too low"}
+	revertedMessages     = []string{"execution reverted", "revert"}
+	transientMessages    = []string{
+		"timeout", "timed out", "deadline exceeded",
+		"too many requests", "rate limit",
+		"connection reset", "connection refused", "broken pipe", "eof",
+		"bad gateway", "service unavailable", "gateway timeout",
+		"header not found", "temporarily unavailable", "try again",
+	}
+)
+
+// Classify returns the class of err. A nil error is ClassFatal; check it before classifying.
+func Classify(err error) Class {
+	if err == nil {
+		return ClassFatal
+	}
+	// The caller gave up: retrying cannot help
+	if errors.Is(err, context.Canceled) {
+		return ClassFatal
+	}
+
+	msg := strings.ToLower(err.Error())
+	var revertErr *revert.Error
+	var dataErr rpc.DataError
+	switch {
+	case containsAny(msg, alreadyKnownMessages):
+		return ClassAlreadyKnown
+	case containsAny(msg, nonceTooLowMessages):
+		return ClassNonceTooLow
+	case containsAny(msg, underpricedMessages):
+		return ClassUnderpriced
+	case errors.As(err, &revertErr), errors.As(err, &dataErr), containsAny(msg, revertedMessages):
+		return ClassReverted
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.StatusCode == 429 || httpErr.StatusCode >= 500 {
+			return ClassTransient
+		}
+		return ClassFatal
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32005 {
+		// Limit exceeded
+		return ClassTransient
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ClassTransient
+	}
+	if containsAny(msg, transientMessages) {
+		return ClassTransient
+	}
+	return ClassFatal
+}
+
+// IsAlreadyKnown reports whether a broadcast failed only because the node already has the transaction
+func IsAlreadyKnown(err error) bool {
+	return err != nil && Classify(err) == ClassAlreadyKnown
+}
+
+func containsAny(msg string, substrings []string) bool {
+	for _, s := range substrings {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy configures how transient errors are retried
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first one; 0 or 1 disables retries
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles with every retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts
+	MaxDelay time.Duration
+}
+
+var (
+	// DefaultPolicy makes up to 4 attempts, waiting about 250ms, 500ms and 1s in between
+	DefaultPolicy = Policy{MaxAttempts: 4, BaseDelay: 250 * time.Millisecond, MaxDelay: 5 * time.Second}
+	// NoRetry makes a single attempt
+	NoRetry = Policy{MaxAttempts: 1}
+)
+
+// Backoff returns the delay before retry number attempt (1 for the first retry): the exponential delay
+// capped at MaxDelay, with the upper half jittered so concurrent callers do not retry in lockstep
+func (p Policy) Backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// Do calls fn until it succeeds, returns an error that is not transient, attempts run out or ctx is done.
+// The last error of fn is returned.
+func Do(ctx context.Context, p Policy, fn func() error) error {
+	_, err := DoValue(ctx, p, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
+}
+
+// DoValue is Do for functions returning a value
+func DoValue[T any](ctx context.Context, p Policy, fn func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		v, err := fn()
+		if err == nil || attempt >= p.MaxAttempts || Classify(err) != ClassTransient {
+			return v, err
+		}
+
+		timer := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return v, err
+		case <-timer.C:
+		}
+	}
+}
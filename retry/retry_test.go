@@ -0,0 +1,139 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
+)
+
+var fastPolicy = Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Class
+	}{
+		{context.DeadlineExceeded, ClassTransient},
+		{fmt.Errorf("failed to call: %w", syscall.ECONNRESET), ClassTransient},
+		{io.ErrUnexpectedEOF, ClassTransient},
+		{rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}, ClassTransient},
+		{rpc.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}, ClassTransient},
+		{rpc.HTTPError{StatusCode: 401, Status: "401 Unauthorized"}, ClassFatal},
+		{errors.New("Post \"https://polygon-rpc.com\": dial tcp: i/o timeout"), ClassTransient},
+		{errors.New("nonce too low: next nonce 5, tx nonce 4"), ClassNonceTooLow},
+		{errors.New("replacement transaction underpriced"), ClassUnderpriced},
+		{errors.New("transaction underpriced: tip needed 30000000000"), ClassUnderpriced},
+		{errors.New("execution reverted: ERC20: transfer amount exceeds balance"), ClassReverted},
+		{&revert.Error{Contract: "ExchangeV2", Name: "Paused"}, ClassReverted},
+		{errors.New("already known"), ClassAlreadyKnown},
+		{errors.New("insufficient funds for gas * price + value"), ClassFatal},
+		{context.Canceled, ClassFatal},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.err); got != tt.want {
+			t.Errorf("Classify(%q) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDoRetriesTransientErrorsOnly(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), fastPolicy, func() error {
+		calls++
+		if calls < 3 {
+			return syscall.ECONNRESET
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("Do = %v after %d calls, want success after 3", err, calls)
+	}
+
+	calls = 0
+	reverted := errors.New("execution reverted")
+	if err := Do(context.Background(), fastPolicy, func() error { calls++; return reverted }); err != reverted || calls != 1 {
+		t.Fatalf("revert: Do = %v after %d calls, want a single attempt", err, calls)
+	}
+
+	calls = 0
+	if err := Do(context.Background(), fastPolicy, func() error { calls++; return context.DeadlineExceeded }); err == nil || calls != 3 {
+		t.Fatalf("Do = %v after %d calls, want failure after 3", err, calls)
+	}
+}
+
+func TestDoStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	slow := Policy{MaxAttempts: 5, BaseDelay: time.Hour}
+	if err := Do(ctx, slow, func() error { calls++; return syscall.ECONNREFUSED }); err == nil || calls != 1 {
+		t.Fatalf("Do = %v after %d calls, want one attempt", err, calls)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 5: 300 * time.Millisecond} {
+		for range 20 {
+			if d := p.Backoff(attempt); d < max/2 || d > max {
+				t.Fatalf("Backoff(%d) = %v, want within [%v, %v]", attempt, d, max/2, max)
+			}
+		}
+	}
+}
+
+type sendClient struct {
+	errs    []error
+	sent    int
+	receipt *types.Receipt
+}
+
+func (c *sendClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.sent++
+	if len(c.errs) == 0 {
+		return nil
+	}
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return err
+}
+
+func (c *sendClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if c.receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	return c.receipt, nil
+}
+
+func TestBroadcast(t *testing.T) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: 4, GasPrice: big.NewInt(1)})
+
+	known := &sendClient{errs: []error{errors.New("already known")}}
+	if err := Broadcast(context.Background(), fastPolicy, known, tx); err != nil {
+		t.Errorf("already known: %v", err)
+	}
+
+	// The first attempt timed out but was mined before the retry
+	mined := &sendClient{errs: []error{context.DeadlineExceeded, errors.New("nonce too low")}, receipt: &types.Receipt{}}
+	if err := Broadcast(context.Background(), fastPolicy, mined, tx); err != nil || mined.sent != 2 {
+		t.Errorf("mined before retry: %v after %d sends", err, mined.sent)
+	}
+
+	// Without a retry, nonce too low means the nonce was used by another transaction
+	stale := &sendClient{errs: []error{errors.New("nonce too low")}, receipt: &types.Receipt{}}
+	if err := Broadcast(context.Background(), fastPolicy, stale, tx); Classify(err) != ClassNonceTooLow {
+		t.Errorf("stale nonce: err = %v", err)
+	}
+}
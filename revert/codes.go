@@ -0,0 +1,46 @@
+package revert
+
+// safeErrorCodes describes the GS0xx reasons Gnosis Safe reverts with
+var safeErrorCodes = map[string]string{
+	"GS000": "could not finish initialization",
+	"GS001": "threshold needs to be defined",
+	"GS010": "not enough gas to execute Safe transaction",
+	"GS011": "could not pay gas costs with ether",
+	"GS012": "could not pay gas costs with token",
+	"GS013": "Safe transaction failed when gasPrice and safeTxGas were 0",
+	"GS020": "signatures data too short",
+	"GS021": "invalid contract signature location: inside static part",
+	"GS022": "invalid contract signature location: length not present",
+	"GS023": "invalid contract signature location: data not complete",
+	"GS024": "invalid contract signature provided",
+	"GS025": "hash has not been approved",
+	"GS026": "invalid owner provided",
+	"GS030": "only owners can approve a hash",
+	"GS031": "method can only be called from this contract",
+	"GS100": "modules have already been initialized",
+	"GS101": "invalid module address provided",
+	"GS102": "module has already been added",
+	"GS103": "invalid prevModule, module pair provided",
+	"GS104": "method can only be called from an enabled module",
+	"GS200": "owners have already been setup",
+	"GS201": "threshold cannot exceed owner count",
+	"GS202": "threshold needs to be greater than 0",
+	"GS203": "invalid owner address provided",
+	"GS204": "address is already an owner",
+	"GS205": "invalid prevOwner, owner pair provided",
+	"GS300": "guard does not implement IERC165",
+}
+
+// panicReasons describes the Solidity Panic(uint256) codes
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
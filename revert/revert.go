@@ -0,0 +1,351 @@
+// Package revert decodes EVM revert data into readable errors using the ABIs bundled in contracts/.
+//
+// Custom errors of every bundled contract are recognized, as well as the standard Error(string)
+// and Panic(uint256) reverts and the GS0xx codes used by Gnosis Safe:
+//
+//	failed to estimate gas: ExchangeV2: OrderExpired()
+//	signature verification failed: GnosisSafe: GS026 (invalid owner provided)
+package revert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	collateral_offramp "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/collateral-offramp"
+	collateral_onramp "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/collateral-onramp"
+	collateral_token "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/collateral-token"
+	ctf_collateral_adapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/ctf-collateral-adapter"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/exchange"
+	exchangefees "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/exchange-fees"
+	exchange_v2 "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/exchange-v2"
+	negrisk "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk"
+	negriskadapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-adapter"
+	neg_risk_ctf_collateral_adapter "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-ctf-collateral-adapter"
+	negriskfees "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-fees"
+	neg_risk_v2 "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/neg-risk-v2"
+	permissioned_ramp "github.com/ivanzzeth/polymarket-go-contracts/v2/contracts/permissioned-ramp"
+)
+
+// ErrTransactionFailed is wrapped by errors describing a mined transaction with a failed receipt status
+var ErrTransactionFailed = errors.New("transaction failed")
+
+var (
+	errorStringSelector = [4]byte(crypto.Keccak256([]byte("Error(string)"))[:4])
+	panicSelector       = [4]byte(crypto.Keccak256([]byte("Panic(uint256)"))[:4])
+)
+
+// Error is a decoded revert
+type Error struct {
+	// Contract is the name of the contract that defines the error ("ExchangeV2", "GnosisSafe", ...).
+	// Empty for Error(string) and Panic(uint256) reverts from unknown contracts.
+	Contract string
+	// Name is the custom error name, "Error" or "Panic"
+	Name string
+	// Args are the decoded error arguments
+	Args []interface{}
+	// Reason is the human-readable reason for Error(string), Panic(uint256) and GS0xx reverts
+	Reason string
+	// Data is the raw revert data
+	Data []byte
+
+	cause error
+}
+
+// Error formats the revert like "ExchangeV2: OrderExpired()"
+func (e *Error) Error() string {
+	var msg string
+	switch e.Name {
+	case "Error", "Panic":
+		msg = e.Reason
+	default:
+		args := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = formatArg(arg)
+		}
+		msg = fmt.Sprintf("%s(%s)", e.Name, strings.Join(args, ", "))
+	}
+
+	if e.Contract == "" {
+		return "execution reverted: " + msg
+	}
+	return e.Contract + ": " + msg
+}
+
+// Unwrap returns the original error the revert data was extracted from, if any
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func formatArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case common.Address:
+		return v.Hex()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+type namedError struct {
+	contract string
+	abiError abi.Error
+}
+
+// Decoder maps 4-byte error selectors to the contracts that define them.
+// A Decoder is safe for concurrent use by multiple goroutines.
+type Decoder struct {
+	mu        sync.RWMutex
+	errors    map[[4]byte][]namedError
+	addresses map[common.Address]string
+}
+
+// NewDecoder creates an empty Decoder
+func NewDecoder() *Decoder {
+	return &Decoder{
+		errors:    make(map[[4]byte][]namedError),
+		addresses: make(map[common.Address]string),
+	}
+}
+
+var (
+	defaultDecoder     *Decoder
+	defaultDecoderOnce sync.Once
+)
+
+// bundledABIs lists the bundled bindings with custom errors, in lookup order for selectors shared by several contracts
+var bundledABIs = []struct {
+	name     string
+	metadata interface{ GetAbi() (*abi.ABI, error) }
+}{
+	{"ExchangeV2", exchange_v2.ExchangeV2MetaData},
+	{"NegRiskExchangeV2", neg_risk_v2.NegRiskV2MetaData},
+	{"Exchange", exchange.ExchangeMetaData},
+	{"NegRiskExchange", negrisk.NegRiskMetaData},
+	{"NegRiskAdapter", negriskadapter.NegRiskAdapterMetaData},
+	{"CollateralToken", collateral_token.CollateralTokenMetaData},
+	{"CollateralOnramp", collateral_onramp.CollateralOnrampMetaData},
+	{"CollateralOfframp", collateral_offramp.CollateralOfframpMetaData},
+	{"CtfCollateralAdapter", ctf_collateral_adapter.CtfCollateralAdapterMetaData},
+	{"NegRiskCtfCollateralAdapter", neg_risk_ctf_collateral_adapter.NegRiskCtfCollateralAdapterMetaData},
+	{"PermissionedRamp", permissioned_ramp.PermissionedRampMetaData},
+	{"ExchangeFees", exchangefees.ExchangeFeesMetaData},
+	{"NegRiskFees", negriskfees.NegRiskFeesMetaData},
+}
+
+// Default returns the shared Decoder loaded with the errors of every bundled contract
+func Default() *Decoder {
+	defaultDecoderOnce.Do(func() {
+		defaultDecoder = NewDecoder()
+		for _, b := range bundledABIs {
+			parsed, err := b.metadata.GetAbi()
+			if err != nil {
+				continue
+			}
+			defaultDecoder.RegisterABI(b.name, parsed)
+		}
+	})
+	return defaultDecoder
+}
+
+// RegisterABI adds the custom errors of a contract ABI
+func (d *Decoder) RegisterABI(contract string, parsed *abi.ABI) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, e := range parsed.Errors {
+		var selector [4]byte
+		copy(selector[:], e.ID[:4])
+		d.errors[selector] = append(d.errors[selector], namedError{contract: contract, abiError: e})
+	}
+}
+
+// RegisterAddress names the contract deployed at addr.
+// When a selector is defined by several contracts, the name of the called address wins.
+func (d *Decoder) RegisterAddress(addr common.Address, contract string) {
+	if addr == (common.Address{}) {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.addresses[addr] = contract
+}
+
+// Decode decodes revert data returned by a call to `to` (nil if unknown).
+// It returns false if the data is empty or its selector is unknown.
+func (d *Decoder) Decode(to *common.Address, data []byte) (*Error, bool) {
+	if len(data) < 4 {
+		return nil, false
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var hint string
+	if to != nil {
+		hint = d.addresses[*to]
+	}
+
+	selector := [4]byte(data[:4])
+	switch selector {
+	case errorStringSelector:
+		return decodeErrorString(hint, data)
+	case panicSelector:
+		return decodePanic(hint, data)
+	}
+
+	candidates := d.errors[selector]
+	if len(candidates) == 0 {
+		return nil, false
+	}
+	chosen := candidates[0]
+	for _, c := range candidates {
+		if c.contract == hint {
+			chosen = c
+			break
+		}
+	}
+
+	args, err := chosen.abiError.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, false
+	}
+	return &Error{Contract: chosen.contract, Name: chosen.abiError.Name, Args: args, Data: data}, true
+}
+
+func decodeErrorString(contract string, data []byte) (*Error, bool) {
+	reason, err := abi.UnpackRevert(data)
+	if err != nil {
+		return nil, false
+	}
+	e := &Error{Contract: contract, Name: "Error", Args: []interface{}{reason}, Reason: reason, Data: data}
+	if desc, ok := safeErrorCodes[reason]; ok {
+		e.Contract = "GnosisSafe"
+		e.Reason = fmt.Sprintf("%s (%s)", reason, desc)
+	}
+	return e, true
+}
+
+func decodePanic(contract string, data []byte) (*Error, bool) {
+	if len(data) != 4+32 {
+		return nil, false
+	}
+	code := new(big.Int).SetBytes(data[4:])
+	reason := fmt.Sprintf("panic: unknown code 0x%x", code)
+	if code.IsUint64() {
+		if desc, ok := panicReasons[code.Uint64()]; ok {
+			reason = fmt.Sprintf("panic: %s (0x%02x)", desc, code.Uint64())
+		}
+	}
+	return &Error{Contract: contract, Name: "Panic", Args: []interface{}{code}, Reason: reason, Data: data}, true
+}
+
+// ExtractData returns the revert data carried by an RPC error, if any
+func ExtractData(err error) ([]byte, bool) {
+	var dataErr interface{ ErrorData() interface{} }
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	switch v := dataErr.ErrorData().(type) {
+	case string:
+		data, decodeErr := hexutil.Decode(v)
+		if decodeErr != nil || len(data) == 0 {
+			return nil, false
+		}
+		return data, true
+	case []byte:
+		return v, len(v) > 0
+	default:
+		return nil, false
+	}
+}
+
+// Wrap decodes the revert data carried by err, returned by a call or estimate against `to` (nil if unknown).
+// If decoding succeeds, the returned *Error reads like "ExchangeV2: OrderExpired()" and unwraps to err.
+// Otherwise err is returned unchanged.
+func (d *Decoder) Wrap(to *common.Address, err error) error {
+	if err == nil {
+		return nil
+	}
+	var decoded *Error
+	if errors.As(err, &decoded) {
+		return err
+	}
+	data, ok := ExtractData(err)
+	if !ok {
+		return err
+	}
+	decoded, ok = d.Decode(to, data)
+	if !ok {
+		return err
+	}
+	decoded.cause = err
+	return decoded
+}
+
+// Wrap decodes err with the Default decoder
+func Wrap(to *common.Address, err error) error {
+	return Default().Wrap(to, err)
+}
+
+// Replayer is the subset of the Ethereum client needed to replay a failed transaction
+type Replayer interface {
+	TransactionByHash(ctx context.Context, txHash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// ReceiptError returns nil for a successful receipt. For a failed one, it replays the transaction
+// with eth_call at its block to recover the revert reason, and returns an error wrapping
+// ErrTransactionFailed (and the decoded *Error when the reason is known).
+func (d *Decoder) ReceiptError(ctx context.Context, client Replayer, receipt *types.Receipt) error {
+	if receipt == nil || receipt.Status == types.ReceiptStatusSuccessful {
+		return nil
+	}
+	failed := fmt.Errorf("%w: tx %s reverted", ErrTransactionFailed, receipt.TxHash.Hex())
+
+	tx, _, err := client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil || tx == nil {
+		return failed
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return failed
+	}
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	_, callErr := client.CallContract(ctx, msg, receipt.BlockNumber)
+	if callErr == nil {
+		return failed
+	}
+
+	decoded := d.Wrap(tx.To(), callErr)
+	var revertErr *Error
+	if errors.As(decoded, &revertErr) {
+		return fmt.Errorf("%w: tx %s reverted: %w", ErrTransactionFailed, receipt.TxHash.Hex(), revertErr)
+	}
+	return fmt.Errorf("%w: tx %s reverted: %v", ErrTransactionFailed, receipt.TxHash.Hex(), callErr)
+}
+
+// ReceiptError checks receipt with the Default decoder
+func ReceiptError(ctx context.Context, client Replayer, receipt *types.Receipt) error {
+	return Default().ReceiptError(ctx, client, receipt)
+}
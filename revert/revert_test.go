@@ -0,0 +1,185 @@
+package revert
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// rpcDataError mimics the JSON-RPC error returned by nodes for reverted calls
+type rpcDataError struct {
+	data string
+}
+
+func (e *rpcDataError) Error() string          { return "execution reverted" }
+func (e *rpcDataError) ErrorData() interface{} { return e.data }
+
+func selector(sig string) []byte {
+	return crypto.Keccak256([]byte(sig))[:4]
+}
+
+func errorStringData(t *testing.T, reason string) []byte {
+	t.Helper()
+	strType, _ := abi.NewType("string", "", nil)
+	packed, err := abi.Arguments{{Type: strType}}.Pack(reason)
+	if err != nil {
+		t.Fatalf("pack: %v", err)
+	}
+	return append(selector("Error(string)"), packed...)
+}
+
+func TestDecode_CustomError(t *testing.T) {
+	e, ok := Default().Decode(nil, selector("NotCrossing()"))
+	if !ok {
+		t.Fatal("expected NotCrossing() to be decoded")
+	}
+	if e.Name != "NotCrossing" || e.Contract != "ExchangeV2" {
+		t.Errorf("unexpected decode: %+v", e)
+	}
+	if e.Error() != "ExchangeV2: NotCrossing()" {
+		t.Errorf("unexpected message %q", e.Error())
+	}
+}
+
+func TestDecode_AddressHintPicksContract(t *testing.T) {
+	d := NewDecoder()
+	for _, b := range bundledABIs {
+		parsed, err := b.metadata.GetAbi()
+		if err != nil {
+			t.Fatalf("GetAbi %s: %v", b.name, err)
+		}
+		d.RegisterABI(b.name, parsed)
+	}
+	exchangeAddr := common.HexToAddress("0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E")
+	d.RegisterAddress(exchangeAddr, "Exchange")
+
+	e, ok := d.Decode(&exchangeAddr, selector("Paused()"))
+	if !ok {
+		t.Fatal("expected Paused() to be decoded")
+	}
+	if e.Error() != "Exchange: Paused()" {
+		t.Errorf("expected address hint to pick Exchange, got %q", e.Error())
+	}
+
+	e, _ = d.Decode(&exchangeAddr, selector("OrderExpired()"))
+	if e == nil || e.Error() != "Exchange: OrderExpired()" {
+		t.Errorf("unexpected decode %v", e)
+	}
+}
+
+func TestDecode_CustomErrorArgs(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"error","name":"TooLow","inputs":[{"name":"got","type":"uint256"},{"name":"who","type":"address"}]}]`))
+	if err != nil {
+		t.Fatalf("abi: %v", err)
+	}
+	d := NewDecoder()
+	d.RegisterABI("Test", &parsed)
+
+	who := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	packed, _ := parsed.Errors["TooLow"].Inputs.Pack(big.NewInt(7), who)
+	e, ok := d.Decode(nil, append(selector("TooLow(uint256,address)"), packed...))
+	if !ok {
+		t.Fatal("expected TooLow to be decoded")
+	}
+	if want := "Test: TooLow(7, " + who.Hex() + ")"; e.Error() != want {
+		t.Errorf("expected %q, got %q", want, e.Error())
+	}
+}
+
+func TestDecode_ErrorStringAndSafeCodes(t *testing.T) {
+	e, ok := Default().Decode(nil, errorStringData(t, "not enough balance"))
+	if !ok || e.Error() != "execution reverted: not enough balance" {
+		t.Errorf("unexpected Error(string) decode: %v", e)
+	}
+
+	e, ok = Default().Decode(nil, errorStringData(t, "GS026"))
+	if !ok || e.Error() != "GnosisSafe: GS026 (invalid owner provided)" {
+		t.Errorf("unexpected GS code decode: %v", e)
+	}
+}
+
+func TestDecode_Panic(t *testing.T) {
+	data := append(selector("Panic(uint256)"), common.LeftPadBytes([]byte{0x11}, 32)...)
+	e, ok := Default().Decode(nil, data)
+	if !ok {
+		t.Fatal("expected panic to be decoded")
+	}
+	if e.Error() != "execution reverted: panic: arithmetic underflow or overflow (0x11)" {
+		t.Errorf("unexpected message %q", e.Error())
+	}
+}
+
+func TestDecode_Unknown(t *testing.T) {
+	if _, ok := Default().Decode(nil, []byte{0xde, 0xad, 0xbe, 0xef}); ok {
+		t.Error("unknown selector must not decode")
+	}
+	if _, ok := Default().Decode(nil, nil); ok {
+		t.Error("empty data must not decode")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	cause := &rpcDataError{data: hexutil.Encode(selector("NotCrossing()"))}
+	err := Wrap(nil, cause)
+
+	var revertErr *Error
+	if !errors.As(err, &revertErr) {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("wrapped error must unwrap to the original error")
+	}
+	if err.Error() != "ExchangeV2: NotCrossing()" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	plain := errors.New("connection refused")
+	if Wrap(nil, plain) != plain {
+		t.Error("errors without revert data must be returned unchanged")
+	}
+}
+
+type mockReplayer struct {
+	tx      *types.Transaction
+	callErr error
+}
+
+func (m *mockReplayer) TransactionByHash(_ context.Context, _ common.Hash) (*types.Transaction, bool, error) {
+	return m.tx, false, nil
+}
+
+func (m *mockReplayer) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	return nil, m.callErr
+}
+
+func TestReceiptError(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	tx, _ := types.SignTx(types.NewTransaction(0, to, big.NewInt(0), 100000, big.NewInt(1), nil), types.LatestSignerForChainID(big.NewInt(137)), key)
+
+	replayer := &mockReplayer{tx: tx, callErr: &rpcDataError{data: hexutil.Encode(errorStringData(t, "GS013"))}}
+
+	ok := &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()}
+	if err := ReceiptError(context.Background(), replayer, ok); err != nil {
+		t.Errorf("successful receipt must not error, got %v", err)
+	}
+
+	failed := &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: tx.Hash(), BlockNumber: big.NewInt(10)}
+	err := ReceiptError(context.Background(), replayer, failed)
+	if !errors.Is(err, ErrTransactionFailed) {
+		t.Fatalf("expected ErrTransactionFailed, got %v", err)
+	}
+	var revertErr *Error
+	if !errors.As(err, &revertErr) || !strings.Contains(err.Error(), "GnosisSafe: GS013") {
+		t.Errorf("expected decoded Safe reason, got %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// ErrGasPriceAboveCap is returned by a CappedGasPricer with CapPolicyFail when the network price exceeds the cap
+var ErrGasPriceAboveCap = errors.New("gas price above cap")
+
+// Urgency tells a GasPricer how fast a transaction needs to be included
+type Urgency int
+
+const (
+	// UrgencyNormal is the default urgency
+	UrgencyNormal Urgency = iota
+	// UrgencyLow is for transactions that can wait for cheaper gas, such as redeems
+	UrgencyLow
+	// UrgencyHigh is for transactions that must be included fast, such as cancellations
+	UrgencyHigh
+)
+
+// String returns the urgency name
+func (u Urgency) String() string {
+	switch u {
+	case UrgencyLow:
+		return "low"
+	case UrgencyNormal:
+		return "normal"
+	case UrgencyHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("Urgency(%d)", int(u))
+	}
+}
+
+// GasFees are the fees chosen for a transaction.
+// GasPrice is set for legacy transactions, GasFeeCap and GasTipCap for EIP-1559 transactions.
+type GasFees struct {
+	GasPrice  *big.Int
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+	// BaseFee is the expected base fee of the next block, if known
+	BaseFee *big.Int
+}
+
+// IsDynamic reports whether the fees are for an EIP-1559 transaction
+func (f *GasFees) IsDynamic() bool {
+	return f.GasFeeCap != nil
+}
+
+// Price returns the expected price per gas: the gas price, or base fee + tip capped by the fee cap.
+// Without a known base fee it returns the fee cap.
+func (f *GasFees) Price() *big.Int {
+	if !f.IsDynamic() {
+		return f.GasPrice
+	}
+	if f.BaseFee == nil {
+		return f.GasFeeCap
+	}
+	price := new(big.Int).Add(f.BaseFee, f.GasTipCap)
+	if price.Cmp(f.GasFeeCap) > 0 {
+		return f.GasFeeCap
+	}
+	return price
+}
+
+// SendOptions returns the SendOptions setting these fees explicitly
+func (f *GasFees) SendOptions() []SendOption {
+	if f.IsDynamic() {
+		return []SendOption{WithFeeCaps(f.GasFeeCap, f.GasTipCap)}
+	}
+	return []SendOption{WithGasPrice(f.GasPrice)}
+}
+
+// GasPricer chooses the fees of a transaction
+type GasPricer interface {
+	GasFees(ctx context.Context, urgency Urgency) (*GasFees, error)
+}
+
+// MultiplierGasPricer applies a percentage per urgency to the node's suggested gas price (legacy fees)
+type MultiplierGasPricer struct {
+	client   ethereum.GasPricer
+	percents map[Urgency]int64
+}
+
+// NewMultiplierGasPricer creates a MultiplierGasPricer. Percentages are of the suggested gas price, e.g. 130 for 1.3x.
+func NewMultiplierGasPricer(client ethereum.GasPricer, lowPercent, normalPercent, highPercent int64) *MultiplierGasPricer {
+	return &MultiplierGasPricer{
+		client: client,
+		percents: map[Urgency]int64{
+			UrgencyLow:    lowPercent,
+			UrgencyNormal: normalPercent,
+			UrgencyHigh:   highPercent,
+		},
+	}
+}
+
+// NewDefaultGasPricer returns the pricer senders use by default: 1x, 1.3x and 2x the suggested gas price
+func NewDefaultGasPricer(client ethereum.GasPricer) *MultiplierGasPricer {
+	return NewMultiplierGasPricer(client, 100, 130, 200)
+}
+
+// GasFees implements GasPricer
+func (p *MultiplierGasPricer) GasFees(ctx context.Context, urgency Urgency) (*GasFees, error) {
+	percent, ok := p.percents[urgency]
+	if !ok {
+		return nil, fmt.Errorf("unknown urgency %s", urgency)
+	}
+	suggested, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	gasPrice := new(big.Int).Mul(suggested, big.NewInt(percent))
+	gasPrice.Div(gasPrice, big.NewInt(100))
+	return &GasFees{GasPrice: gasPrice}, nil
+}
+
+// FeeHistoryGasPricer prices EIP-1559 transactions from eth_feeHistory: the tip is the median,
+// over recent blocks, of the priority fee at the urgency's percentile, and the fee cap leaves
+// room for the base fee to double.
+type FeeHistoryGasPricer struct {
+	client      ethereum.FeeHistoryReader
+	blocks      uint64
+	percentiles map[Urgency]float64
+	minTip      *big.Int
+}
+
+// FeeHistoryOption configures optional fields on FeeHistoryGasPricer
+type FeeHistoryOption func(p *FeeHistoryGasPricer)
+
+// WithFeeHistoryBlocks sets how many recent blocks are sampled (default: 20)
+func WithFeeHistoryBlocks(blocks uint64) FeeHistoryOption {
+	return func(p *FeeHistoryGasPricer) {
+		p.blocks = blocks
+	}
+}
+
+// WithFeeHistoryPercentiles sets the priority fee percentile per urgency (default: 25, 50, 90)
+func WithFeeHistoryPercentiles(low, normal, high float64) FeeHistoryOption {
+	return func(p *FeeHistoryGasPricer) {
+		p.percentiles = map[Urgency]float64{UrgencyLow: low, UrgencyNormal: normal, UrgencyHigh: high}
+	}
+}
+
+// WithMinTip sets a floor for the tip, e.g. the minimum priority fee a chain enforces
+func WithMinTip(minTip *big.Int) FeeHistoryOption {
+	return func(p *FeeHistoryGasPricer) {
+		p.minTip = minTip
+	}
+}
+
+// NewFeeHistoryGasPricer creates a FeeHistoryGasPricer
+func NewFeeHistoryGasPricer(client ethereum.FeeHistoryReader, opts ...FeeHistoryOption) *FeeHistoryGasPricer {
+	p := &FeeHistoryGasPricer{
+		client:      client,
+		blocks:      20,
+		percentiles: map[Urgency]float64{UrgencyLow: 25, UrgencyNormal: 50, UrgencyHigh: 90},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// GasFees implements GasPricer
+func (p *FeeHistoryGasPricer) GasFees(ctx context.Context, urgency Urgency) (*GasFees, error) {
+	percentile, ok := p.percentiles[urgency]
+	if !ok {
+		return nil, fmt.Errorf("unknown urgency %s", urgency)
+	}
+	history, err := p.client.FeeHistory(ctx, p.blocks, nil, []float64{percentile})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, errors.New("fee history returned no base fee")
+	}
+	// The last base fee is the one of the next block
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	rewards := make([]*big.Int, 0, len(history.Reward))
+	for _, blockRewards := range history.Reward {
+		if len(blockRewards) > 0 && blockRewards[0] != nil {
+			rewards = append(rewards, blockRewards[0])
+		}
+	}
+	tip := big.NewInt(0)
+	if len(rewards) > 0 {
+		sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+		tip = new(big.Int).Set(rewards[len(rewards)/2])
+	}
+	if p.minTip != nil && tip.Cmp(p.minTip) < 0 {
+		tip = new(big.Int).Set(p.minTip)
+	}
+
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+	feeCap.Add(feeCap, tip)
+	return &GasFees{GasFeeCap: feeCap, GasTipCap: tip, BaseFee: new(big.Int).Set(baseFee)}, nil
+}
+
+// CapPolicy decides what a CappedGasPricer does when the network price is above the cap
+type CapPolicy int
+
+const (
+	// CapPolicyFail returns ErrGasPriceAboveCap
+	CapPolicyFail CapPolicy = iota
+	// CapPolicyWait polls until the price drops under the cap or ctx is done.
+	// UrgencyHigh never waits and fails like CapPolicyFail.
+	CapPolicyWait
+)
+
+// CappedGasPricer enforces a hard cap on the price per gas chosen by another GasPricer.
+// EIP-1559 fee caps above the cap are lowered to it.
+type CappedGasPricer struct {
+	pricer       GasPricer
+	maxPrice     *big.Int
+	policy       CapPolicy
+	pollInterval time.Duration
+}
+
+// CappedGasPricerOption configures optional fields on CappedGasPricer
+type CappedGasPricerOption func(p *CappedGasPricer)
+
+// WithCapPollInterval sets how often the price is checked again under CapPolicyWait (default: 15 seconds)
+func WithCapPollInterval(d time.Duration) CappedGasPricerOption {
+	return func(p *CappedGasPricer) {
+		p.pollInterval = d
+	}
+}
+
+// NewCappedGasPricer creates a CappedGasPricer around pricer
+func NewCappedGasPricer(pricer GasPricer, maxPrice *big.Int, policy CapPolicy, opts ...CappedGasPricerOption) *CappedGasPricer {
+	p := &CappedGasPricer{pricer: pricer, maxPrice: maxPrice, policy: policy, pollInterval: 15 * time.Second}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// GasFees implements GasPricer
+func (p *CappedGasPricer) GasFees(ctx context.Context, urgency Urgency) (*GasFees, error) {
+	for {
+		fees, err := p.pricer.GasFees(ctx, urgency)
+		if err != nil {
+			return nil, err
+		}
+		price := fees.Price()
+		if price.Cmp(p.maxPrice) <= 0 {
+			if fees.IsDynamic() && fees.GasFeeCap.Cmp(p.maxPrice) > 0 {
+				fees.GasFeeCap = new(big.Int).Set(p.maxPrice)
+			}
+			return fees, nil
+		}
+		if p.policy != CapPolicyWait || urgency == UrgencyHigh {
+			return nil, fmt.Errorf("%w: %s > %s", ErrGasPriceAboveCap, price, p.maxPrice)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gas price %s still above cap %s: %w", price, p.maxPrice, ctx.Err())
+		case <-time.After(p.pollInterval):
+		}
+	}
+}
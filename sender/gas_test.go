@@ -0,0 +1,129 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+type fixedGasPriceClient struct {
+	price *big.Int
+}
+
+func (c *fixedGasPriceClient) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return new(big.Int).Set(c.price), nil
+}
+
+type feeHistoryClient struct {
+	history     *ethereum.FeeHistory
+	percentiles []float64
+}
+
+func (c *feeHistoryClient) FeeHistory(_ context.Context, _ uint64, _ *big.Int, percentiles []float64) (*ethereum.FeeHistory, error) {
+	c.percentiles = percentiles
+	return c.history, nil
+}
+
+// sequenceGasPricer returns legacy fees with the given prices in order, repeating the last one
+type sequenceGasPricer struct {
+	prices []int64
+	calls  int
+}
+
+func (p *sequenceGasPricer) GasFees(_ context.Context, _ Urgency) (*GasFees, error) {
+	i := min(p.calls, len(p.prices)-1)
+	p.calls++
+	return &GasFees{GasPrice: big.NewInt(p.prices[i])}, nil
+}
+
+func TestMultiplierGasPricer_Urgency(t *testing.T) {
+	p := NewDefaultGasPricer(&fixedGasPriceClient{price: big.NewInt(100)})
+
+	for urgency, want := range map[Urgency]int64{UrgencyLow: 100, UrgencyNormal: 130, UrgencyHigh: 200} {
+		fees, err := p.GasFees(context.Background(), urgency)
+		if err != nil {
+			t.Fatalf("%s: %v", urgency, err)
+		}
+		if fees.IsDynamic() || fees.GasPrice.Int64() != want {
+			t.Errorf("%s: expected legacy gas price %d, got %+v", urgency, want, fees)
+		}
+	}
+}
+
+func TestFeeHistoryGasPricer(t *testing.T) {
+	client := &feeHistoryClient{history: &ethereum.FeeHistory{
+		Reward:  [][]*big.Int{{big.NewInt(5)}, {big.NewInt(1)}, {big.NewInt(3)}},
+		BaseFee: []*big.Int{big.NewInt(90), big.NewInt(95), big.NewInt(100), big.NewInt(110)},
+	}}
+	p := NewFeeHistoryGasPricer(client, WithMinTip(big.NewInt(2)))
+
+	fees, err := p.GasFees(context.Background(), UrgencyHigh)
+	if err != nil {
+		t.Fatalf("GasFees: %v", err)
+	}
+	if len(client.percentiles) != 1 || client.percentiles[0] != 90 {
+		t.Errorf("expected the high urgency percentile, got %v", client.percentiles)
+	}
+	// Median tip of 1, 3, 5; fee cap is twice the next base fee plus the tip
+	if fees.GasTipCap.Int64() != 3 || fees.GasFeeCap.Int64() != 223 || fees.BaseFee.Int64() != 110 {
+		t.Errorf("unexpected fees: tip=%s feeCap=%s baseFee=%s", fees.GasTipCap, fees.GasFeeCap, fees.BaseFee)
+	}
+	if fees.Price().Int64() != 113 {
+		t.Errorf("expected price base fee + tip = 113, got %s", fees.Price())
+	}
+
+	client.history.Reward = nil
+	if fees, _ = p.GasFees(context.Background(), UrgencyLow); fees.GasTipCap.Int64() != 2 {
+		t.Errorf("expected the minimum tip without rewards, got %s", fees.GasTipCap)
+	}
+}
+
+func TestCappedGasPricer_Fail(t *testing.T) {
+	p := NewCappedGasPricer(&sequenceGasPricer{prices: []int64{150}}, big.NewInt(100), CapPolicyFail)
+
+	if _, err := p.GasFees(context.Background(), UrgencyNormal); !errors.Is(err, ErrGasPriceAboveCap) {
+		t.Fatalf("expected ErrGasPriceAboveCap, got %v", err)
+	}
+}
+
+func TestCappedGasPricer_WaitsUntilUnderCap(t *testing.T) {
+	inner := &sequenceGasPricer{prices: []int64{150, 120, 90}}
+	p := NewCappedGasPricer(inner, big.NewInt(100), CapPolicyWait, WithCapPollInterval(time.Millisecond))
+
+	fees, err := p.GasFees(context.Background(), UrgencyLow)
+	if err != nil {
+		t.Fatalf("GasFees: %v", err)
+	}
+	if fees.GasPrice.Int64() != 90 || inner.calls != 3 {
+		t.Errorf("expected to wait for 90 after 3 polls, got %s after %d", fees.GasPrice, inner.calls)
+	}
+}
+
+func TestCappedGasPricer_HighUrgencyDoesNotWait(t *testing.T) {
+	inner := &sequenceGasPricer{prices: []int64{150, 90}}
+	p := NewCappedGasPricer(inner, big.NewInt(100), CapPolicyWait, WithCapPollInterval(time.Hour))
+
+	if _, err := p.GasFees(context.Background(), UrgencyHigh); !errors.Is(err, ErrGasPriceAboveCap) {
+		t.Fatalf("expected ErrGasPriceAboveCap, got %v", err)
+	}
+}
+
+func TestCappedGasPricer_LowersFeeCap(t *testing.T) {
+	client := &feeHistoryClient{history: &ethereum.FeeHistory{
+		Reward:  [][]*big.Int{{big.NewInt(10)}},
+		BaseFee: []*big.Int{big.NewInt(60), big.NewInt(60)},
+	}}
+	p := NewCappedGasPricer(NewFeeHistoryGasPricer(client), big.NewInt(100), CapPolicyFail)
+
+	fees, err := p.GasFees(context.Background(), UrgencyNormal)
+	if err != nil {
+		t.Fatalf("GasFees: %v", err)
+	}
+	if fees.GasFeeCap.Int64() != 100 || fees.GasTipCap.Int64() != 10 {
+		t.Errorf("expected fee cap lowered to 100, got feeCap=%s tip=%s", fees.GasFeeCap, fees.GasTipCap)
+	}
+}
@@ -0,0 +1,105 @@
+package sender
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrSendOptionsNotSupported is returned when a sender cannot honor the requested per-call options
+var ErrSendOptionsNotSupported = errors.New("send options not supported by transaction sender")
+
+// SendOptions holds per-call overrides for ContextTransactionSender.
+// Zero values mean the sender chooses (estimate gas, allocate nonce, suggest fees).
+type SendOptions struct {
+	// GasLimit overrides the estimated gas limit
+	GasLimit uint64
+	// Nonce overrides the nonce allocated by the sender
+	Nonce *uint64
+	// GasPrice sets the gas price of a legacy transaction
+	GasPrice *big.Int
+	// GasFeeCap and GasTipCap make the sender build an EIP-1559 transaction
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+	// NoEstimate skips gas estimation. GasLimit must be set.
+	NoEstimate bool
+	// Urgency is passed to the sender's GasPricer when no fees are set.
+	// It is a hint: senders without a GasPricer ignore it.
+	Urgency Urgency
+}
+
+// SendOption configures SendOptions
+type SendOption func(o *SendOptions)
+
+// WithGasLimit sets the gas limit instead of estimating it
+func WithGasLimit(gasLimit uint64) SendOption {
+	return func(o *SendOptions) {
+		o.GasLimit = gasLimit
+	}
+}
+
+// WithNonce sets the transaction nonce
+func WithNonce(nonce uint64) SendOption {
+	return func(o *SendOptions) {
+		o.Nonce = &nonce
+	}
+}
+
+// WithGasPrice sets the gas price of a legacy transaction
+func WithGasPrice(gasPrice *big.Int) SendOption {
+	return func(o *SendOptions) {
+		o.GasPrice = gasPrice
+	}
+}
+
+// WithFeeCaps sets EIP-1559 fee caps
+func WithFeeCaps(gasFeeCap, gasTipCap *big.Int) SendOption {
+	return func(o *SendOptions) {
+		o.GasFeeCap = gasFeeCap
+		o.GasTipCap = gasTipCap
+	}
+}
+
+// WithNoEstimate skips gas estimation; use together with WithGasLimit
+func WithNoEstimate() SendOption {
+	return func(o *SendOptions) {
+		o.NoEstimate = true
+	}
+}
+
+// WithUrgency sets the urgency the sender's GasPricer prices the transaction with
+func WithUrgency(urgency Urgency) SendOption {
+	return func(o *SendOptions) {
+		o.Urgency = urgency
+	}
+}
+
+// ApplySendOptions applies opts to an empty SendOptions
+func ApplySendOptions(opts ...SendOption) *SendOptions {
+	o := &SendOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// IsZero reports whether no override is set. Urgency is a hint and does not count.
+func (o *SendOptions) IsZero() bool {
+	return o.GasLimit == 0 && o.Nonce == nil && o.GasPrice == nil && o.GasFeeCap == nil && o.GasTipCap == nil && !o.NoEstimate
+}
+
+// Validate checks that the options are consistent
+func (o *SendOptions) Validate() error {
+	if o.NoEstimate && o.GasLimit == 0 {
+		return errors.New("gas limit is required when gas estimation is disabled")
+	}
+	if o.GasPrice != nil && (o.GasFeeCap != nil || o.GasTipCap != nil) {
+		return errors.New("gas price and EIP-1559 fee caps are mutually exclusive")
+	}
+	if (o.GasFeeCap == nil) != (o.GasTipCap == nil) {
+		return errors.New("both gas fee cap and gas tip cap must be set")
+	}
+	if o.GasFeeCap != nil && o.GasFeeCap.Cmp(o.GasTipCap) < 0 {
+		return errors.New("gas fee cap is lower than gas tip cap")
+	}
+	return nil
+}
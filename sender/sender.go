@@ -1,12 +1,80 @@
 package sender
 
 import (
+	"context"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // TransactionSender defines the interface for sending Ethereum transactions
 type TransactionSender interface {
 	SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error)
 }
+
+// MinedWaiter is implemented by senders that keep track of their transactions until they are mined.
+// WaitMined may replace a stuck transaction, so the returned receipt's TxHash can differ from txHash.
+type MinedWaiter interface {
+	WaitMined(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Unwrapper is implemented by senders that delegate to another TransactionSender
+type Unwrapper interface {
+	TransactionSender() TransactionSender
+}
+
+// AsMinedWaiter returns the MinedWaiter of s, looking through wrapping senders
+func AsMinedWaiter(s TransactionSender) (MinedWaiter, bool) {
+	for s != nil {
+		if w, ok := s.(MinedWaiter); ok {
+			return w, true
+		}
+		u, ok := s.(Unwrapper)
+		if !ok {
+			break
+		}
+		s = u.TransactionSender()
+	}
+	return nil, false
+}
+
+// ContextTransactionSender is a TransactionSender that takes a context and per-call options
+type ContextTransactionSender interface {
+	TransactionSender
+	SendEthereumTransactionWithContext(ctx context.Context, to common.Address, data []byte, value *big.Int, opts ...SendOption) (common.Hash, error)
+}
+
+// AsContextTransactionSender returns s as a ContextTransactionSender.
+// Senders that only implement TransactionSender are adapted: ctx is checked before sending,
+// and any per-call option makes the send fail with ErrSendOptionsNotSupported.
+func AsContextTransactionSender(s TransactionSender) ContextTransactionSender {
+	if cs, ok := s.(ContextTransactionSender); ok {
+		return cs
+	}
+	return &contextSenderAdapter{sender: s}
+}
+
+type contextSenderAdapter struct {
+	sender TransactionSender
+}
+
+func (a *contextSenderAdapter) SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error) {
+	return a.sender.SendEthereumTransaction(to, data, value)
+}
+
+func (a *contextSenderAdapter) SendEthereumTransactionWithContext(ctx context.Context, to common.Address, data []byte, value *big.Int, opts ...SendOption) (common.Hash, error) {
+	if err := ctx.Err(); err != nil {
+		return common.Hash{}, err
+	}
+	if !ApplySendOptions(opts...).IsZero() {
+		return common.Hash{}, fmt.Errorf("%w: %T", ErrSendOptionsNotSupported, a.sender)
+	}
+	return a.sender.SendEthereumTransaction(to, data, value)
+}
+
+// TransactionSender returns the adapted sender
+func (a *contextSenderAdapter) TransactionSender() TransactionSender {
+	return a.sender
+}
@@ -0,0 +1,90 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type legacySender struct {
+	calls int
+}
+
+func (s *legacySender) SendEthereumTransaction(_ common.Address, _ []byte, _ *big.Int) (common.Hash, error) {
+	s.calls++
+	return common.HexToHash("0x01"), nil
+}
+
+func TestAsContextTransactionSender_AdaptsLegacySender(t *testing.T) {
+	legacy := &legacySender{}
+	cs := AsContextTransactionSender(legacy)
+
+	hash, err := cs.SendEthereumTransactionWithContext(context.Background(), common.Address{}, nil, big.NewInt(0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash != common.HexToHash("0x01") || legacy.calls != 1 {
+		t.Errorf("expected the legacy sender to be called once, got %d calls", legacy.calls)
+	}
+
+	if u, ok := cs.(Unwrapper); !ok || u.TransactionSender() != legacy {
+		t.Error("adapter should unwrap to the legacy sender")
+	}
+}
+
+func TestAsContextTransactionSender_RejectsOptions(t *testing.T) {
+	legacy := &legacySender{}
+
+	_, err := AsContextTransactionSender(legacy).SendEthereumTransactionWithContext(context.Background(), common.Address{}, nil, big.NewInt(0), WithGasLimit(100000))
+	if !errors.Is(err, ErrSendOptionsNotSupported) {
+		t.Fatalf("expected ErrSendOptionsNotSupported, got %v", err)
+	}
+	if legacy.calls != 0 {
+		t.Error("legacy sender must not be called when options cannot be honored")
+	}
+}
+
+func TestAsContextTransactionSender_HonorsCancelledContext(t *testing.T) {
+	legacy := &legacySender{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := AsContextTransactionSender(legacy).SendEthereumTransactionWithContext(ctx, common.Address{}, nil, big.NewInt(0))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if legacy.calls != 0 {
+		t.Error("legacy sender must not be called with a cancelled context")
+	}
+}
+
+func TestSendOptions_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    []SendOption
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"gas limit and no estimate", []SendOption{WithGasLimit(21000), WithNoEstimate()}, false},
+		{"no estimate without gas limit", []SendOption{WithNoEstimate()}, true},
+		{"gas price with fee caps", []SendOption{WithGasPrice(big.NewInt(1)), WithFeeCaps(big.NewInt(2), big.NewInt(1))}, true},
+		{"fee cap below tip cap", []SendOption{WithFeeCaps(big.NewInt(1), big.NewInt(2))}, true},
+		{"fee cap without tip cap", []SendOption{WithFeeCaps(big.NewInt(1), nil)}, true},
+		{"nonce", []SendOption{WithNonce(0)}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ApplySendOptions(tc.opts...).Validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	if !ApplySendOptions().IsZero() || ApplySendOptions(WithNonce(0)).IsZero() {
+		t.Error("IsZero must only be true when no option is set")
+	}
+}
@@ -0,0 +1,568 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ethclient "github.com/ivanzzeth/ethclient"
+	"github.com/ivanzzeth/ethsig"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/journal"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/retry"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+var (
+	// ErrCustodyCallFailed is returned for calls a custody provider reports as failed or dropped.
+	// It wraps the provider's own error, if any.
+	ErrCustodyCallFailed = errors.New("custody provider call failed")
+	// ErrCustodyAwaitingApproval is returned for calls waiting for approvers when the sender is created with
+	// WithCustodyFailOnApproval
+	ErrCustodyAwaitingApproval = errors.New("custody provider call is awaiting approval")
+	// ErrCustodyTimeout is returned when a call did not reach the expected state in time
+	ErrCustodyTimeout = errors.New("custody provider call did not reach the expected state in time")
+	// ErrCustodyReplaceNotSupported is returned by SpeedUp and Cancel for providers that cannot replace calls
+	ErrCustodyReplaceNotSupported = errors.New("custody provider cannot replace calls")
+	// ErrCustodySignsOwnTransactions is returned by CustodyEOASigner.SignTransactionWithChainID
+	ErrCustodySignsOwnTransactions = errors.New("custody provider signs and broadcasts its own transactions")
+)
+
+// CustodyProvider is a custody vendor (MPC wallet, institutional custodian) holding the key of an account.
+// It signs typed data, but never hands out signed transactions: contract calls are submitted to it and it
+// signs, broadcasts and tracks them itself. Implement it to plug in a vendor; CoboMpcSigner is one.
+type CustodyProvider interface {
+	ethsig.AddressGetter
+	ethsig.TypedDataSigner
+	ContextTypedDataSigner
+
+	// SubmitContractCall submits call and returns the provider's id for it
+	SubmitContractCall(ctx context.Context, call CustodyCall) (string, error)
+	// CallStatus returns the current status of a submitted call
+	CallStatus(ctx context.Context, callId string) (*CustodyStatus, error)
+}
+
+// CustodyReplacer is implemented by custody providers that can replace calls pending on chain
+type CustodyReplacer interface {
+	// SpeedUpCall replaces the call with the same call paying fees and returns the replacement's id
+	SpeedUpCall(ctx context.Context, callId string, fees *sender.GasFees, gasLimit uint64) (string, error)
+	// DropCall replaces the call with a 0-value self-transfer paying fees and returns the replacement's id
+	DropCall(ctx context.Context, callId string, fees *sender.GasFees) (string, error)
+}
+
+// CustodyCanceller is implemented by custody providers that can cancel calls not broadcast yet
+type CustodyCanceller interface {
+	// CancelCall cancels the call before it is broadcast; it fails if the call was already broadcast
+	CancelCall(ctx context.Context, callId string) error
+}
+
+// CustodyNotifier is implemented by custody providers learning of status updates before they are polled,
+// e.g. through webhooks. Waits then poll a call as soon as its channel is closed.
+type CustodyNotifier interface {
+	// CallUpdated returns a channel closed on the next update of callId, and a func to call once the channel
+	// is no longer waited on
+	CallUpdated(callId string) (updated <-chan struct{}, unsubscribe func())
+}
+
+// CustodyCall is a contract call submitted to a custody provider.
+// The provider assigns the nonce.
+type CustodyCall struct {
+	To       common.Address
+	Data     []byte
+	Value    *big.Int
+	GasLimit uint64
+	// Fees are legacy or EIP-1559 fees; providers pricing gas themselves may ignore them
+	Fees *sender.GasFees
+}
+
+// CustodyState is the state of a call submitted to a custody provider
+type CustodyState int
+
+const (
+	// CustodyPending calls are being processed by the provider and not broadcast yet
+	CustodyPending CustodyState = iota
+	// CustodyAwaitingApproval calls wait for approvers
+	CustodyAwaitingApproval
+	// CustodyBroadcast calls are broadcast and wait to be mined
+	CustodyBroadcast
+	// CustodyMined calls are mined, successfully or not
+	CustodyMined
+	// CustodyFailed calls failed or were rejected and will not be mined
+	CustodyFailed
+	// CustodyDropped calls were replaced by a cancellation, the call ReplacedBy
+	CustodyDropped
+)
+
+func (s CustodyState) String() string {
+	switch s {
+	case CustodyPending:
+		return "pending"
+	case CustodyAwaitingApproval:
+		return "awaiting approval"
+	case CustodyBroadcast:
+		return "broadcast"
+	case CustodyMined:
+		return "mined"
+	case CustodyFailed:
+		return "failed"
+	case CustodyDropped:
+		return "dropped"
+	default:
+		return fmt.Sprintf("CustodyState(%d)", int(s))
+	}
+}
+
+// CustodyStatus is the status of a call submitted to a custody provider
+type CustodyStatus struct {
+	State CustodyState
+	// TxHash is the hash of the broadcast transaction; it is set for CustodyBroadcast and CustodyMined
+	TxHash common.Hash
+	// ReplacedBy is the id of the call replacing this one after a speed-up or a drop
+	ReplacedBy string
+	// Err optionally reports why the call failed or cannot proceed, in the provider's terms
+	Err error
+}
+
+// CustodyTransactionSender implements TransactionSender on top of a CustodyProvider.
+// It prices and estimates calls, submits them and tracks them by the hashes they are broadcast with.
+type CustodyTransactionSender struct {
+	client         bind.ContractBackend
+	provider       CustodyProvider
+	gasPricer      sender.GasPricer
+	retry          retry.Policy
+	pollInterval   time.Duration
+	maxPolls       int
+	failOnApproval bool
+
+	mu sync.Mutex
+	// sent maps every hash broadcast for a call to it
+	sent map[common.Hash]*custodyTx
+}
+
+// custodyTx is a call sent through a custody provider and its replacements
+type custodyTx struct {
+	// callId is the call first submitted, latestId the one of its last replacement
+	callId   string
+	latestId string
+	fees     *sender.GasFees
+	gasLimit uint64
+	// hashes are every hash broadcast for the call, all keys of it in sent
+	hashes []common.Hash
+}
+
+var (
+	_ sender.ContextTransactionSender = (*CustodyTransactionSender)(nil)
+	_ sender.MinedWaiter              = (*CustodyTransactionSender)(nil)
+)
+
+// CustodyTransactionSenderOption configures optional fields on CustodyTransactionSender
+type CustodyTransactionSenderOption func(s *CustodyTransactionSender)
+
+// WithCustodyGasPricer sets the GasPricer choosing the fees when none are passed per call
+func WithCustodyGasPricer(pricer sender.GasPricer) CustodyTransactionSenderOption {
+	return func(s *CustodyTransactionSender) {
+		s.gasPricer = pricer
+	}
+}
+
+// WithCustodyRetryPolicy sets how transient errors of gas pricing, gas estimation and status polling are retried
+// (default: retry.DefaultPolicy)
+func WithCustodyRetryPolicy(policy retry.Policy) CustodyTransactionSenderOption {
+	return func(s *CustodyTransactionSender) {
+		s.retry = policy
+	}
+}
+
+// WithCustodyPolling sets how often call statuses are polled and how many times a send polls before giving
+// up with ErrCustodyTimeout (default: every 3 seconds, 100 times). WaitMined polls until its ctx is done.
+func WithCustodyPolling(interval time.Duration, maxPolls int) CustodyTransactionSenderOption {
+	return func(s *CustodyTransactionSender) {
+		s.pollInterval = interval
+		s.maxPolls = maxPolls
+	}
+}
+
+// WithCustodyFailOnApproval makes sends fail with ErrCustodyAwaitingApproval (or the provider's error) as soon as
+// a call waits for approvers, instead of waiting for them. The call is cancelled first, see SendEthereumTransactionWithContext.
+func WithCustodyFailOnApproval() CustodyTransactionSenderOption {
+	return func(s *CustodyTransactionSender) {
+		s.failOnApproval = true
+	}
+}
+
+// NewCustodyTransactionSender creates a TransactionSender submitting calls to provider
+func NewCustodyTransactionSender(client bind.ContractBackend, provider CustodyProvider, opts ...CustodyTransactionSenderOption) *CustodyTransactionSender {
+	s := &CustodyTransactionSender{
+		client:       client,
+		provider:     provider,
+		retry:        retry.DefaultPolicy,
+		pollInterval: 3 * time.Second,
+		maxPolls:     100,
+		sent:         make(map[common.Hash]*custodyTx),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.gasPricer == nil {
+		s.gasPricer = sender.NewDefaultGasPricer(client)
+	}
+	return s
+}
+
+// Provider returns the custody provider calls are submitted to
+func (s *CustodyTransactionSender) Provider() CustodyProvider {
+	return s.provider
+}
+
+// SendEthereumTransaction sends an Ethereum transaction through the custody provider
+func (s *CustodyTransactionSender) SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error) {
+	return s.SendEthereumTransactionWithContext(context.Background(), to, data, value)
+}
+
+// SendEthereumTransactionWithContext sends an Ethereum transaction through the custody provider and returns
+// once it is broadcast. The provider assigns nonces, so WithNonce is rejected.
+//
+// Calls waiting for approvers are waited for, bounded by ctx and the number of polls (see WithCustodyPolling
+// and WithCustodyFailOnApproval). A call given up on before it was broadcast is cancelled when the provider
+// implements CustodyCanceller, so it cannot be broadcast after the error is returned, and its journal entry
+// is marked failed. Otherwise the error reports the call as still live at the provider.
+func (s *CustodyTransactionSender) SendEthereumTransactionWithContext(ctx context.Context, to common.Address, data []byte, value *big.Int, opts ...sender.SendOption) (common.Hash, error) {
+	o := sender.ApplySendOptions(opts...)
+	if err := o.Validate(); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid send options: %w", err)
+	}
+	if o.Nonce != nil {
+		return common.Hash{}, fmt.Errorf("%w: custody providers assign nonces", sender.ErrSendOptionsNotSupported)
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	var fees *sender.GasFees
+	switch {
+	case o.GasFeeCap != nil:
+		fees = &sender.GasFees{GasFeeCap: o.GasFeeCap, GasTipCap: o.GasTipCap}
+	case o.GasPrice != nil:
+		fees = &sender.GasFees{GasPrice: o.GasPrice}
+	default:
+		var err error
+		fees, err = retry.DoValue(ctx, s.retry, func() (*sender.GasFees, error) {
+			return s.gasPricer.GasFees(ctx, o.Urgency)
+		})
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to price gas: %w", err)
+		}
+	}
+
+	gasLimit := o.GasLimit
+	if !o.NoEstimate {
+		msg := ethereum.CallMsg{
+			From:  s.provider.GetAddress(),
+			To:    &to,
+			Data:  data,
+			Value: value,
+		}
+		estimated, err := retry.DoValue(ctx, s.retry, func() (uint64, error) {
+			return s.client.EstimateGas(ctx, msg)
+		})
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to estimate gas: %w", revert.Wrap(&to, err))
+		}
+		if gasLimit == 0 {
+			gasLimit = estimated
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return common.Hash{}, err
+	}
+
+	// Once submitted the call may be broadcast without this process learning its hash
+	if err := journal.Record(ctx, func(e *journal.Entry) { e.State = journal.StateSigned }); err != nil {
+		return common.Hash{}, err
+	}
+
+	callId, err := s.provider.SubmitContractCall(ctx, CustodyCall{To: to, Data: data, Value: value, GasLimit: gasLimit, Fees: fees})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to submit call: %w", err)
+	}
+
+	status, err := s.wait(ctx, callId, CustodyBroadcast, !s.failOnApproval, s.maxPolls)
+	if err == nil && status.State == CustodyDropped {
+		err = fmt.Errorf("%w: call %s was dropped", ErrCustodyCallFailed, callId)
+	}
+	switch {
+	case errors.Is(err, ErrCustodyAwaitingApproval) || errors.Is(err, ErrCustodyTimeout):
+		return common.Hash{}, s.abandon(ctx, callId, err)
+	case errors.Is(err, ErrCustodyCallFailed):
+		// Rejected, failed or dropped: the provider will never broadcast the call
+		_ = journal.Record(ctx, func(e *journal.Entry) { e.Fail(err) })
+		return common.Hash{}, err
+	case err != nil:
+		return common.Hash{}, err
+	}
+
+	s.mu.Lock()
+	s.sent[status.TxHash] = &custodyTx{callId: callId, latestId: callId, fees: fees, gasLimit: gasLimit, hashes: []common.Hash{status.TxHash}}
+	s.mu.Unlock()
+
+	_ = journal.Record(ctx, func(e *journal.Entry) {
+		e.State = journal.StateSent
+		e.TxHash = status.TxHash
+	})
+	return status.TxHash, nil
+}
+
+// abandon cancels callId, given up on with err before it was broadcast, and returns the error to report
+func (s *CustodyTransactionSender) abandon(ctx context.Context, callId string, err error) error {
+	canceller, ok := s.provider.(CustodyCanceller)
+	if !ok {
+		return fmt.Errorf("%w (call %s is still live at the provider)", err, callId)
+	}
+	// ctx may be what was given up on
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	if cancelErr := canceller.CancelCall(cancelCtx, callId); cancelErr != nil {
+		return fmt.Errorf("%w (call %s is still live at the provider: failed to cancel it: %v)", err, callId, cancelErr)
+	}
+	_ = journal.Record(ctx, func(e *journal.Entry) { e.Fail(err) })
+	return fmt.Errorf("%w (call %s was cancelled)", err, callId)
+}
+
+// SpeedUp replaces a call still pending on chain with the same call paying bumped fees.
+// txHash may be any hash previously broadcast for the call. It returns the new hash.
+func (s *CustodyTransactionSender) SpeedUp(ctx context.Context, txHash common.Hash) (common.Hash, error) {
+	return s.replace(ctx, txHash, false)
+}
+
+// Cancel replaces a call still pending on chain with a 0-value self-transfer paying bumped fees.
+// txHash may be any hash previously broadcast for the call. It returns the hash of the cancellation.
+func (s *CustodyTransactionSender) Cancel(ctx context.Context, txHash common.Hash) (common.Hash, error) {
+	return s.replace(ctx, txHash, true)
+}
+
+func (s *CustodyTransactionSender) replace(ctx context.Context, txHash common.Hash, drop bool) (common.Hash, error) {
+	replacer, ok := s.provider.(CustodyReplacer)
+	if !ok {
+		return common.Hash{}, fmt.Errorf("%w: %T", ErrCustodyReplaceNotSupported, s.provider)
+	}
+
+	s.mu.Lock()
+	tx, ok := s.sent[txHash]
+	var prev *sender.GasFees
+	var latestId string
+	if ok {
+		prev, latestId = tx.fees, tx.latestId
+	}
+	s.mu.Unlock()
+	if !ok {
+		return common.Hash{}, fmt.Errorf("%w: %s", ErrTransactionNotTracked, txHash.Hex())
+	}
+
+	// Replacements are priced as urgent: the bumped fees are raised to the current urgent price if lower
+	urgent, err := retry.DoValue(ctx, s.retry, func() (*sender.GasFees, error) {
+		return s.gasPricer.GasFees(ctx, sender.UrgencyHigh)
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to price replacement gas: %w", err)
+	}
+	fees := bumpCustodyFees(prev, urgent)
+
+	var replacementId string
+	if drop {
+		replacementId, err = replacer.DropCall(ctx, latestId, fees)
+	} else {
+		replacementId, err = replacer.SpeedUpCall(ctx, latestId, fees, tx.gasLimit)
+	}
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to replace call %s: %w", latestId, err)
+	}
+
+	status, err := s.wait(ctx, replacementId, CustodyBroadcast, true, s.maxPolls)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if status.State == CustodyDropped {
+		return common.Hash{}, fmt.Errorf("%w: call %s was dropped", ErrCustodyCallFailed, replacementId)
+	}
+
+	s.mu.Lock()
+	tx.latestId = replacementId
+	tx.fees = fees
+	tx.hashes = append(tx.hashes, status.TxHash)
+	s.sent[status.TxHash] = tx
+	s.mu.Unlock()
+	return status.TxHash, nil
+}
+
+// WaitMined waits until the call sent as txHash, or the speed-up replacing it, is mined.
+// If it was cancelled, the receipt of the cancellation is returned together with ErrTransactionCancelled.
+// Once the call is mined or has failed it is no longer tracked, so SpeedUp and Cancel fail with ErrTransactionNotTracked.
+// Hashes not sent through this sender are simply polled until mined.
+func (s *CustodyTransactionSender) WaitMined(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	backend, ok := s.client.(bind.DeployBackend)
+	if !ok {
+		return nil, fmt.Errorf("client %T cannot read receipts", s.client)
+	}
+
+	s.mu.Lock()
+	tx, tracked := s.sent[txHash]
+	var callId string
+	if tracked {
+		callId = tx.callId
+	}
+	s.mu.Unlock()
+	if !tracked {
+		return bind.WaitMined(ctx, backend, txHash)
+	}
+
+	status, err := s.wait(ctx, callId, CustodyMined, true, 0)
+	cancelled := err == nil && status.State == CustodyDropped
+	switch {
+	case cancelled && status.ReplacedBy == "":
+		err = fmt.Errorf("%w: call %s was dropped", ErrCustodyCallFailed, callId)
+	case cancelled:
+		if status, err = s.wait(ctx, status.ReplacedBy, CustodyMined, true, 0); err == nil && status.State == CustodyDropped {
+			err = fmt.Errorf("%w: cancellation was dropped", ErrCustodyCallFailed)
+		}
+	}
+	if errors.Is(err, ErrCustodyCallFailed) {
+		s.forget(tx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := bind.WaitMined(ctx, backend, status.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	s.forget(tx)
+	if cancelled {
+		return receipt, ErrTransactionCancelled
+	}
+	return receipt, nil
+}
+
+// forget stops tracking every hash broadcast for tx
+func (s *CustodyTransactionSender) forget(tx *custodyTx) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, h := range tx.hashes {
+		delete(s.sent, h)
+	}
+}
+
+// wait polls callId until it reaches state, following speed-ups, and returns the status of the call that did.
+// The status of a dropped call is returned as is. maxPolls 0 polls until ctx is done.
+func (s *CustodyTransactionSender) wait(ctx context.Context, callId string, state CustodyState, awaitApproval bool, maxPolls int) (*CustodyStatus, error) {
+	notifier, _ := s.provider.(CustodyNotifier)
+	unsubscribe := func() {}
+	defer func() { unsubscribe() }()
+	for polls := 1; ; polls++ {
+		unsubscribe()
+		var updated <-chan struct{}
+		if notifier != nil {
+			// Subscribe before polling so an update in between is not missed
+			updated, unsubscribe = notifier.CallUpdated(callId)
+		}
+
+		status, err := retry.DoValue(ctx, s.retry, func() (*CustodyStatus, error) {
+			return s.provider.CallStatus(ctx, callId)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get status of call %s: %w", callId, err)
+		}
+
+		switch {
+		case status.State == CustodyDropped:
+			return status, nil
+		case status.ReplacedBy != "":
+			// Sped up: the replacing call is the one that gets mined
+			callId = status.ReplacedBy
+			continue
+		case status.State == CustodyFailed:
+			if status.Err != nil && !errors.Is(status.Err, ErrCustodyCallFailed) {
+				return nil, fmt.Errorf("%w: %w", ErrCustodyCallFailed, status.Err)
+			}
+			if status.Err != nil {
+				return nil, status.Err
+			}
+			return nil, fmt.Errorf("%w: call %s", ErrCustodyCallFailed, callId)
+		case status.State == state || (status.State == CustodyMined && state == CustodyBroadcast):
+			return status, nil
+		case status.State == CustodyAwaitingApproval && !awaitApproval:
+			if status.Err != nil {
+				// Keep the provider's error, which may carry the approval details, but make the give-up recognizable
+				return nil, fmt.Errorf("%w: %w", ErrCustodyAwaitingApproval, status.Err)
+			}
+			return nil, fmt.Errorf("%w: call %s", ErrCustodyAwaitingApproval, callId)
+		}
+
+		if maxPolls > 0 && polls >= maxPolls {
+			return nil, fmt.Errorf("%w: call %s is %s", ErrCustodyTimeout, callId, status.State)
+		}
+		timer := time.NewTimer(s.pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w: call %s is %s: %w", ErrCustodyTimeout, callId, status.State, ctx.Err())
+		case <-timer.C:
+		case <-updated:
+			timer.Stop()
+		}
+	}
+}
+
+// bumpCustodyFees returns the fees of a replacement for a call paying prev, keeping its fee type:
+// prev bumped by 15%, raised to the urgent fees if lower
+func bumpCustodyFees(prev, urgent *sender.GasFees) *sender.GasFees {
+	const percent = 15
+	if !prev.IsDynamic() {
+		return &sender.GasFees{GasPrice: maxBig(bumpFee(prev.GasPrice, percent), urgent.Price())}
+	}
+	tipCap := bumpFee(prev.GasTipCap, percent)
+	feeCap := bumpFee(prev.GasFeeCap, percent)
+	if urgent.IsDynamic() {
+		tipCap = maxBig(tipCap, urgent.GasTipCap)
+		feeCap = maxBig(feeCap, urgent.GasFeeCap)
+	} else {
+		feeCap = maxBig(feeCap, urgent.GasPrice)
+	}
+	if feeCap.Cmp(tipCap) < 0 {
+		feeCap = new(big.Int).Set(tipCap)
+	}
+	return &sender.GasFees{GasFeeCap: feeCap, GasTipCap: tipCap}
+}
+
+// CustodyEOASigner lets a custody provider's account trade as an EOA. Pass it to WithEOASigner: transactions
+// are then sent through a CustodyTransactionSender, as it cannot sign transactions itself.
+type CustodyEOASigner struct {
+	CustodyProvider
+}
+
+var _ EOATradingSigner = (*CustodyEOASigner)(nil)
+
+// NewCustodyEOASigner wraps provider as an EOATradingSigner
+func NewCustodyEOASigner(provider CustodyProvider) *CustodyEOASigner {
+	return &CustodyEOASigner{CustodyProvider: provider}
+}
+
+// SignTransactionWithChainID fails with ErrCustodySignsOwnTransactions
+func (s *CustodyEOASigner) SignTransactionWithChainID(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, ErrCustodySignsOwnTransactions
+}
+
+// NewSafeTradingCustodySigner creates a SafeTradingSigner whose owner is held by a custody provider:
+// Safe transactions are signed by the provider and executed through a CustodyTransactionSender
+func NewSafeTradingCustodySigner(client ethclient.EthClientInterface, provider CustodyProvider, opts ...CustodyTransactionSenderOption) *SimpleSafeTradingSigner {
+	return NewSimpleSafeTradingSigner(provider.GetAddress(), provider, NewCustodyTransactionSender(client, provider, opts...))
+}
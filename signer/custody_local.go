@@ -0,0 +1,336 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ivanzzeth/ethsig/eip712"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+// ErrLocalCustodyUnknownCall is returned by LocalCustodyProvider for call ids it did not issue
+var ErrLocalCustodyUnknownCall = errors.New("unknown custody call")
+
+// LocalCustodyBackend is the part of a client LocalCustodyProvider broadcasts through
+type LocalCustodyBackend interface {
+	bind.ContractTransactor
+	bind.DeployBackend
+}
+
+// LocalCustodyProvider is a CustodyProvider holding a private key in process: it signs and broadcasts
+// submitted calls itself. It stands in for a custody vendor so Safe and EOA flows, approvals, speed-ups
+// and cancellations can be tested end to end without one.
+type LocalCustodyProvider struct {
+	key             *ecdsa.PrivateKey
+	address         common.Address
+	chainID         *big.Int
+	client          LocalCustodyBackend
+	requireApproval bool
+
+	mu        sync.Mutex
+	nextNonce *uint64
+	calls     map[string]*localCustodyCall
+	seq       int
+}
+
+// localCustodyCall is a call submitted to a LocalCustodyProvider
+type localCustodyCall struct {
+	call       CustodyCall
+	nonce      uint64
+	state      CustodyState
+	txHash     common.Hash
+	replacedBy string
+	drop       bool
+	err        error
+}
+
+var (
+	_ CustodyProvider = (*LocalCustodyProvider)(nil)
+	_ CustodyReplacer = (*LocalCustodyProvider)(nil)
+)
+
+// LocalCustodyProviderOption configures optional fields on LocalCustodyProvider
+type LocalCustodyProviderOption func(p *LocalCustodyProvider)
+
+// WithLocalCustodyApproval holds submitted calls as CustodyAwaitingApproval until Approve or Reject is called
+func WithLocalCustodyApproval() LocalCustodyProviderOption {
+	return func(p *LocalCustodyProvider) {
+		p.requireApproval = true
+	}
+}
+
+// NewLocalCustodyProvider creates a LocalCustodyProvider signing with key for chainID and broadcasting through client
+func NewLocalCustodyProvider(key *ecdsa.PrivateKey, chainID *big.Int, client LocalCustodyBackend, opts ...LocalCustodyProviderOption) *LocalCustodyProvider {
+	p := &LocalCustodyProvider{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+		chainID: chainID,
+		client:  client,
+		calls:   make(map[string]*localCustodyCall),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// GetAddress returns the address of the key
+func (p *LocalCustodyProvider) GetAddress() common.Address {
+	return p.address
+}
+
+// SignTypedData signs EIP-712 typed data
+func (p *LocalCustodyProvider) SignTypedData(typedData eip712.TypedData) ([]byte, error) {
+	return p.SignTypedDataWithContext(context.Background(), typedData)
+}
+
+// SignTypedDataWithContext signs EIP-712 typed data
+func (p *LocalCustodyProvider) SignTypedDataWithContext(ctx context.Context, typedData eip712.TypedData) ([]byte, error) {
+	hash, _, err := eip712.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	signature, err := crypto.Sign(hash, p.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// SubmitContractCall broadcasts call, or holds it for approval with WithLocalCustodyApproval
+func (p *LocalCustodyProvider) SubmitContractCall(ctx context.Context, call CustodyCall) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := p.newId()
+	c := &localCustodyCall{call: call, state: CustodyAwaitingApproval}
+	p.calls[id] = c
+	if p.requireApproval {
+		return id, nil
+	}
+	if err := p.broadcast(ctx, c, true); err != nil {
+		delete(p.calls, id)
+		return "", err
+	}
+	return id, nil
+}
+
+// Approve broadcasts a call awaiting approval
+func (p *LocalCustodyProvider) Approve(ctx context.Context, callId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, err := p.awaitingApproval(callId)
+	if err != nil {
+		return err
+	}
+	if err := p.broadcast(ctx, c, true); err != nil {
+		c.state = CustodyFailed
+		c.err = err
+		return err
+	}
+	return nil
+}
+
+// CancelCall cancels a call awaiting approval
+func (p *LocalCustodyProvider) CancelCall(_ context.Context, callId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, err := p.awaitingApproval(callId)
+	if err != nil {
+		return err
+	}
+	c.state = CustodyFailed
+	c.err = fmt.Errorf("%w: call %s was cancelled", ErrCustodyCallFailed, callId)
+	return nil
+}
+
+// AwaitingApproval returns the ids of the calls awaiting approval
+func (p *LocalCustodyProvider) AwaitingApproval() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var ids []string
+	for id, c := range p.calls {
+		if c.state == CustodyAwaitingApproval {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Reject fails a call awaiting approval
+func (p *LocalCustodyProvider) Reject(callId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, err := p.awaitingApproval(callId)
+	if err != nil {
+		return err
+	}
+	c.state = CustodyFailed
+	c.err = fmt.Errorf("%w: call %s was rejected", ErrCustodyCallFailed, callId)
+	return nil
+}
+
+// CallStatus returns the status of callId. Broadcast calls are reported mined once their receipt is available.
+func (p *LocalCustodyProvider) CallStatus(ctx context.Context, callId string) (*CustodyStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, ok := p.calls[callId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLocalCustodyUnknownCall, callId)
+	}
+	if c.state == CustodyBroadcast {
+		_, err := p.client.TransactionReceipt(ctx, c.txHash)
+		switch {
+		case err == nil:
+			// Mined before its replacement, if any
+			c.state = CustodyMined
+			c.replacedBy = ""
+		case !errors.Is(err, ethereum.NotFound):
+			return nil, err
+		}
+	}
+
+	status := &CustodyStatus{State: c.state, TxHash: c.txHash, ReplacedBy: c.replacedBy, Err: c.err}
+	if c.replacedBy != "" && p.calls[c.replacedBy].drop {
+		status.State = CustodyDropped
+	}
+	return status, nil
+}
+
+// SpeedUpCall rebroadcasts the call with the same nonce paying fees
+func (p *LocalCustodyProvider) SpeedUpCall(ctx context.Context, callId string, fees *sender.GasFees, gasLimit uint64) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, err := p.broadcastCall(callId)
+	if err != nil {
+		return "", err
+	}
+	call := c.call
+	call.Fees = fees
+	call.GasLimit = gasLimit
+	return p.replace(ctx, callId, c, &localCustodyCall{call: call, nonce: c.nonce})
+}
+
+// DropCall replaces the call with a 0-value self-transfer with the same nonce paying fees
+func (p *LocalCustodyProvider) DropCall(ctx context.Context, callId string, fees *sender.GasFees) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, err := p.broadcastCall(callId)
+	if err != nil {
+		return "", err
+	}
+	call := CustodyCall{To: p.address, Value: big.NewInt(0), GasLimit: params.TxGas, Fees: fees}
+	return p.replace(ctx, callId, c, &localCustodyCall{call: call, nonce: c.nonce, drop: true})
+}
+
+func (p *LocalCustodyProvider) replace(ctx context.Context, callId string, c, replacement *localCustodyCall) (string, error) {
+	if err := p.broadcast(ctx, replacement, false); err != nil {
+		return "", fmt.Errorf("failed to replace call %s: %w", callId, err)
+	}
+	id := p.newId()
+	p.calls[id] = replacement
+	c.replacedBy = id
+	return id, nil
+}
+
+func (p *LocalCustodyProvider) awaitingApproval(callId string) (*localCustodyCall, error) {
+	c, ok := p.calls[callId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLocalCustodyUnknownCall, callId)
+	}
+	if c.state != CustodyAwaitingApproval {
+		return nil, fmt.Errorf("call %s is %s, not awaiting approval", callId, c.state)
+	}
+	return c, nil
+}
+
+func (p *LocalCustodyProvider) broadcastCall(callId string) (*localCustodyCall, error) {
+	c, ok := p.calls[callId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLocalCustodyUnknownCall, callId)
+	}
+	if c.state != CustodyBroadcast || c.replacedBy != "" {
+		return nil, fmt.Errorf("call %s is %s and cannot be replaced", callId, c.state)
+	}
+	return c, nil
+}
+
+// broadcast signs and sends c, assigning it the next nonce if newNonce is set
+func (p *LocalCustodyProvider) broadcast(ctx context.Context, c *localCustodyCall, newNonce bool) error {
+	if newNonce {
+		if p.nextNonce == nil {
+			nonce, err := p.client.PendingNonceAt(ctx, p.address)
+			if err != nil {
+				return fmt.Errorf("failed to get nonce: %w", err)
+			}
+			p.nextNonce = &nonce
+		}
+		c.nonce = *p.nextNonce
+	}
+
+	call := c.call
+	value := call.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	gasLimit := call.GasLimit
+	if gasLimit == 0 {
+		var err error
+		gasLimit, err = p.client.EstimateGas(ctx, ethereum.CallMsg{From: p.address, To: &call.To, Data: call.Data, Value: value})
+		if err != nil {
+			return fmt.Errorf("failed to estimate gas: %w", err)
+		}
+	}
+	fees := call.Fees
+	if fees == nil {
+		gasPrice, err := p.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to suggest gas price: %w", err)
+		}
+		fees = &sender.GasFees{GasPrice: gasPrice}
+	}
+
+	var tx *types.Transaction
+	if fees.IsDynamic() {
+		tx = types.NewTx(&types.DynamicFeeTx{ChainID: p.chainID, Nonce: c.nonce, GasTipCap: fees.GasTipCap, GasFeeCap: fees.GasFeeCap, Gas: gasLimit, To: &call.To, Value: value, Data: call.Data})
+	} else {
+		tx = types.NewTx(&types.LegacyTx{Nonce: c.nonce, GasPrice: fees.GasPrice, Gas: gasLimit, To: &call.To, Value: value, Data: call.Data})
+	}
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(p.chainID), p.key)
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := p.client.SendTransaction(ctx, signedTx); err != nil {
+		return fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	if newNonce {
+		*p.nextNonce++
+	}
+	c.state = CustodyBroadcast
+	c.txHash = signedTx.Hash()
+	return nil
+}
+
+func (p *LocalCustodyProvider) newId() string {
+	p.seq++
+	return fmt.Sprintf("local-%d", p.seq)
+}
@@ -0,0 +1,251 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ivanzzeth/ethsig/eip712"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/journal"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+func newTestCustodyProvider(t *testing.T, opts ...LocalCustodyProviderOption) (*LocalCustodyProvider, *mockTxClient) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	client := newMockTxClient()
+	return NewLocalCustodyProvider(key, big.NewInt(137), client, opts...), client
+}
+
+func TestCustodyTransactionSender_EOAFlow(t *testing.T) {
+	p, client := newTestCustodyProvider(t)
+	txSender, err := GetTransactionSenderBySigner(big.NewInt(137), client, NewCustodyEOASigner(p))
+	if err != nil {
+		t.Fatalf("GetTransactionSenderBySigner: %v", err)
+	}
+	custodySender, ok := txSender.(*CustodyTransactionSender)
+	if !ok {
+		t.Fatalf("got %T, want *CustodyTransactionSender", txSender)
+	}
+
+	ctx := context.Background()
+	hash, err := custodySender.SendEthereumTransactionWithContext(ctx, testTxTo, []byte{0x01}, big.NewInt(5), sender.WithFeeCaps(big.NewInt(300), big.NewInt(30)))
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	sent := client.lastSent()
+	if sent.Hash() != hash || sent.Type() != types.DynamicFeeTxType || sent.Nonce() != 3 || sent.Gas() != 50000 {
+		t.Fatalf("unexpected transaction sent: type %d, nonce %d, gas %d", sent.Type(), sent.Nonce(), sent.Gas())
+	}
+	if from, _ := types.Sender(types.LatestSignerForChainID(big.NewInt(137)), sent); from != p.GetAddress() {
+		t.Errorf("sent from %s, want %s", from.Hex(), p.GetAddress().Hex())
+	}
+
+	if _, err := custodySender.SendEthereumTransactionWithContext(ctx, testTxTo, nil, nil, sender.WithNonce(7)); !errors.Is(err, sender.ErrSendOptionsNotSupported) {
+		t.Errorf("WithNonce: got %v, want ErrSendOptionsNotSupported", err)
+	}
+
+	client.mine(hash)
+	receipt, err := custodySender.WaitMined(ctx, hash)
+	if err != nil || receipt.TxHash != hash {
+		t.Fatalf("WaitMined: %v", err)
+	}
+}
+
+func TestCustodyTransactionSender_Approval(t *testing.T) {
+	ctx := context.Background()
+
+	for _, approve := range []bool{true, false} {
+		p, client := newTestCustodyProvider(t, WithLocalCustodyApproval())
+		s := NewCustodyTransactionSender(client, p, WithCustodyPolling(time.Millisecond, 1000))
+		j := journal.NewMemoryJournal()
+		entry := journal.NewEntry(p.GetAddress(), common.Address{}, testTxTo, nil, big.NewInt(0))
+		_ = j.Put(entry)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			// Approve or reject the call once it is submitted
+			for len(p.AwaitingApproval()) == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			id := p.AwaitingApproval()[0]
+			if approve {
+				_ = p.Approve(ctx, id)
+			} else {
+				_ = p.Reject(id)
+			}
+		}()
+
+		hash, err := s.SendEthereumTransactionWithContext(journal.NewContext(ctx, j, entry.ID), testTxTo, nil, nil, sender.WithGasPrice(big.NewInt(100)))
+		<-done
+		if approve {
+			if err != nil || client.lastSent().Hash() != hash {
+				t.Errorf("approved: %v", err)
+			}
+		} else if !errors.Is(err, ErrCustodyCallFailed) {
+			t.Errorf("rejected: got %v, want ErrCustodyCallFailed", err)
+		} else if got, _ := j.Get(entry.ID); got.State != journal.StateFailed {
+			t.Errorf("rejected: expected the journal entry failed, got %s", got.State)
+		}
+	}
+}
+
+func TestCustodyTransactionSender_CancelsCallGivenUpOn(t *testing.T) {
+	for _, opts := range [][]CustodyTransactionSenderOption{
+		{WithCustodyPolling(time.Millisecond, 10), WithCustodyFailOnApproval()},
+		// Nobody approves before the polls run out
+		{WithCustodyPolling(time.Millisecond, 10)},
+	} {
+		p, client := newTestCustodyProvider(t, WithLocalCustodyApproval())
+		s := NewCustodyTransactionSender(client, p, opts...)
+		j := journal.NewMemoryJournal()
+		entry := journal.NewEntry(p.GetAddress(), common.Address{}, testTxTo, nil, big.NewInt(0))
+		_ = j.Put(entry)
+
+		_, err := s.SendEthereumTransactionWithContext(journal.NewContext(context.Background(), j, entry.ID), testTxTo, nil, nil, sender.WithGasPrice(big.NewInt(100)))
+		if !errors.Is(err, ErrCustodyAwaitingApproval) && !errors.Is(err, ErrCustodyTimeout) {
+			t.Fatalf("got %v, want ErrCustodyAwaitingApproval or ErrCustodyTimeout", err)
+		}
+		if ids := p.AwaitingApproval(); len(ids) != 0 {
+			t.Errorf("expected the call to be cancelled, %v still await approval", ids)
+		}
+		if got, _ := j.Get(entry.ID); got.State != journal.StateFailed {
+			t.Errorf("expected the journal entry failed, got %s", got.State)
+		}
+	}
+}
+
+// errOnApprovalProvider reports calls awaiting approval with an error of its own, as CoboMpcSigner does
+type errOnApprovalProvider struct {
+	*LocalCustodyProvider
+}
+
+var errTestPendingApproval = errors.New("pending approval")
+
+func (p errOnApprovalProvider) CallStatus(ctx context.Context, callId string) (*CustodyStatus, error) {
+	status, err := p.LocalCustodyProvider.CallStatus(ctx, callId)
+	if err == nil && status.State == CustodyAwaitingApproval {
+		status.Err = errTestPendingApproval
+	}
+	return status, err
+}
+
+func TestCustodyTransactionSender_CancelsCallWithProviderApprovalError(t *testing.T) {
+	p, client := newTestCustodyProvider(t, WithLocalCustodyApproval())
+	s := NewCustodyTransactionSender(client, errOnApprovalProvider{p}, WithCustodyPolling(time.Millisecond, 10), WithCustodyFailOnApproval())
+
+	_, err := s.SendEthereumTransactionWithContext(context.Background(), testTxTo, nil, nil, sender.WithGasPrice(big.NewInt(100)))
+	if !errors.Is(err, ErrCustodyAwaitingApproval) || !errors.Is(err, errTestPendingApproval) {
+		t.Fatalf("got %v, want ErrCustodyAwaitingApproval wrapping the provider's error", err)
+	}
+	if ids := p.AwaitingApproval(); len(ids) != 0 {
+		t.Errorf("expected the call to be cancelled, %v still await approval", ids)
+	}
+}
+
+func TestCustodyTransactionSender_SpeedUpAndCancel(t *testing.T) {
+	p, client := newTestCustodyProvider(t)
+	s := NewCustodyTransactionSender(client, p, WithCustodyPolling(time.Millisecond, 10))
+	ctx := context.Background()
+
+	hash, err := s.SendEthereumTransactionWithContext(ctx, testTxTo, []byte{0x01}, nil, sender.WithGasPrice(big.NewInt(100)))
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	original := client.lastSent()
+
+	spedUp, err := s.SpeedUp(ctx, hash)
+	if err != nil {
+		t.Fatalf("SpeedUp: %v", err)
+	}
+	replacement := client.lastSent()
+	if replacement.Hash() != spedUp || replacement.Nonce() != original.Nonce() || replacement.GasPrice().Cmp(big.NewInt(115)) < 0 || replacement.To() == nil || *replacement.To() != testTxTo {
+		t.Fatalf("unexpected speed-up: nonce %d, gas price %s", replacement.Nonce(), replacement.GasPrice())
+	}
+	client.mine(spedUp)
+	if receipt, err := s.WaitMined(ctx, hash); err != nil || receipt.TxHash != spedUp {
+		t.Fatalf("WaitMined after speed-up: %v", err)
+	}
+
+	hash, err = s.SendEthereumTransactionWithContext(ctx, testTxTo, []byte{0x01}, nil, sender.WithGasPrice(big.NewInt(100)))
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	cancelled, err := s.Cancel(ctx, hash)
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	cancellation := client.lastSent()
+	if cancellation.Hash() != cancelled || cancellation.Nonce() != original.Nonce()+1 || *cancellation.To() != p.GetAddress() || len(cancellation.Data()) != 0 {
+		t.Fatalf("unexpected cancellation: nonce %d, to %s", cancellation.Nonce(), cancellation.To().Hex())
+	}
+	client.mine(cancelled)
+	if receipt, err := s.WaitMined(ctx, hash); !errors.Is(err, ErrTransactionCancelled) || receipt == nil || receipt.TxHash != cancelled {
+		t.Fatalf("WaitMined after cancel: got %v, want ErrTransactionCancelled", err)
+	}
+
+	if _, err := s.SpeedUp(ctx, common.Hash{0x01}); !errors.Is(err, ErrTransactionNotTracked) {
+		t.Errorf("SpeedUp of unknown hash: got %v, want ErrTransactionNotTracked", err)
+	}
+	if _, err := s.SpeedUp(ctx, hash); !errors.Is(err, ErrTransactionNotTracked) {
+		t.Errorf("SpeedUp of a mined call: got %v, want ErrTransactionNotTracked", err)
+	}
+	if len(s.sent) != 0 {
+		t.Errorf("expected mined calls untracked, %d hashes still tracked", len(s.sent))
+	}
+}
+
+func TestNewSafeTradingCustodySigner(t *testing.T) {
+	p, client := newTestCustodyProvider(t)
+	safeSigner := NewSafeTradingCustodySigner(client, p, WithCustodyPolling(time.Millisecond, 10))
+
+	signature, err := safeSigner.SignTypedData(testTypedData)
+	if err != nil {
+		t.Fatalf("SignTypedData: %v", err)
+	}
+	hash, _, err := eip712.TypedDataAndHash(testTypedData)
+	if err != nil {
+		t.Fatalf("TypedDataAndHash: %v", err)
+	}
+	sig := common.CopyBytes(signature)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != p.GetAddress() {
+		t.Fatalf("signature does not recover to the provider: %v", err)
+	}
+
+	// Safe transactions are executed by the owner through the provider
+	safe := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	txHash, err := safeSigner.SendEthereumTransactionWithContext(context.Background(), safe, []byte{0x6a, 0x76, 0x12, 0x02}, nil, sender.WithGasPrice(big.NewInt(100)))
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	sent := client.lastSent()
+	if sent.Hash() != txHash || *sent.To() != safe {
+		t.Fatalf("unexpected transaction sent to %s", sent.To().Hex())
+	}
+	if _, ok := safeSigner.TransactionSender().(*CustodyTransactionSender); !ok {
+		t.Errorf("got %T, want *CustodyTransactionSender", safeSigner.TransactionSender())
+	}
+}
+
+func TestBumpCustodyFees(t *testing.T) {
+	prev := &sender.GasFees{GasFeeCap: big.NewInt(100), GasTipCap: big.NewInt(10)}
+	fees := bumpCustodyFees(prev, &sender.GasFees{GasFeeCap: big.NewInt(200), GasTipCap: big.NewInt(5)})
+	if fees.GasFeeCap.Int64() != 200 || fees.GasTipCap.Int64() != 12 {
+		t.Errorf("dynamic: got fee cap %s, tip cap %s", fees.GasFeeCap, fees.GasTipCap)
+	}
+
+	fees = bumpCustodyFees(&sender.GasFees{GasPrice: big.NewInt(100)}, &sender.GasFees{GasPrice: big.NewInt(50)})
+	if fees.IsDynamic() || fees.GasPrice.Int64() != 115 {
+		t.Errorf("legacy: got %+v", fees)
+	}
+}
@@ -0,0 +1,195 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ivanzzeth/ethsig/eip712"
+)
+
+// ErrKMSSignatureMismatch is returned when a KMS signature does not recover to the key's address
+var ErrKMSSignatureMismatch = errors.New("KMS signature does not match the public key")
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// KMSClient is the minimal interface of a cloud KMS holding a secp256k1 key, e.g. an AWS KMS or
+// Google Cloud KMS ECC_SECG_P256K1 key. Adapt your vendor's SDK to it.
+type KMSClient interface {
+	// SignDigest signs a 32-byte digest and returns an ASN.1 DER encoded ECDSA signature
+	SignDigest(ctx context.Context, digest []byte) ([]byte, error)
+	// PublicKey returns the public key as a DER or PEM encoded SubjectPublicKeyInfo,
+	// or as 65 uncompressed bytes
+	PublicKey(ctx context.Context) ([]byte, error)
+}
+
+// KMSSigner signs with a key held by a KMSClient, converting its DER signatures to Ethereum signatures.
+// It can back both an EOATradingSigner and a SafeTradingSigner.
+type KMSSigner struct {
+	client  KMSClient
+	pub     *ecdsa.PublicKey
+	address common.Address
+	timeout time.Duration
+}
+
+var (
+	_ EOATradingSigner       = (*KMSSigner)(nil)
+	_ ContextTypedDataSigner = (*KMSSigner)(nil)
+)
+
+// KMSSignerOption configures optional fields on KMSSigner
+type KMSSignerOption func(s *KMSSigner)
+
+// WithKMSSignerTimeout bounds every signing request (default: 30 seconds)
+func WithKMSSignerTimeout(timeout time.Duration) KMSSignerOption {
+	return func(s *KMSSigner) {
+		s.timeout = timeout
+	}
+}
+
+// NewKMSSigner creates a KMSSigner, reading the public key from client
+func NewKMSSigner(ctx context.Context, client KMSClient, opts ...KMSSignerOption) (*KMSSigner, error) {
+	der, err := client.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KMS public key: %w", err)
+	}
+	pub, err := parseKMSPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &KMSSigner{client: client, pub: pub, address: crypto.PubkeyToAddress(*pub), timeout: 30 * time.Second}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// GetAddress returns the address of the KMS key
+func (s *KMSSigner) GetAddress() common.Address {
+	return s.address
+}
+
+// SignTypedData signs EIP-712 typed data
+func (s *KMSSigner) SignTypedData(typedData eip712.TypedData) ([]byte, error) {
+	return s.SignTypedDataWithContext(context.Background(), typedData)
+}
+
+// SignTypedDataWithContext signs EIP-712 typed data, honoring ctx. V is 27 or 28.
+func (s *KMSSigner) SignTypedDataWithContext(ctx context.Context, typedData eip712.TypedData) ([]byte, error) {
+	hash, _, err := eip712.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	signature, err := s.SignHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// SignTransactionWithChainID signs tx for chainID
+func (s *KMSSigner) SignTransactionWithChainID(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.SignTransactionWithContext(context.Background(), tx, chainID)
+}
+
+// SignTransactionWithContext is SignTransactionWithChainID honoring ctx
+func (s *KMSSigner) SignTransactionWithContext(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	txSigner := types.LatestSignerForChainID(chainID)
+	signature, err := s.SignHash(ctx, txSigner.Hash(tx).Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return tx.WithSignature(txSigner, signature)
+}
+
+// SignHash signs a 32-byte hash and returns the 65-byte [R || S || V] signature, V being 0 or 1
+func (s *KMSSigner) SignHash(ctx context.Context, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("hash is required to be exactly 32 bytes (%d)", len(hash))
+	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	der, err := s.client.SignDigest(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return derToEthSignature(der, hash, s.pub)
+}
+
+// derToEthSignature converts an ASN.1 DER ECDSA signature of hash by pub to a 65-byte [R || S || V] signature:
+// S is normalized to the lower half of the curve order and V found by recovering the public key
+func derToEthSignature(der, hash []byte, pub *ecdsa.PublicKey) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode DER signature: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("failed to decode DER signature: trailing data")
+	}
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.Cmp(secp256k1N) >= 0 || sig.S.Cmp(secp256k1N) >= 0 {
+		return nil, errors.New("invalid DER signature: r or s out of range")
+	}
+
+	// Ethereum only accepts low-s signatures (EIP-2); (r, n-s) is an equally valid signature
+	if sig.S.Cmp(secp256k1HalfN) > 0 {
+		sig.S = new(big.Int).Sub(secp256k1N, sig.S)
+	}
+
+	signature := make([]byte, 65)
+	sig.R.FillBytes(signature[:32])
+	sig.S.FillBytes(signature[32:64])
+	want := crypto.FromECDSAPub(pub)
+	for v := byte(0); v < 2; v++ {
+		signature[64] = v
+		recovered, err := crypto.Ecrecover(hash, signature)
+		if err == nil && bytes.Equal(recovered, want) {
+			return signature, nil
+		}
+	}
+	return nil, ErrKMSSignatureMismatch
+}
+
+// parseKMSPublicKey parses a secp256k1 public key from a DER or PEM encoded SubjectPublicKeyInfo,
+// or from 65 uncompressed bytes
+func parseKMSPublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	if len(data) != 65 {
+		// x509.ParsePKIXPublicKey does not know secp256k1
+		var spki struct {
+			Algorithm pkix.AlgorithmIdentifier
+			PublicKey asn1.BitString
+		}
+		if _, err := asn1.Unmarshal(data, &spki); err != nil {
+			return nil, fmt.Errorf("failed to decode KMS public key: %w", err)
+		}
+		data = spki.PublicKey.RightAlign()
+	}
+	pub, err := crypto.UnmarshalPubkey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode KMS public key: %w", err)
+	}
+	return pub, nil
+}
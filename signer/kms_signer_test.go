@@ -0,0 +1,158 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ivanzzeth/ethsig/eip712"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+// softwareKMS stands in for a cloud KMS: it returns DER signatures and a DER SubjectPublicKeyInfo
+type softwareKMS struct {
+	key *ecdsa.PrivateKey
+	// highS returns the high-s form of every signature, as KMSs do half of the time
+	highS bool
+	// pem returns the public key PEM encoded
+	pem bool
+}
+
+func (k *softwareKMS) SignDigest(_ context.Context, digest []byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest, k.key)
+	if err != nil {
+		return nil, err
+	}
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if k.highS {
+		s.Sub(secp256k1N, s)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func (k *softwareKMS) PublicKey(_ context.Context) ([]byte, error) {
+	der, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}}, // secp256k1
+		},
+		PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&k.key.PublicKey), BitLength: 65 * 8},
+	})
+	if err != nil || !k.pem {
+		return der, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+func newTestKMSSigner(t *testing.T, kms *softwareKMS) *KMSSigner {
+	t.Helper()
+	if kms.key == nil {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("GenerateKey: %v", err)
+		}
+		kms.key = key
+	}
+	s, err := NewKMSSigner(context.Background(), kms)
+	if err != nil {
+		t.Fatalf("NewKMSSigner: %v", err)
+	}
+	if s.GetAddress() != crypto.PubkeyToAddress(kms.key.PublicKey) {
+		t.Fatalf("address %s, want %s", s.GetAddress().Hex(), crypto.PubkeyToAddress(kms.key.PublicKey).Hex())
+	}
+	return s
+}
+
+func TestKMSSigner_SignTypedData(t *testing.T) {
+	hash, _, err := eip712.TypedDataAndHash(testTypedData)
+	if err != nil {
+		t.Fatalf("TypedDataAndHash: %v", err)
+	}
+
+	for _, kms := range []*softwareKMS{{}, {highS: true}, {pem: true}} {
+		s := newTestKMSSigner(t, kms)
+		signature, err := s.SignTypedData(testTypedData)
+		if err != nil {
+			t.Fatalf("highS=%v: SignTypedData: %v", kms.highS, err)
+		}
+		if v := signature[64]; v != 27 && v != 28 {
+			t.Errorf("highS=%v: v = %d", kms.highS, v)
+		}
+		if new(big.Int).SetBytes(signature[32:64]).Cmp(secp256k1HalfN) > 0 {
+			t.Errorf("highS=%v: s is not normalized", kms.highS)
+		}
+		sig := common.CopyBytes(signature)
+		sig[64] -= 27
+		pub, err := crypto.SigToPub(hash, sig)
+		if err != nil || crypto.PubkeyToAddress(*pub) != s.GetAddress() {
+			t.Errorf("highS=%v: signature does not recover to the signer: %v", kms.highS, err)
+		}
+	}
+}
+
+func TestKMSSigner_SignTransaction(t *testing.T) {
+	s := newTestKMSSigner(t, &softwareKMS{highS: true})
+	chainID := big.NewInt(137)
+	txs := []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(30e9), Gas: 21000, To: &testTxTo, Value: big.NewInt(1)}),
+		types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 2, GasTipCap: big.NewInt(30e9), GasFeeCap: big.NewInt(100e9), Gas: 50000, To: &testTxTo, Data: []byte{0x01}}),
+	}
+	for _, tx := range txs {
+		signed, err := s.SignTransactionWithChainID(tx, chainID)
+		if err != nil {
+			t.Fatalf("type %d: SignTransactionWithChainID: %v", tx.Type(), err)
+		}
+		if from, err := types.Sender(types.LatestSignerForChainID(chainID), signed); err != nil || from != s.GetAddress() {
+			t.Errorf("type %d: signed by %s: %v", tx.Type(), from.Hex(), err)
+		}
+	}
+}
+
+func TestKMSSigner_RejectsSignatureOfAnotherKey(t *testing.T) {
+	s := newTestKMSSigner(t, &softwareKMS{})
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	s.client.(*softwareKMS).key = other
+
+	if _, err := s.SignTypedData(testTypedData); !errors.Is(err, ErrKMSSignatureMismatch) {
+		t.Fatalf("got %v, want ErrKMSSignatureMismatch", err)
+	}
+	if _, err := derToEthSignature([]byte{0x30, 0x00}, make([]byte, 32), &other.PublicKey); err == nil {
+		t.Error("expected invalid DER signature to fail")
+	}
+}
+
+func TestKMSSigner_BacksSafeTradingSigner(t *testing.T) {
+	s := newTestKMSSigner(t, &softwareKMS{})
+	client := newMockTxClient()
+	txSender, err := GetTransactionSenderBySigner(big.NewInt(137), client, s)
+	if err != nil {
+		t.Fatalf("GetTransactionSenderBySigner: %v", err)
+	}
+	safeSigner := NewSimpleSafeTradingSigner(s.GetAddress(), s, txSender)
+
+	if _, err := safeSigner.SignTypedDataWithContext(context.Background(), testTypedData); err != nil {
+		t.Fatalf("SignTypedDataWithContext: %v", err)
+	}
+	hash, err := safeSigner.SendEthereumTransactionWithContext(context.Background(), testTxTo, []byte{0x01}, nil, sender.WithGasPrice(big.NewInt(100)))
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	sent := client.lastSent()
+	if from, _ := types.Sender(types.LatestSignerForChainID(big.NewInt(137)), sent); sent.Hash() != hash || from != s.GetAddress() {
+		t.Errorf("sent %s from %s", sent.Hash().Hex(), from.Hex())
+	}
+}
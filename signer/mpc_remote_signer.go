@@ -3,11 +3,13 @@ package signer
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math/big"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2/crypto"
@@ -16,9 +18,13 @@ import (
 	coboWaas2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
 	// "github.com/sirupsen/logrus"
 )
 
+var coboRequestSeq atomic.Uint64
+
 type CoboMpcSigner struct {
 	address common.Address
 
@@ -30,16 +36,41 @@ type CoboMpcSigner struct {
 	coboEnv        int
 
 	maxRetryCount int
+	pollInterval  time.Duration
+	updates       *coboUpdates
+	describe      func(typedData eip712.TypedData) (string, error)
 
 	// logger *logrus.Logger
 }
 
-func NewCoboMpcSigner(env int, privateKey, coboChainId string, ethChainId *big.Int, walletId string, address common.Address) (*CoboMpcSigner, error) {
+// CoboMpcSignerOption configures optional fields on CoboMpcSigner
+type CoboMpcSignerOption func(s *CoboMpcSigner)
+
+// WithCoboPollInterval sets how often Cobo is polled for transaction statuses (default: 3 seconds).
+// With webhooks forwarded to NotifyTransaction, a long interval only serves as a fallback.
+func WithCoboPollInterval(interval time.Duration) CoboMpcSignerOption {
+	return func(s *CoboMpcSigner) {
+		s.pollInterval = interval
+	}
+}
+
+// WithCoboDescriber sets the description of typed data signing requests shown to Cobo approvers, e.g.
+// polymarketcontracts.Describer.Describe. Requests are submitted without a description if describe fails.
+func WithCoboDescriber(describe func(typedData eip712.TypedData) (string, error)) CoboMpcSignerOption {
+	return func(s *CoboMpcSigner) {
+		s.describe = describe
+	}
+}
+
+func NewCoboMpcSigner(env int, privateKey, coboChainId string, ethChainId *big.Int, walletId string, address common.Address, opts ...CoboMpcSignerOption) (*CoboMpcSigner, error) {
 	if env != coboWaas2.ProdEnv && env != coboWaas2.DevEnv {
 		return nil, fmt.Errorf("env should be coboWaas2.ProdEnv or coboWaas2.DevEnv, got %d", env)
 	}
 
-	signer := CoboMpcSigner{coboEnv: env, coboPrivateKey: privateKey, coboChainId: coboChainId, ethChainId: ethChainId, coboWalletId: walletId, maxRetryCount: 100, address: address}
+	signer := CoboMpcSigner{coboEnv: env, coboPrivateKey: privateKey, coboChainId: coboChainId, ethChainId: ethChainId, coboWalletId: walletId, maxRetryCount: 100, pollInterval: 3 * time.Second, updates: &coboUpdates{}, address: address}
+	for _, opt := range opts {
+		opt(&signer)
+	}
 
 	configuration := coboWaas2.NewConfiguration()
 	tr := &http.Transport{
@@ -69,8 +100,40 @@ func (s CoboMpcSigner) CoboChainId() string {
 	return s.coboChainId
 }
 
-// SignTypedData signs EIP-712 typed data
+// SignTypedData signs EIP-712 typed data, polling Cobo up to 100 times.
+// Signatures waiting for authorization fail with ErrCoboPendingAuthorization; use SignTypedDataWithContext
+// or SignTypedDataAsync to wait for approvers.
 func (s *CoboMpcSigner) SignTypedData(typedData eip712.TypedData) ([]byte, error) {
+	req, err := s.SignTypedDataAsync(context.Background(), typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	txDetail, err := s.WaitTransactionStatus(req.TransactionId, coboWaas2.TRANSACTIONSTATUS_COMPLETED, s.maxRetryCount)
+	if err != nil {
+		return nil, err
+	}
+	return coboSignature(txDetail)
+}
+
+// SignTypedDataWithContext signs EIP-712 typed data, waiting for Cobo and its approvers until ctx is done.
+// The signing request is cancelled if ctx is done before it was signed.
+func (s *CoboMpcSigner) SignTypedDataWithContext(ctx context.Context, typedData eip712.TypedData) ([]byte, error) {
+	req, err := s.SignTypedDataAsync(ctx, typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := req.Signature(ctx)
+	if err != nil && ctx.Err() != nil {
+		// Best effort: do not leave a request for approvers that nobody waits for
+		_ = req.Cancel(context.Background())
+	}
+	return signature, err
+}
+
+// SignTypedDataAsync submits a request to sign EIP-712 typed data and returns without waiting for it
+func (s *CoboMpcSigner) SignTypedDataAsync(ctx context.Context, typedData eip712.TypedData, opts ...CoboRequestOption) (*CoboRequest, error) {
 	mpcSource := coboWaas2.NewMpcMessageSignSource(coboWaas2.MESSAGESIGNSOURCETYPE_ORG_CONTROLLED, s.coboWalletId, s.address.Hex())
 	signSource := coboWaas2.MpcMessageSignSourceAsMessageSignSource(mpcSource)
 
@@ -91,18 +154,22 @@ func (s *CoboMpcSigner) SignTypedData(typedData eip712.TypedData) ([]byte, error
 		signSource,
 		destination,
 	)
-
-	resp, r, err := s.coboClient.TransactionsAPI.CreateMessageSignTransaction(s.getCtx()).MessageSignParams(messageSignParams).Execute()
-	formattedResp, err := s.formatResponse(resp, r, err)
-	if err != nil {
-		return nil, err
+	if s.describe != nil {
+		if description, err := s.describe(typedData); err == nil {
+			messageSignParams.SetDescription(description)
+		}
 	}
 
-	txDetail, err := s.WaitTransactionStatus(formattedResp.TransactionId, coboWaas2.TRANSACTIONSTATUS_COMPLETED, s.maxRetryCount)
+	resp, r, err := s.coboClient.TransactionsAPI.CreateMessageSignTransaction(s.getCtx(ctx)).MessageSignParams(messageSignParams).Execute()
+	formattedResp, err := s.formatResponse(resp, r, err)
 	if err != nil {
 		return nil, err
 	}
+	return s.newRequest(formattedResp, messageSignParams.RequestId, opts...), nil
+}
 
+// coboSignature extracts the signature of a completed message sign transaction
+func coboSignature(txDetail *coboWaas2.TransactionDetail) ([]byte, error) {
 	// Extract signature from transaction result
 	if txDetail.Result == nil {
 		return nil, fmt.Errorf("transaction has no result")
@@ -134,6 +201,20 @@ func (s *CoboMpcSigner) SignTypedData(typedData eip712.TypedData) ([]byte, error
 }
 
 func (m *CoboMpcSigner) CallContract(to, callData, value string, fee *coboWaas2.TransactionRequestFee) (*coboWaas2.CreateTransferTransaction201Response, error) {
+	return m.callContract(context.Background(), m.createRequestId(), to, callData, value, fee)
+}
+
+// CallContractAsync submits a contract call and returns without waiting for it
+func (m *CoboMpcSigner) CallContractAsync(ctx context.Context, to, callData, value string, fee *coboWaas2.TransactionRequestFee, opts ...CoboRequestOption) (*CoboRequest, error) {
+	requestId := m.createRequestId()
+	resp, err := m.callContract(ctx, requestId, to, callData, value, fee)
+	if err != nil {
+		return nil, err
+	}
+	return m.newRequest(resp, requestId, opts...), nil
+}
+
+func (m *CoboMpcSigner) callContract(ctx context.Context, requestId, to, callData, value string, fee *coboWaas2.TransactionRequestFee) (*coboWaas2.CreateTransferTransaction201Response, error) {
 	//source
 	mpcSource := coboWaas2.NewMpcContractCallSource(
 		coboWaas2.CONTRACTCALLSOURCETYPE_ORG_CONTROLLED,
@@ -149,7 +230,7 @@ func (m *CoboMpcSigner) CallContract(to, callData, value string, fee *coboWaas2.
 	des := coboWaas2.EvmContractCallDestinationAsContractCallDestination(evmContractCallDes)
 	//param
 	contractCallParams := *coboWaas2.NewContractCallParams(
-		m.createRequestId(),
+		requestId,
 		m.coboChainId,
 		source,
 		des,
@@ -158,54 +239,182 @@ func (m *CoboMpcSigner) CallContract(to, callData, value string, fee *coboWaas2.
 		contractCallParams.Fee = fee
 	}
 
-	resp, r, err := m.coboClient.TransactionsAPI.CreateContractCallTransaction(m.getCtx()).ContractCallParams(contractCallParams).Execute()
+	resp, r, err := m.coboClient.TransactionsAPI.CreateContractCallTransaction(m.getCtx(ctx)).ContractCallParams(contractCallParams).Execute()
 	return m.formatResponse(resp, r, err)
 }
 
-func (m *CoboMpcSigner) WaitTransactionStatus(transactionId string, status coboWaas2.TransactionStatus, maxTryTime int) (*coboWaas2.TransactionDetail, error) {
-	if status == coboWaas2.TRANSACTIONSTATUS_FAILED || status == coboWaas2.TRANSACTIONSTATUS_REJECTED {
-		return nil, fmt.Errorf("invalid status")
+// SpeedUpTransaction replaces a Broadcasting transaction with the same transaction paying fee (Cobo RBF)
+// and returns the replacement
+func (m *CoboMpcSigner) SpeedUpTransaction(transactionId string, fee *coboWaas2.TransactionRequestFee) (*coboWaas2.CreateTransferTransaction201Response, error) {
+	return m.speedUpTransaction(context.Background(), transactionId, fee)
+}
+
+func (m *CoboMpcSigner) speedUpTransaction(ctx context.Context, transactionId string, fee *coboWaas2.TransactionRequestFee) (*coboWaas2.CreateTransferTransaction201Response, error) {
+	rbf := *coboWaas2.NewTransactionRbf(m.createRequestId(), *fee)
+	resp, r, err := m.coboClient.TransactionsAPI.SpeedupTransactionById(m.getCtx(ctx), transactionId).TransactionRbf(rbf).Execute()
+	return m.formatResponse(resp, r, err)
+}
+
+// DropTransaction replaces a Broadcasting transaction with a 0-value self-transfer paying fee (Cobo RBF)
+// and returns the replacement
+func (m *CoboMpcSigner) DropTransaction(transactionId string, fee *coboWaas2.TransactionRequestFee) (*coboWaas2.CreateTransferTransaction201Response, error) {
+	return m.dropTransaction(context.Background(), transactionId, fee)
+}
+
+func (m *CoboMpcSigner) dropTransaction(ctx context.Context, transactionId string, fee *coboWaas2.TransactionRequestFee) (*coboWaas2.CreateTransferTransaction201Response, error) {
+	rbf := *coboWaas2.NewTransactionRbf(m.createRequestId(), *fee)
+	resp, r, err := m.coboClient.TransactionsAPI.DropTransactionById(m.getCtx(ctx), transactionId).TransactionRbf(rbf).Execute()
+	return m.formatResponse(resp, r, err)
+}
+
+var (
+	_ CustodyProvider  = (*CoboMpcSigner)(nil)
+	_ CustodyReplacer  = (*CoboMpcSigner)(nil)
+	_ CustodyCanceller = (*CoboMpcSigner)(nil)
+	_ CustodyNotifier  = (*CoboMpcSigner)(nil)
+)
+
+// SubmitContractCall submits call as a Cobo contract call and returns its Cobo transaction id
+func (m *CoboMpcSigner) SubmitContractCall(ctx context.Context, call CustodyCall) (string, error) {
+	value := "0"
+	if call.Value != nil {
+		value = call.Value.String()
+	}
+	var fee *coboWaas2.TransactionRequestFee
+	if call.Fees != nil {
+		fee = m.coboFee(call.Fees, call.GasLimit)
+	}
+	resp, err := m.callContract(ctx, m.createRequestId(), call.To.Hex(), fmt.Sprintf("0x%x", call.Data), value, fee)
+	if err != nil {
+		return "", err
 	}
+	return resp.TransactionId, nil
+}
 
-	tryTime := 1
-	for {
-		if tryTime > maxTryTime {
-			return nil, fmt.Errorf("wait transaction get max try times")
-		}
-		resp, err := m.GetTransactionByTransactionId(transactionId)
-		if err != nil {
-			return nil, err
+// CallStatus returns the status of the Cobo transaction callId
+func (m *CoboMpcSigner) CallStatus(ctx context.Context, callId string) (*CustodyStatus, error) {
+	detail, err := m.getTransaction(ctx, callId)
+	if err != nil {
+		return nil, err
+	}
+	return coboCustodyStatus(detail), nil
+}
+
+// SpeedUpCall speeds up the Cobo transaction callId, see SpeedUpTransaction
+func (m *CoboMpcSigner) SpeedUpCall(ctx context.Context, callId string, fees *sender.GasFees, gasLimit uint64) (string, error) {
+	resp, err := m.speedUpTransaction(ctx, callId, m.coboFee(fees, gasLimit))
+	if err != nil {
+		return "", err
+	}
+	return resp.TransactionId, nil
+}
+
+// DropCall drops the Cobo transaction callId, see DropTransaction
+func (m *CoboMpcSigner) DropCall(ctx context.Context, callId string, fees *sender.GasFees) (string, error) {
+	resp, err := m.dropTransaction(ctx, callId, m.coboFee(fees, params.TxGas))
+	if err != nil {
+		return "", err
+	}
+	return resp.TransactionId, nil
+}
+
+// CancelCall cancels the Cobo transaction callId, e.g. while it waits for authorization
+func (m *CoboMpcSigner) CancelCall(ctx context.Context, callId string) error {
+	resp, httpResp, err := m.coboClient.TransactionsAPI.CancelTransactionById(m.getCtx(ctx), callId).Execute()
+	_, err = m.formatResponse(resp, httpResp, err)
+	return err
+}
+
+// CallUpdated returns a channel closed when a webhook forwarded to NotifyTransaction reports an update of callId
+func (m *CoboMpcSigner) CallUpdated(callId string) (<-chan struct{}, func()) {
+	return m.updates.updated(callId)
+}
+
+// coboFee converts fees to a Cobo fee with gasLimit
+func (m *CoboMpcSigner) coboFee(fees *sender.GasFees, gasLimit uint64) *coboWaas2.TransactionRequestFee {
+	limit := new(big.Int).SetUint64(gasLimit).String()
+	if fees.IsDynamic() {
+		fee := coboWaas2.NewTransactionRequestEvmEip1559Fee(fees.GasFeeCap.String(), fees.GasTipCap.String(), coboWaas2.FEETYPE_EVM_EIP_1559, m.coboChainId)
+		fee.SetGasLimit(limit)
+		paramFee := coboWaas2.TransactionRequestEvmEip1559FeeAsTransactionRequestFee(fee)
+		return &paramFee
+	}
+	fee := coboWaas2.NewTransactionRequestEvmLegacyFee(fees.GasPrice.String(), coboWaas2.FEETYPE_EVM_LEGACY, m.coboChainId)
+	fee.SetGasLimit(limit)
+	paramFee := coboWaas2.TransactionRequestEvmLegacyFeeAsTransactionRequestFee(fee)
+	return &paramFee
+}
+
+// coboCustodyStatus maps a Cobo transaction detail to a CustodyStatus
+func coboCustodyStatus(detail *coboWaas2.TransactionDetail) *CustodyStatus {
+	if replacement := detail.Replacement; replacement != nil && replacement.GetReplacedByTransactionId() != "" {
+		status := &CustodyStatus{State: CustodyPending, ReplacedBy: replacement.GetReplacedByTransactionId()}
+		if replacement.GetReplacedByType() == coboWaas2.REPLACETYPE_DROP {
+			status.State = CustodyDropped
+			status.Err = newCoboTransactionError(detail, ErrCoboTransactionDropped)
 		}
+		return status
+	}
 
-		switch resp.Status {
-		case status:
-			//done
-			return resp, nil
-		case coboWaas2.TRANSACTIONSTATUS_REJECTED:
-			return nil, fmt.Errorf("transaction id: %s, request id: %s, cobo id: %s, REJECTED", transactionId, *resp.RequestId, *resp.CoboId)
-		case coboWaas2.TRANSACTIONSTATUS_FAILED:
-			return nil, fmt.Errorf("transaction id: %s, request id: %s, cobo id: %s, FAILED", transactionId, *resp.RequestId, *resp.CoboId)
-		default:
-			//continue when got other status
-			log.Printf("transaction id: %s, request id: %s, cobo id: %s, status: %s", transactionId, *resp.RequestId, *resp.CoboId, resp.Status)
+	hash := common.HexToHash(detail.GetTransactionHash())
+	switch detail.Status {
+	case coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION:
+		return &CustodyStatus{State: CustodyAwaitingApproval, Err: newCoboTransactionError(detail, ErrCoboPendingAuthorization)}
+	case coboWaas2.TRANSACTIONSTATUS_BROADCASTING:
+		if detail.GetTransactionHash() == "" {
+			// The hash may only be reported once the transaction is on chain
+			return &CustodyStatus{State: CustodyPending}
 		}
+		return &CustodyStatus{State: CustodyBroadcast, TxHash: hash}
+	case coboWaas2.TRANSACTIONSTATUS_CONFIRMING, coboWaas2.TRANSACTIONSTATUS_COMPLETED:
+		return &CustodyStatus{State: CustodyMined, TxHash: hash}
+	case coboWaas2.TRANSACTIONSTATUS_REJECTED:
+		return &CustodyStatus{State: CustodyFailed, Err: newCoboTransactionError(detail, ErrCoboTransactionRejected)}
+	case coboWaas2.TRANSACTIONSTATUS_FAILED:
+		return &CustodyStatus{State: CustodyFailed, Err: newCoboTransactionError(detail, ErrCoboTransactionFailed)}
+	default:
+		return &CustodyStatus{State: CustodyPending}
+	}
+}
 
-		time.Sleep(3 * time.Second)
-		tryTime++
+// WaitTransactionStatus polls the transaction every poll interval (3 seconds by default) until it reaches status or a later one,
+// following speed-ups and resends to the replacing transaction. Failed, rejected and dropped transactions,
+// transactions waiting for authorization and running out of tries are reported as *CoboTransactionError.
+func (m *CoboMpcSigner) WaitTransactionStatus(transactionId string, status coboWaas2.TransactionStatus, maxTryTime int) (*coboWaas2.TransactionDetail, error) {
+	return m.waitStatus(context.Background(), transactionId, status, coboWait{maxTryTime: maxTryTime})
+}
+
+// WaitTransactionStatusWithContext is WaitTransactionStatus bounded by ctx instead of a number of tries.
+// Transactions waiting for authorization are waited for as well.
+func (m *CoboMpcSigner) WaitTransactionStatusWithContext(ctx context.Context, transactionId string, status coboWaas2.TransactionStatus) (*coboWaas2.TransactionDetail, error) {
+	return m.waitStatus(ctx, transactionId, status, coboWait{awaitAuthorization: true})
+}
+
+// waitStatus waits for the transaction with w, polling Cobo every poll interval and on webhook updates
+func (m *CoboMpcSigner) waitStatus(ctx context.Context, transactionId string, status coboWaas2.TransactionStatus, w coboWait) (*coboWaas2.TransactionDetail, error) {
+	get := func(transactionId string) (*coboWaas2.TransactionDetail, error) {
+		return m.getTransaction(ctx, transactionId)
 	}
+	w.interval = m.pollInterval
+	w.updated = m.updates.updated
+	return waitCoboStatus(ctx, get, transactionId, status, w)
 }
 
 func (m *CoboMpcSigner) GetTransactionByTransactionId(transactionId string) (*coboWaas2.TransactionDetail, error) {
-	resp, _, err := m.coboClient.TransactionsAPI.GetTransactionById(m.getCtx(), transactionId).Execute()
+	return m.getTransaction(context.Background(), transactionId)
+}
+
+func (m *CoboMpcSigner) getTransaction(ctx context.Context, transactionId string) (*coboWaas2.TransactionDetail, error) {
+	resp, _, err := m.coboClient.TransactionsAPI.GetTransactionById(m.getCtx(ctx), transactionId).Execute()
 	return resp, err
 }
 
 func (s *CoboMpcSigner) createRequestId() string {
-	return fmt.Sprintf("cobo-mpc-go-v2-%d", time.Now().UnixMilli())
+	// Request ids must be unique: requests may be submitted concurrently within the same millisecond
+	return fmt.Sprintf("cobo-mpc-go-v2-%d-%d", time.Now().UnixMilli(), coboRequestSeq.Add(1))
 }
 
-func (m *CoboMpcSigner) getCtx() context.Context {
-	ctx := context.Background()
+func (m *CoboMpcSigner) getCtx(ctx context.Context) context.Context {
 	ctx = context.WithValue(ctx, coboWaas2.ContextEnv, m.coboEnv)
 	ctx = context.WithValue(ctx, coboWaas2.ContextPortalSigner, crypto.Ed25519Signer{
 		Secret: m.coboPrivateKey,
@@ -230,3 +439,160 @@ func (m *CoboMpcSigner) formatResponse(resp *coboWaas2.CreateTransferTransaction
 
 	return nil, fmt.Errorf("err: %s, nil body", err.Error())
 }
+
+var (
+	// ErrCoboTransactionFailed is wrapped by CoboTransactionError for transactions Cobo reports as Failed
+	ErrCoboTransactionFailed = errors.New("Cobo transaction failed")
+	// ErrCoboTransactionRejected is wrapped by CoboTransactionError for transactions rejected by an approver or a policy
+	ErrCoboTransactionRejected = errors.New("Cobo transaction rejected")
+	// ErrCoboPendingAuthorization is wrapped by CoboTransactionError for transactions waiting for an approver.
+	// Wait for the transaction again once it is approved.
+	ErrCoboPendingAuthorization = errors.New("Cobo transaction is pending authorization")
+	// ErrCoboTransactionDropped is wrapped by CoboTransactionError for transactions replaced by a drop
+	ErrCoboTransactionDropped = errors.New("Cobo transaction was dropped")
+	// ErrCoboWaitTimeout is wrapped by CoboTransactionError when the expected status was not reached in time
+	ErrCoboWaitTimeout = errors.New("Cobo transaction did not reach the expected status in time")
+)
+
+// CoboTransactionError reports a Cobo transaction that did not reach the expected status.
+// It wraps one of the ErrCobo* errors.
+type CoboTransactionError struct {
+	TransactionId string
+	RequestId     string
+	CoboId        string
+	Status        coboWaas2.TransactionStatus
+	SubStatus     string
+	// Reason is Cobo's failed reason, if any
+	Reason string
+
+	err error
+}
+
+func newCoboTransactionError(detail *coboWaas2.TransactionDetail, err error) *CoboTransactionError {
+	e := &CoboTransactionError{
+		TransactionId: detail.TransactionId,
+		RequestId:     detail.GetRequestId(),
+		CoboId:        detail.GetCoboId(),
+		Status:        detail.Status,
+		Reason:        detail.GetFailedReason(),
+		err:           err,
+	}
+	if detail.SubStatus != nil {
+		e.SubStatus = string(*detail.SubStatus)
+	}
+	return e
+}
+
+func (e *CoboTransactionError) Error() string {
+	msg := fmt.Sprintf("%s: transaction id: %s, request id: %s, cobo id: %s, status: %s", e.err, e.TransactionId, e.RequestId, e.CoboId, e.Status)
+	if e.SubStatus != "" {
+		msg += "/" + e.SubStatus
+	}
+	if e.Reason != "" {
+		msg += ", reason: " + e.Reason
+	}
+	return msg
+}
+
+func (e *CoboTransactionError) Unwrap() error {
+	return e.err
+}
+
+// coboStatusRank orders the statuses a successful transaction goes through
+var coboStatusRank = map[coboWaas2.TransactionStatus]int{
+	coboWaas2.TRANSACTIONSTATUS_SUBMITTED:             1,
+	coboWaas2.TRANSACTIONSTATUS_PENDING_SCREENING:     2,
+	coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION: 3,
+	coboWaas2.TRANSACTIONSTATUS_PENDING_SIGNATURE:     4,
+	coboWaas2.TRANSACTIONSTATUS_BROADCASTING:          5,
+	coboWaas2.TRANSACTIONSTATUS_CONFIRMING:            6,
+	coboWaas2.TRANSACTIONSTATUS_COMPLETED:             7,
+}
+
+// coboStatusReached reports whether a transaction in status current has gone through target
+func coboStatusReached(current, target coboWaas2.TransactionStatus) bool {
+	if current == target {
+		return true
+	}
+	rank, ok := coboStatusRank[target]
+	return ok && coboStatusRank[current] >= rank
+}
+
+// coboWait configures waitCoboStatus
+type coboWait struct {
+	// maxTryTime bounds the number of polls; 0 polls until ctx is done
+	maxTryTime int
+	interval   time.Duration
+	// awaitAuthorization keeps waiting while approvers have not authorized the transaction
+	awaitAuthorization bool
+	// updated returns a channel closed when a webhook reports an update of the transaction, polling it early,
+	// and a func unsubscribing from it
+	updated func(transactionId string) (<-chan struct{}, func())
+	// onStatus is called with every new status seen
+	onStatus func(detail *coboWaas2.TransactionDetail)
+}
+
+// waitCoboStatus polls transactionId with get until it reaches status, see WaitTransactionStatus
+func waitCoboStatus(ctx context.Context, get func(transactionId string) (*coboWaas2.TransactionDetail, error), transactionId string, status coboWaas2.TransactionStatus, w coboWait) (*coboWaas2.TransactionDetail, error) {
+	if status == coboWaas2.TRANSACTIONSTATUS_FAILED || status == coboWaas2.TRANSACTIONSTATUS_REJECTED {
+		return nil, fmt.Errorf("invalid status")
+	}
+
+	var lastStatus coboWaas2.TransactionStatus
+	unsubscribe := func() {}
+	defer func() { unsubscribe() }()
+	for tryTime := 1; ; tryTime++ {
+		unsubscribe()
+		var updated <-chan struct{}
+		if w.updated != nil {
+			// Subscribe before polling so an update in between is not missed
+			updated, unsubscribe = w.updated(transactionId)
+		}
+
+		resp, err := get(transactionId)
+		if err != nil {
+			return nil, err
+		}
+		if w.onStatus != nil && resp.Status != lastStatus {
+			w.onStatus(resp)
+		}
+		lastStatus = resp.Status
+
+		if replacement := resp.Replacement; replacement != nil && replacement.GetReplacedByTransactionId() != "" {
+			if replacement.GetReplacedByType() == coboWaas2.REPLACETYPE_DROP {
+				return resp, newCoboTransactionError(resp, ErrCoboTransactionDropped)
+			}
+			// Sped up or resent: the replacing transaction is the one that gets mined
+			transactionId = replacement.GetReplacedByTransactionId()
+			lastStatus = ""
+			continue
+		}
+
+		switch {
+		case coboStatusReached(resp.Status, status):
+			return resp, nil
+		case resp.Status == coboWaas2.TRANSACTIONSTATUS_REJECTED:
+			return nil, newCoboTransactionError(resp, ErrCoboTransactionRejected)
+		case resp.Status == coboWaas2.TRANSACTIONSTATUS_FAILED:
+			return nil, newCoboTransactionError(resp, ErrCoboTransactionFailed)
+		case resp.Status == coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION && !w.awaitAuthorization:
+			return nil, newCoboTransactionError(resp, ErrCoboPendingAuthorization)
+		default:
+			//continue when got other status
+			log.Printf("transaction id: %s, request id: %s, cobo id: %s, status: %s", transactionId, resp.GetRequestId(), resp.GetCoboId(), resp.Status)
+		}
+
+		if w.maxTryTime > 0 && tryTime >= w.maxTryTime {
+			return nil, newCoboTransactionError(resp, ErrCoboWaitTimeout)
+		}
+		timer := time.NewTimer(w.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w: %w", newCoboTransactionError(resp, ErrCoboWaitTimeout), ctx.Err())
+		case <-updated:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	coboWaas2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// coboStatuses serves the successive statuses of Cobo transactions
+type coboStatuses map[string][]*coboWaas2.TransactionDetail
+
+func (c coboStatuses) get(transactionId string) (*coboWaas2.TransactionDetail, error) {
+	details := c[transactionId]
+	if len(details) == 0 {
+		return nil, errors.New("unknown transaction " + transactionId)
+	}
+	if len(details) > 1 {
+		c[transactionId] = details[1:]
+	}
+	return details[0], nil
+}
+
+func coboDetail(id string, status coboWaas2.TransactionStatus) *coboWaas2.TransactionDetail {
+	return &coboWaas2.TransactionDetail{TransactionId: id, Status: status}
+}
+
+func coboReplaced(id string, replaceType coboWaas2.ReplaceType, by string) *coboWaas2.TransactionDetail {
+	d := coboDetail(id, coboWaas2.TRANSACTIONSTATUS_BROADCASTING)
+	d.Replacement = &coboWaas2.TransactionReplacement{ReplacedByType: &replaceType, ReplacedByTransactionId: &by}
+	return d
+}
+
+func TestWaitCoboStatus(t *testing.T) {
+	ctx := context.Background()
+
+	// A later status than the expected one counts as reached
+	statuses := coboStatuses{"a": {
+		coboDetail("a", coboWaas2.TRANSACTIONSTATUS_SUBMITTED),
+		coboDetail("a", coboWaas2.TRANSACTIONSTATUS_CONFIRMING),
+	}}
+	if d, err := waitCoboStatus(ctx, statuses.get, "a", coboWaas2.TRANSACTIONSTATUS_BROADCASTING, coboWait{maxTryTime: 5}); err != nil || d.Status != coboWaas2.TRANSACTIONSTATUS_CONFIRMING {
+		t.Fatalf("wait = %v, %v", d, err)
+	}
+
+	// Speed-ups are followed to the replacing transaction
+	statuses = coboStatuses{
+		"a": {coboReplaced("a", coboWaas2.REPLACETYPE_SPEED_UP, "b")},
+		"b": {coboDetail("b", coboWaas2.TRANSACTIONSTATUS_COMPLETED)},
+	}
+	if d, err := waitCoboStatus(ctx, statuses.get, "a", coboWaas2.TRANSACTIONSTATUS_CONFIRMING, coboWait{maxTryTime: 5}); err != nil || d.TransactionId != "b" {
+		t.Fatalf("speed-up: wait = %v, %v", d, err)
+	}
+
+	tests := []struct {
+		detail *coboWaas2.TransactionDetail
+		want   error
+	}{
+		{coboDetail("a", coboWaas2.TRANSACTIONSTATUS_FAILED), ErrCoboTransactionFailed},
+		{coboDetail("a", coboWaas2.TRANSACTIONSTATUS_REJECTED), ErrCoboTransactionRejected},
+		{coboDetail("a", coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION), ErrCoboPendingAuthorization},
+		{coboReplaced("a", coboWaas2.REPLACETYPE_DROP, "b"), ErrCoboTransactionDropped},
+		{coboDetail("a", coboWaas2.TRANSACTIONSTATUS_PENDING_SIGNATURE), ErrCoboWaitTimeout},
+	}
+	for _, tt := range tests {
+		statuses := coboStatuses{"a": {tt.detail}}
+		_, err := waitCoboStatus(ctx, statuses.get, "a", coboWaas2.TRANSACTIONSTATUS_CONFIRMING, coboWait{maxTryTime: 3})
+		var coboErr *CoboTransactionError
+		if !errors.Is(err, tt.want) || !errors.As(err, &coboErr) || coboErr.TransactionId != "a" {
+			t.Errorf("%s: err = %v, want %v", tt.detail.Status, err, tt.want)
+		}
+	}
+
+	// Waiting for authorization itself is allowed
+	statuses = coboStatuses{"a": {coboDetail("a", coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION)}}
+	if _, err := waitCoboStatus(ctx, statuses.get, "a", coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION, coboWait{maxTryTime: 1}); err != nil {
+		t.Fatalf("pending authorization: %v", err)
+	}
+}
+
+func TestWaitCoboStatusAwaitsApproversAndWebhooks(t *testing.T) {
+	statuses := coboStatuses{"a": {
+		coboDetail("a", coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION),
+		coboDetail("a", coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION),
+		coboDetail("a", coboWaas2.TRANSACTIONSTATUS_COMPLETED),
+	}}
+	updates := &coboUpdates{subs: make(map[string]*coboSubscription)}
+
+	var seen []coboWaas2.TransactionStatus
+	w := coboWait{
+		// Without webhook updates the test would time out
+		interval:           time.Hour,
+		awaitAuthorization: true,
+		updated:            updates.updated,
+		onStatus:           func(d *coboWaas2.TransactionDetail) { seen = append(seen, d.Status) },
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := waitCoboStatus(context.Background(), statuses.get, "a", coboWaas2.TRANSACTIONSTATUS_COMPLETED, w)
+		done <- err
+	}()
+
+	for {
+		updates.notify("a")
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("wait: %v", err)
+			}
+			if len(seen) != 2 || seen[0] != coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION || seen[1] != coboWaas2.TRANSACTIONSTATUS_COMPLETED {
+				t.Fatalf("seen statuses %v", seen)
+			}
+			updates.mu.Lock()
+			defer updates.mu.Unlock()
+			if len(updates.subs) != 0 {
+				t.Fatalf("%d subscriptions left after the wait", len(updates.subs))
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestCoboUpdates_Unsubscribe(t *testing.T) {
+	updates := &coboUpdates{subs: make(map[string]*coboSubscription)}
+
+	first, unsubscribeFirst := updates.updated("a")
+	second, unsubscribeSecond := updates.updated("a")
+	if first != second {
+		t.Fatal("expected waiters of a transaction to share its channel")
+	}
+	unsubscribeFirst()
+	unsubscribeFirst()
+	if len(updates.subs) != 1 {
+		t.Fatal("subscription removed while a waiter is left")
+	}
+	unsubscribeSecond()
+	if len(updates.subs) != 0 {
+		t.Fatal("subscription kept after its last waiter left")
+	}
+
+	// A notification replaces the closed subscription: unsubscribing from it leaves the new one alone
+	old, unsubscribeOld := updates.updated("a")
+	updates.notify("a")
+	<-old
+	_, unsubscribeNew := updates.updated("a")
+	unsubscribeOld()
+	if len(updates.subs) != 1 {
+		t.Fatal("unsubscribing from a notified channel removed the new subscription")
+	}
+	unsubscribeNew()
+}
+
+func TestWaitCoboStatusStopsWithContext(t *testing.T) {
+	statuses := coboStatuses{"a": {coboDetail("a", coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION)}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := waitCoboStatus(ctx, statuses.get, "a", coboWaas2.TRANSACTIONSTATUS_COMPLETED, coboWait{interval: time.Millisecond, awaitAuthorization: true})
+	if !errors.Is(err, ErrCoboWaitTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestCoboCustodyStatus(t *testing.T) {
+	hash := "0x" + strings.Repeat("ab", 32)
+	withHash := func(d *coboWaas2.TransactionDetail) *coboWaas2.TransactionDetail {
+		d.TransactionHash = &hash
+		return d
+	}
+
+	tests := []struct {
+		detail *coboWaas2.TransactionDetail
+		state  CustodyState
+		hash   bool
+		err    error
+	}{
+		{detail: coboDetail("a", coboWaas2.TRANSACTIONSTATUS_PENDING_SIGNATURE), state: CustodyPending},
+		{detail: coboDetail("a", coboWaas2.TRANSACTIONSTATUS_PENDING_AUTHORIZATION), state: CustodyAwaitingApproval, err: ErrCoboPendingAuthorization},
+		{detail: coboDetail("a", coboWaas2.TRANSACTIONSTATUS_BROADCASTING), state: CustodyPending},
+		{detail: withHash(coboDetail("a", coboWaas2.TRANSACTIONSTATUS_BROADCASTING)), state: CustodyBroadcast, hash: true},
+		{detail: withHash(coboDetail("a", coboWaas2.TRANSACTIONSTATUS_CONFIRMING)), state: CustodyMined, hash: true},
+		{detail: coboDetail("a", coboWaas2.TRANSACTIONSTATUS_REJECTED), state: CustodyFailed, err: ErrCoboTransactionRejected},
+		{detail: coboDetail("a", coboWaas2.TRANSACTIONSTATUS_FAILED), state: CustodyFailed, err: ErrCoboTransactionFailed},
+		{detail: coboReplaced("a", coboWaas2.REPLACETYPE_SPEED_UP, "b"), state: CustodyPending},
+		{detail: coboReplaced("a", coboWaas2.REPLACETYPE_DROP, "b"), state: CustodyDropped, err: ErrCoboTransactionDropped},
+	}
+	for _, test := range tests {
+		status := coboCustodyStatus(test.detail)
+		if status.State != test.state || (status.TxHash != common.Hash{}) != test.hash || !errors.Is(status.Err, test.err) || (status.Err == nil) != (test.err == nil) {
+			t.Errorf("%s: got %+v, want state %s", test.detail.Status, status, test.state)
+		}
+		if test.detail.Replacement != nil && status.ReplacedBy != "b" {
+			t.Errorf("%s: replaced by %q, want b", test.detail.Status, status.ReplacedBy)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package signer
+
+import (
+	"context"
+	"sync"
+
+	coboWaas2 "github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
+)
+
+// CoboRequest is a handle on a request submitted to Cobo: a message to sign or a contract call.
+// Approvers may take a long time, so nothing waits for it until Wait, WaitStatus or Signature is called.
+type CoboRequest struct {
+	TransactionId string
+	RequestId     string
+
+	signer   *CoboMpcSigner
+	onStatus func(detail *coboWaas2.TransactionDetail)
+}
+
+// CoboRequestOption configures optional fields on CoboRequest
+type CoboRequestOption func(r *CoboRequest)
+
+// WithCoboStatusCallback calls fn with every new status of the request seen while waiting for it
+func WithCoboStatusCallback(fn func(detail *coboWaas2.TransactionDetail)) CoboRequestOption {
+	return func(r *CoboRequest) {
+		r.onStatus = fn
+	}
+}
+
+func (m *CoboMpcSigner) newRequest(resp *coboWaas2.CreateTransferTransaction201Response, requestId string, opts ...CoboRequestOption) *CoboRequest {
+	r := &CoboRequest{TransactionId: resp.TransactionId, RequestId: requestId, signer: m}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Status returns the current state of the request
+func (r *CoboRequest) Status(ctx context.Context) (*coboWaas2.TransactionDetail, error) {
+	return r.signer.getTransaction(ctx, r.TransactionId)
+}
+
+// Wait waits until the request is completed or ctx is done, waiting for approvers as long as needed.
+// Failed, rejected and dropped requests are reported as *CoboTransactionError.
+func (r *CoboRequest) Wait(ctx context.Context) (*coboWaas2.TransactionDetail, error) {
+	return r.WaitStatus(ctx, coboWaas2.TRANSACTIONSTATUS_COMPLETED)
+}
+
+// WaitStatus is Wait for status or a later one, e.g. TRANSACTIONSTATUS_BROADCASTING for a contract call
+func (r *CoboRequest) WaitStatus(ctx context.Context, status coboWaas2.TransactionStatus) (*coboWaas2.TransactionDetail, error) {
+	return r.signer.waitStatus(ctx, r.TransactionId, status, coboWait{awaitAuthorization: true, onStatus: r.onStatus})
+}
+
+// Signature waits for a message sign request and returns the signature
+func (r *CoboRequest) Signature(ctx context.Context) ([]byte, error) {
+	txDetail, err := r.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return coboSignature(txDetail)
+}
+
+// Cancel cancels the request. Cobo only cancels requests that are not broadcast yet,
+// use the sender's Cancel for contract calls pending on chain.
+func (r *CoboRequest) Cancel(ctx context.Context) error {
+	return r.signer.CancelCall(ctx, r.TransactionId)
+}
+
+// NotifyTransaction reports that Cobo updated a transaction, e.g. from a webhook handler.
+// Requests waiting for it poll it right away instead of at the next poll interval.
+func (m *CoboMpcSigner) NotifyTransaction(transactionId string) {
+	m.updates.notify(transactionId)
+}
+
+// HandleWebhookEvent passes a Cobo webhook event to NotifyTransaction. Events other than transaction
+// updates are ignored. Verify the event signature before calling it.
+func (m *CoboMpcSigner) HandleWebhookEvent(event *coboWaas2.WebhookEvent) {
+	if data := event.Data.TransactionWebhookEventData; data != nil {
+		m.NotifyTransaction(data.TransactionId)
+	}
+}
+
+// coboUpdates wakes waiters of transactions reported as updated
+type coboUpdates struct {
+	mu sync.Mutex
+	// subs is nil until the first notification: without webhooks waiters only poll
+	subs map[string]*coboSubscription
+}
+
+// coboSubscription is the channel closed on the next update of a transaction, shared by its waiters
+type coboSubscription struct {
+	ch      chan struct{}
+	waiters int
+}
+
+// updated returns a channel closed on the next update of transactionId, and a func to call once the channel
+// is no longer waited on. The subscription is removed when its last waiter unsubscribes.
+func (u *coboUpdates) updated(transactionId string) (<-chan struct{}, func()) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.subs == nil {
+		return nil, func() {}
+	}
+	sub, ok := u.subs[transactionId]
+	if !ok {
+		sub = &coboSubscription{ch: make(chan struct{})}
+		u.subs[transactionId] = sub
+	}
+	sub.waiters++
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			u.mu.Lock()
+			defer u.mu.Unlock()
+			sub.waiters--
+			// A notification already replaced a closed subscription
+			if sub.waiters == 0 && u.subs[transactionId] == sub {
+				delete(u.subs, transactionId)
+			}
+		})
+	}
+}
+
+func (u *coboUpdates) notify(transactionId string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.subs == nil {
+		u.subs = make(map[string]*coboSubscription)
+	}
+	if sub, ok := u.subs[transactionId]; ok {
+		close(sub.ch)
+		delete(u.subs, transactionId)
+	}
+}
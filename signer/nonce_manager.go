@@ -0,0 +1,205 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceReader is the subset of the Ethereum client needed to sync nonces with the chain
+type NonceReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager allocates transaction nonces locally, per address.
+//
+// The first allocation for an address syncs with PendingNonceAt. After that, nonces are
+// handed out from a local counter, so several transactions can be sent back-to-back
+// without waiting for the previous one to be mined. Nonces that were allocated but
+// never broadcast are released and handed out again before the counter advances, so
+// a failed send does not leave a gap that blocks every later transaction.
+//
+// A NonceManager is safe for concurrent use by multiple goroutines.
+type NonceManager struct {
+	client NonceReader
+
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonces
+}
+
+type accountNonces struct {
+	mu       sync.Mutex
+	synced   bool
+	next     uint64
+	released []uint64 // allocated but never broadcast, sorted ascending
+	inflight map[uint64]struct{}
+}
+
+// NewNonceManager creates a NonceManager that syncs with the given client
+func NewNonceManager(client NonceReader) *NonceManager {
+	return &NonceManager{
+		client:   client,
+		accounts: make(map[common.Address]*accountNonces),
+	}
+}
+
+var defaultNonceManagers sync.Map // key: defaultNonceManagerKey, value: *NonceManager
+
+type defaultNonceManagerKey struct {
+	chainId string
+	client  NonceReader
+}
+
+// getDefaultNonceManager returns the NonceManager shared by all senders on the same chain and client,
+// so separate senders for the same signer never hand out the same nonce. Senders on different clients
+// get separate managers, each syncing through its own client. A client whose dynamic type is not
+// comparable cannot be used as a key and gets a fresh, unshared manager.
+func getDefaultNonceManager(chainId *big.Int, client NonceReader) *NonceManager {
+	if client == nil || !reflect.TypeOf(client).Comparable() {
+		return NewNonceManager(client)
+	}
+	key := defaultNonceManagerKey{chainId: "<nil>", client: client}
+	if chainId != nil {
+		key.chainId = chainId.String()
+	}
+	nm, _ := defaultNonceManagers.LoadOrStore(key, NewNonceManager(client))
+	return nm.(*NonceManager)
+}
+
+func (m *NonceManager) account(addr common.Address) *accountNonces {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, ok := m.accounts[addr]
+	if !ok {
+		a = &accountNonces{inflight: make(map[uint64]struct{})}
+		m.accounts[addr] = a
+	}
+	return a
+}
+
+// Next allocates the next nonce for the given address.
+// Every allocated nonce must be followed by exactly one call to Commit or Release.
+func (m *NonceManager) Next(ctx context.Context, addr common.Address) (uint64, error) {
+	a := m.account(addr)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.synced {
+		if err := m.sync(ctx, addr, a); err != nil {
+			return 0, err
+		}
+	}
+
+	var nonce uint64
+	if len(a.released) > 0 {
+		nonce = a.released[0]
+		a.released = a.released[1:]
+	} else {
+		nonce = a.next
+		a.next++
+	}
+	a.inflight[nonce] = struct{}{}
+
+	return nonce, nil
+}
+
+// Commit marks an allocated nonce as broadcast
+func (m *NonceManager) Commit(addr common.Address, nonce uint64) {
+	a := m.account(addr)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.inflight, nonce)
+}
+
+// Release returns an allocated nonce that was never broadcast, so it is reused by the next allocation
+func (m *NonceManager) Release(addr common.Address, nonce uint64) {
+	a := m.account(addr)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.inflight[nonce]; !ok {
+		return
+	}
+	delete(a.inflight, nonce)
+
+	if nonce+1 == a.next {
+		a.next--
+		return
+	}
+
+	i := sort.Search(len(a.released), func(i int) bool { return a.released[i] >= nonce })
+	a.released = append(a.released, 0)
+	copy(a.released[i+1:], a.released[i:])
+	a.released[i] = nonce
+}
+
+// Invalidate forces the next allocation for the address to resync with the chain.
+// Senders call it after a broadcast error, since the local view may no longer match the node.
+func (m *NonceManager) Invalidate(addr common.Address) {
+	a := m.account(addr)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.synced = false
+}
+
+// Sync immediately resyncs the local nonce of the address with the chain's pending nonce
+func (m *NonceManager) Sync(ctx context.Context, addr common.Address) error {
+	a := m.account(addr)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return m.sync(ctx, addr, a)
+}
+
+// Peek returns the nonce the next allocation would return without allocating it.
+// The second return value is false if the address has not been synced yet.
+func (m *NonceManager) Peek(addr common.Address) (uint64, bool) {
+	a := m.account(addr)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.synced {
+		return 0, false
+	}
+	if len(a.released) > 0 {
+		return a.released[0], true
+	}
+	return a.next, true
+}
+
+func (m *NonceManager) sync(ctx context.Context, addr common.Address, a *accountNonces) error {
+	pending, err := m.client.PendingNonceAt(ctx, addr)
+	if err != nil {
+		return fmt.Errorf("failed to get pending nonce for %s: %w", addr.Hex(), err)
+	}
+
+	switch {
+	case pending > a.next:
+		// First sync, or transactions were sent for this address outside of this manager
+		a.next = pending
+	case pending < a.next && len(a.inflight) == 0:
+		// Gap: nonces below next were handed out but never reached the node (dropped or failed).
+		// Rewind so later transactions are not stuck behind the missing ones.
+		a.next = pending
+	}
+
+	// Released nonces below the pending nonce were consumed by someone else
+	kept := a.released[:0]
+	for _, n := range a.released {
+		if n >= pending && n < a.next {
+			kept = append(kept, n)
+		}
+	}
+	a.released = kept
+	a.synced = true
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// mockNonceReader returns a configurable pending nonce.
+type mockNonceReader struct {
+	mu      sync.Mutex
+	pending uint64
+	calls   int
+	err     error
+}
+
+func (m *mockNonceReader) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	return m.pending, m.err
+}
+
+func (m *mockNonceReader) setPending(n uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending = n
+}
+
+var testNonceAddr = common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+func TestNonceManager_SyncsOnceThenAllocatesLocally(t *testing.T) {
+	reader := &mockNonceReader{pending: 7}
+	nm := NewNonceManager(reader)
+	ctx := context.Background()
+
+	for want := uint64(7); want < 10; want++ {
+		got, err := nm.Next(ctx, testNonceAddr)
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if got != want {
+			t.Errorf("expected nonce %d, got %d", want, got)
+		}
+		nm.Commit(testNonceAddr, got)
+	}
+
+	if reader.calls != 1 {
+		t.Errorf("expected 1 PendingNonceAt call, got %d", reader.calls)
+	}
+}
+
+func TestNonceManager_ReleaseReusesNonce(t *testing.T) {
+	nm := NewNonceManager(&mockNonceReader{pending: 0})
+	ctx := context.Background()
+
+	n0, _ := nm.Next(ctx, testNonceAddr)
+	n1, _ := nm.Next(ctx, testNonceAddr)
+	n2, _ := nm.Next(ctx, testNonceAddr)
+	nm.Commit(testNonceAddr, n0)
+	nm.Commit(testNonceAddr, n2)
+
+	// n1 failed to broadcast: it must be handed out again before the counter advances
+	nm.Release(testNonceAddr, n1)
+
+	got, _ := nm.Next(ctx, testNonceAddr)
+	if got != n1 {
+		t.Errorf("expected released nonce %d to be reused, got %d", n1, got)
+	}
+	nm.Commit(testNonceAddr, got)
+
+	got, _ = nm.Next(ctx, testNonceAddr)
+	if got != 3 {
+		t.Errorf("expected nonce 3, got %d", got)
+	}
+}
+
+func TestNonceManager_ReleaseLastRewinds(t *testing.T) {
+	nm := NewNonceManager(&mockNonceReader{pending: 4})
+	ctx := context.Background()
+
+	n, _ := nm.Next(ctx, testNonceAddr)
+	nm.Release(testNonceAddr, n)
+
+	if next, _ := nm.Peek(testNonceAddr); next != 4 {
+		t.Errorf("expected next nonce 4 after release, got %d", next)
+	}
+}
+
+func TestNonceManager_InvalidateResyncsAheadOfChain(t *testing.T) {
+	reader := &mockNonceReader{pending: 1}
+	nm := NewNonceManager(reader)
+	ctx := context.Background()
+
+	n, _ := nm.Next(ctx, testNonceAddr)
+	nm.Commit(testNonceAddr, n)
+
+	// Another process sent transactions for the same address
+	reader.setPending(5)
+	nm.Invalidate(testNonceAddr)
+
+	got, err := nm.Next(ctx, testNonceAddr)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if got != 5 {
+		t.Errorf("expected nonce 5 after resync, got %d", got)
+	}
+}
+
+func TestNonceManager_DetectsGap(t *testing.T) {
+	reader := &mockNonceReader{pending: 0}
+	nm := NewNonceManager(reader)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		n, _ := nm.Next(ctx, testNonceAddr)
+		nm.Commit(testNonceAddr, n)
+	}
+
+	// Nonce 1 and 2 were dropped by the node: only nonce 0 made it into the pool
+	reader.setPending(1)
+	if err := nm.Sync(ctx, testNonceAddr); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	if next, _ := nm.Peek(testNonceAddr); next != 1 {
+		t.Errorf("expected next nonce to rewind to 1, got %d", next)
+	}
+}
+
+func TestNonceManager_SyncError(t *testing.T) {
+	nm := NewNonceManager(&mockNonceReader{err: errors.New("rpc down")})
+
+	if _, err := nm.Next(context.Background(), testNonceAddr); err == nil {
+		t.Fatal("expected error when pending nonce cannot be read")
+	}
+}
+
+func TestNonceManager_ConcurrentAllocationsAreUnique(t *testing.T) {
+	nm := NewNonceManager(&mockNonceReader{pending: 100})
+	ctx := context.Background()
+
+	const n = 50
+	var wg sync.WaitGroup
+	results := make(chan uint64, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := nm.Next(ctx, testNonceAddr)
+			if err != nil {
+				t.Errorf("Next: %v", err)
+				return
+			}
+			nm.Commit(testNonceAddr, nonce)
+			results <- nonce
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[uint64]bool)
+	for nonce := range results {
+		if seen[nonce] {
+			t.Errorf("nonce %d allocated twice", nonce)
+		}
+		seen[nonce] = true
+	}
+	for i := uint64(100); i < 100+n; i++ {
+		if !seen[i] {
+			t.Errorf("nonce %d never allocated", i)
+		}
+	}
+}
+
+func TestDefaultNonceManager_KeyedByChainAndClient(t *testing.T) {
+	chainId := big.NewInt(31337)
+	a, b := &mockNonceReader{pending: 3}, &mockNonceReader{pending: 9}
+
+	if getDefaultNonceManager(chainId, a) != getDefaultNonceManager(big.NewInt(31337), a) {
+		t.Error("senders on the same chain and client must share a NonceManager")
+	}
+	nm := getDefaultNonceManager(chainId, b)
+	if nm == getDefaultNonceManager(chainId, a) {
+		t.Fatal("senders on different clients must not share a NonceManager")
+	}
+	if n, err := nm.Next(context.Background(), testNonceAddr); err != nil || n != 9 {
+		t.Errorf("expected nonce 9 synced through the second client, got %d (%v)", n, err)
+	}
+	if a.calls != 0 {
+		t.Errorf("the first client must not be used by the second client's manager, got %d calls", a.calls)
+	}
+}
@@ -0,0 +1,69 @@
+package signer
+
+import (
+	"context"
+
+	"github.com/ivanzzeth/ethsig"
+	"github.com/ivanzzeth/ethsig/eip712"
+)
+
+// PreSignHook is called with typed data before it is signed. Returning an error aborts signing,
+// e.g. when an operator reviewing a description of the data declines it.
+type PreSignHook func(ctx context.Context, typedData eip712.TypedData) error
+
+// HookedTypedDataSigner calls a PreSignHook before every signature of a TypedDataSigner.
+// Pass it to NewSimpleSafeTradingSigner to review Safe transactions before they are signed.
+type HookedTypedDataSigner struct {
+	ethsig.TypedDataSigner
+	hook PreSignHook
+}
+
+var _ ContextTypedDataSigner = (*HookedTypedDataSigner)(nil)
+
+// NewHookedTypedDataSigner wraps s so hook is called before it signs
+func NewHookedTypedDataSigner(s ethsig.TypedDataSigner, hook PreSignHook) *HookedTypedDataSigner {
+	return &HookedTypedDataSigner{TypedDataSigner: s, hook: hook}
+}
+
+// SignTypedData calls the hook and signs typedData
+func (s *HookedTypedDataSigner) SignTypedData(typedData eip712.TypedData) ([]byte, error) {
+	return s.SignTypedDataWithContext(context.Background(), typedData)
+}
+
+// SignTypedDataWithContext calls the hook and signs typedData, honoring ctx
+func (s *HookedTypedDataSigner) SignTypedDataWithContext(ctx context.Context, typedData eip712.TypedData) ([]byte, error) {
+	return signHooked(ctx, s.TypedDataSigner, s.hook, typedData)
+}
+
+// HookedEOASigner calls a PreSignHook before every typed data signature of an EOATradingSigner.
+// Transactions are signed without calling the hook.
+type HookedEOASigner struct {
+	EOATradingSigner
+	hook PreSignHook
+}
+
+var _ ContextTypedDataSigner = (*HookedEOASigner)(nil)
+
+// NewHookedEOASigner wraps s so hook is called before it signs typed data
+func NewHookedEOASigner(s EOATradingSigner, hook PreSignHook) *HookedEOASigner {
+	return &HookedEOASigner{EOATradingSigner: s, hook: hook}
+}
+
+// SignTypedData calls the hook and signs typedData
+func (s *HookedEOASigner) SignTypedData(typedData eip712.TypedData) ([]byte, error) {
+	return s.SignTypedDataWithContext(context.Background(), typedData)
+}
+
+// SignTypedDataWithContext calls the hook and signs typedData, honoring ctx
+func (s *HookedEOASigner) SignTypedDataWithContext(ctx context.Context, typedData eip712.TypedData) ([]byte, error) {
+	return signHooked(ctx, s.EOATradingSigner, s.hook, typedData)
+}
+
+func signHooked(ctx context.Context, s ethsig.TypedDataSigner, hook PreSignHook, typedData eip712.TypedData) ([]byte, error) {
+	if hook != nil {
+		if err := hook(ctx, typedData); err != nil {
+			return nil, err
+		}
+	}
+	return SignTypedDataWithContext(ctx, s, typedData)
+}
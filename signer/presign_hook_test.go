@@ -0,0 +1,44 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ivanzzeth/ethsig/eip712"
+)
+
+func TestHookedSigners(t *testing.T) {
+	kms := newTestKMSSigner(t, &softwareKMS{})
+	declined := errors.New("declined")
+	var calls int
+	decline := false
+	hook := func(_ context.Context, typedData eip712.TypedData) error {
+		calls++
+		if typedData.PrimaryType != testTypedData.PrimaryType {
+			t.Errorf("hook got %s", typedData.PrimaryType)
+		}
+		if decline {
+			return declined
+		}
+		return nil
+	}
+
+	eoa := NewHookedEOASigner(kms, hook)
+	for _, s := range []ContextTypedDataSigner{NewHookedTypedDataSigner(kms, hook), eoa} {
+		decline = false
+		if _, err := s.SignTypedDataWithContext(context.Background(), testTypedData); err != nil {
+			t.Fatalf("SignTypedDataWithContext: %v", err)
+		}
+		decline = true
+		if _, err := s.SignTypedDataWithContext(context.Background(), testTypedData); !errors.Is(err, declined) {
+			t.Fatalf("got %v, want the hook's error", err)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("hook called %d times, want 4", calls)
+	}
+	if eoa.GetAddress() != kms.GetAddress() {
+		t.Errorf("address %s, want %s", eoa.GetAddress().Hex(), kms.GetAddress().Hex())
+	}
+}
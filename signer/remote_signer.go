@@ -0,0 +1,288 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/ivanzzeth/ethsig/eip712"
+)
+
+// ErrRemoteSignerMismatch is returned when a remote signer returns a signature that is not by the expected
+// account or not over the requested data
+var ErrRemoteSignerMismatch = errors.New("remote signer returned an unexpected signature")
+
+// RemoteSigner signs through a remote signer speaking JSON-RPC, such as Clef or Web3Signer.
+// It can back both an EOATradingSigner and a SafeTradingSigner. Every signature is checked against
+// the signer's address before it is returned.
+type RemoteSigner struct {
+	client          *rpc.Client
+	address         common.Address
+	timeout         time.Duration
+	typedDataMethod string
+}
+
+var (
+	_ EOATradingSigner       = (*RemoteSigner)(nil)
+	_ ContextTypedDataSigner = (*RemoteSigner)(nil)
+)
+
+type remoteSignerConfig struct {
+	headers         http.Header
+	tlsConfig       *tls.Config
+	httpClient      *http.Client
+	timeout         time.Duration
+	typedDataMethod string
+}
+
+// RemoteSignerOption configures NewRemoteSigner
+type RemoteSignerOption func(c *remoteSignerConfig)
+
+// WithRemoteSignerHeader adds an HTTP header to every request, e.g. an Authorization header
+func WithRemoteSignerHeader(key, value string) RemoteSignerOption {
+	return func(c *remoteSignerConfig) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithRemoteSignerTLS sets the TLS configuration of HTTPS connections, e.g. a private CA or a client certificate
+func WithRemoteSignerTLS(tlsConfig *tls.Config) RemoteSignerOption {
+	return func(c *remoteSignerConfig) {
+		c.tlsConfig = tlsConfig
+	}
+}
+
+// WithRemoteSignerHTTPClient sets the HTTP client. It takes precedence over WithRemoteSignerTLS.
+func WithRemoteSignerHTTPClient(client *http.Client) RemoteSignerOption {
+	return func(c *remoteSignerConfig) {
+		c.httpClient = client
+	}
+}
+
+// WithRemoteSignerTimeout bounds every request (default: 30 seconds). Clef waits for a human to
+// approve requests, so give it more time.
+func WithRemoteSignerTimeout(timeout time.Duration) RemoteSignerOption {
+	return func(c *remoteSignerConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithRemoteSignerTypedDataMethod sets the method signing EIP-712 typed data (default: eth_signTypedData_v4).
+// Web3Signer uses eth_signTypedData, Clef's external API account_signTypedData.
+func WithRemoteSignerTypedDataMethod(method string) RemoteSignerOption {
+	return func(c *remoteSignerConfig) {
+		c.typedDataMethod = method
+	}
+}
+
+// NewRemoteSigner connects to the remote signer at url, signing as address.
+// If address is zero, the first account returned by eth_accounts is used.
+func NewRemoteSigner(ctx context.Context, url string, address common.Address, opts ...RemoteSignerOption) (*RemoteSigner, error) {
+	c := remoteSignerConfig{
+		headers:         make(http.Header),
+		timeout:         30 * time.Second,
+		typedDataMethod: "eth_signTypedData_v4",
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+		if c.tlsConfig != nil {
+			httpClient.Transport = &http.Transport{TLSClientConfig: c.tlsConfig}
+		}
+	}
+	client, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(httpClient), rpc.WithHeaders(c.headers))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial remote signer: %w", err)
+	}
+
+	s := &RemoteSigner{client: client, address: address, timeout: c.timeout, typedDataMethod: c.typedDataMethod}
+	if address == (common.Address{}) {
+		var accounts []common.Address
+		if err := s.call(ctx, &accounts, "eth_accounts"); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to list remote signer accounts: %w", err)
+		}
+		if len(accounts) == 0 {
+			client.Close()
+			return nil, errors.New("remote signer has no accounts")
+		}
+		s.address = accounts[0]
+	}
+	return s, nil
+}
+
+// Close closes the connection to the remote signer
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+// GetAddress returns the address signing
+func (s *RemoteSigner) GetAddress() common.Address {
+	return s.address
+}
+
+// SignTypedData signs EIP-712 typed data
+func (s *RemoteSigner) SignTypedData(typedData eip712.TypedData) ([]byte, error) {
+	return s.SignTypedDataWithContext(context.Background(), typedData)
+}
+
+// SignTypedDataWithContext signs EIP-712 typed data, honoring ctx
+func (s *RemoteSigner) SignTypedDataWithContext(ctx context.Context, typedData eip712.TypedData) ([]byte, error) {
+	hash, _, err := eip712.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	// The domain is sent as a map so unset fields are omitted rather than sent empty
+	payload := map[string]any{
+		"types":       typedData.Types,
+		"primaryType": typedData.PrimaryType,
+		"domain":      typedData.Domain.Map(),
+		"message":     typedData.Message,
+	}
+	var signature hexutil.Bytes
+	if err := s.call(ctx, &signature, s.typedDataMethod, s.address, payload); err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("invalid signature length: expected 65, got %d", len(signature))
+	}
+
+	sig := common.CopyBytes(signature)
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	recoverable := common.CopyBytes(sig)
+	recoverable[64] -= 27
+	pub, err := crypto.SigToPub(hash, recoverable)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRemoteSignerMismatch, err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != s.address {
+		return nil, fmt.Errorf("%w: signed by %s instead of %s", ErrRemoteSignerMismatch, signer.Hex(), s.address.Hex())
+	}
+	return sig, nil
+}
+
+// SignTransactionWithChainID signs tx through eth_signTransaction
+func (s *RemoteSigner) SignTransactionWithChainID(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.SignTransactionWithContext(context.Background(), tx, chainID)
+}
+
+// SignTransactionWithContext is SignTransactionWithChainID honoring ctx
+func (s *RemoteSigner) SignTransactionWithContext(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args, err := remoteTxArgs(s.address, tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result json.RawMessage
+	if err := s.call(ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	raw, err := decodeSignTxResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+
+	txSigner := types.LatestSignerForChainID(chainID)
+	if txSigner.Hash(signed) != txSigner.Hash(tx) {
+		return nil, fmt.Errorf("%w: signed transaction differs from the requested one", ErrRemoteSignerMismatch)
+	}
+	from, err := types.Sender(txSigner, signed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRemoteSignerMismatch, err)
+	}
+	if from != s.address {
+		return nil, fmt.Errorf("%w: signed by %s instead of %s", ErrRemoteSignerMismatch, from.Hex(), s.address.Hex())
+	}
+	return signed, nil
+}
+
+func (s *RemoteSigner) call(ctx context.Context, result any, method string, args ...any) error {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	return s.client.CallContext(ctx, result, method, args...)
+}
+
+// remoteTxArgs converts tx to eth_signTransaction arguments
+func remoteTxArgs(from common.Address, tx *types.Transaction, chainID *big.Int) (*apitypes.SendTxArgs, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := &apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(from),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if to := tx.To(); to != nil {
+		mixed := common.NewMixedcaseAddress(*to)
+		args.To = &mixed
+	}
+
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.AccessListTxType:
+		accessList := tx.AccessList()
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+		args.AccessList = &accessList
+	case types.DynamicFeeTxType:
+		accessList := tx.AccessList()
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		args.AccessList = &accessList
+	default:
+		return nil, fmt.Errorf("remote signer does not support transaction type %d", tx.Type())
+	}
+	return args, nil
+}
+
+// decodeSignTxResult returns the raw signed transaction of an eth_signTransaction result:
+// either the raw transaction itself (Web3Signer) or an object carrying it (Clef, geth)
+func decodeSignTxResult(result json.RawMessage) ([]byte, error) {
+	result = bytes.TrimSpace(result)
+	if len(result) > 0 && result[0] == '"' {
+		var raw hexutil.Bytes
+		if err := json.Unmarshal(result, &raw); err != nil {
+			return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+		}
+		return raw, nil
+	}
+
+	var obj struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &obj); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+	if len(obj.Raw) == 0 {
+		return nil, errors.New("remote signer returned no signed transaction")
+	}
+	return obj.Raw, nil
+}
@@ -0,0 +1,144 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ivanzzeth/ethsig/eip712"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer/remotesignertest"
+)
+
+func newTestRemoteSigner(t *testing.T, serverOpts []remotesignertest.Option, opts ...RemoteSignerOption) (*RemoteSigner, *remotesignertest.Server) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	server := remotesignertest.NewServer(key, serverOpts...)
+	t.Cleanup(server.Close)
+
+	s, err := NewRemoteSigner(context.Background(), server.URL, server.Address, opts...)
+	if err != nil {
+		t.Fatalf("NewRemoteSigner: %v", err)
+	}
+	t.Cleanup(s.Close)
+	return s, server
+}
+
+var testTypedData = eip712.TypedData{
+	Types: eip712.Types{
+		"EIP712Domain": {{Name: "name", Type: "string"}, {Name: "chainId", Type: "uint256"}},
+		"Mail":         {{Name: "to", Type: "address"}, {Name: "amount", Type: "uint256"}},
+	},
+	PrimaryType: "Mail",
+	Domain:      eip712.TypedDataDomain{Name: "Test", ChainId: "137"},
+	Message: eip712.TypedDataMessage{
+		"to":     "0x2222222222222222222222222222222222222222",
+		"amount": "1000",
+	},
+}
+
+func TestRemoteSigner_SignTypedDataOverTLSWithAuth(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	server := remotesignertest.NewServer(key, remotesignertest.WithTLS(), remotesignertest.WithRequiredHeader("Authorization", "Bearer secret"))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	tlsConfig := &tls.Config{RootCAs: pool}
+
+	if _, err := NewRemoteSigner(context.Background(), server.URL, common.Address{}, WithRemoteSignerTLS(tlsConfig)); err == nil {
+		t.Fatal("expected unauthorized request to fail")
+	}
+
+	s, err := NewRemoteSigner(context.Background(), server.URL, common.Address{}, WithRemoteSignerTLS(tlsConfig), WithRemoteSignerHeader("Authorization", "Bearer secret"))
+	if err != nil {
+		t.Fatalf("NewRemoteSigner: %v", err)
+	}
+	defer s.Close()
+	if s.GetAddress() != server.Address {
+		t.Fatalf("address %s, want %s from eth_accounts", s.GetAddress().Hex(), server.Address.Hex())
+	}
+
+	signature, err := s.SignTypedData(testTypedData)
+	if err != nil {
+		t.Fatalf("SignTypedData: %v", err)
+	}
+	hash, _, err := eip712.TypedDataAndHash(testTypedData)
+	if err != nil {
+		t.Fatalf("TypedDataAndHash: %v", err)
+	}
+	sig := common.CopyBytes(signature)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != server.Address {
+		t.Fatalf("signature does not recover to the signer: %v", err)
+	}
+}
+
+func TestRemoteSigner_SignTransaction(t *testing.T) {
+	for _, clef := range []bool{false, true} {
+		var serverOpts []remotesignertest.Option
+		if clef {
+			serverOpts = append(serverOpts, remotesignertest.WithClefResponses())
+		}
+		s, server := newTestRemoteSigner(t, serverOpts)
+
+		chainID := big.NewInt(137)
+		txs := []*types.Transaction{
+			types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(30e9), Gas: 21000, To: &testTxTo, Value: big.NewInt(1)}),
+			types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 2, GasTipCap: big.NewInt(30e9), GasFeeCap: big.NewInt(100e9), Gas: 50000, To: &testTxTo, Data: []byte{0x01, 0x02}}),
+		}
+		for _, tx := range txs {
+			signed, err := s.SignTransactionWithChainID(tx, chainID)
+			if err != nil {
+				t.Fatalf("clef=%v type %d: SignTransactionWithChainID: %v", clef, tx.Type(), err)
+			}
+			from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+			if err != nil || from != server.Address || signed.Type() != tx.Type() || signed.Nonce() != tx.Nonce() {
+				t.Errorf("clef=%v type %d: unexpected signed transaction from %s: %v", clef, tx.Type(), from.Hex(), err)
+			}
+		}
+	}
+}
+
+func TestRemoteSigner_BacksTransactionSender(t *testing.T) {
+	s, server := newTestRemoteSigner(t, nil)
+	client := newMockTxClient()
+	txSender := NewTransactionSenderByTransactionSigner(big.NewInt(137), client, s, WithNonceManager(NewNonceManager(client)))
+
+	hash, err := txSender.SendEthereumTransactionWithContext(context.Background(), testTxTo, []byte{0x01}, big.NewInt(0), sender.WithFeeCaps(big.NewInt(300), big.NewInt(30)))
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	sent := client.lastSent()
+	if sent.Hash() != hash {
+		t.Fatalf("sent %s, returned %s", sent.Hash().Hex(), hash.Hex())
+	}
+	if from, _ := types.Sender(types.LatestSignerForChainID(big.NewInt(137)), sent); from != server.Address {
+		t.Errorf("sent from %s, want %s", from.Hex(), server.Address.Hex())
+	}
+}
+
+func TestRemoteSigner_Timeout(t *testing.T) {
+	s, _ := newTestRemoteSigner(t, []remotesignertest.Option{remotesignertest.WithDelay(200 * time.Millisecond)}, WithRemoteSignerTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	if _, err := s.SignTypedData(testTypedData); err == nil {
+		t.Fatal("expected timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("timed out after %v", elapsed)
+	}
+}
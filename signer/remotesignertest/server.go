@@ -0,0 +1,163 @@
+// Package remotesignertest provides an in-process remote signer for testing code using signer.RemoteSigner.
+package remotesignertest
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Server is a remote signer holding a single key. It speaks eth_accounts, eth_signTypedData_v4,
+// eth_signTypedData and eth_signTransaction over HTTP, like Web3Signer and Clef.
+type Server struct {
+	*httptest.Server
+
+	Address common.Address
+}
+
+type config struct {
+	tls         bool
+	header      string
+	headerValue string
+	delay       time.Duration
+	clef        bool
+}
+
+// Option configures NewServer
+type Option func(c *config)
+
+// WithTLS serves HTTPS with a self-signed certificate, see httptest.Server.Client and Certificate
+func WithTLS() Option {
+	return func(c *config) {
+		c.tls = true
+	}
+}
+
+// WithRequiredHeader rejects requests without the header key set to value with 401 Unauthorized
+func WithRequiredHeader(key, value string) Option {
+	return func(c *config) {
+		c.header = key
+		c.headerValue = value
+	}
+}
+
+// WithDelay delays every response, e.g. to test timeouts
+func WithDelay(delay time.Duration) Option {
+	return func(c *config) {
+		c.delay = delay
+	}
+}
+
+// WithClefResponses answers eth_signTransaction with an object carrying the raw transaction, like Clef,
+// instead of the raw transaction itself
+func WithClefResponses() Option {
+	return func(c *config) {
+		c.clef = true
+	}
+}
+
+// NewServer starts a remote signer signing with key. Close it when done.
+func NewServer(key *ecdsa.PrivateKey, opts ...Option) *Server {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", &service{key: key, address: address, clef: c.clef}); err != nil {
+		panic(err)
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.header != "" && r.Header.Get(c.header) != c.headerValue {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if c.delay > 0 {
+			select {
+			case <-time.After(c.delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		rpcServer.ServeHTTP(w, r)
+	})
+
+	s := &Server{Address: address}
+	if c.tls {
+		s.Server = httptest.NewTLSServer(handler)
+	} else {
+		s.Server = httptest.NewServer(handler)
+	}
+	return s
+}
+
+type service struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+	clef    bool
+}
+
+func (s *service) Accounts() []common.Address {
+	return []common.Address{s.address}
+}
+
+func (s *service) SignTypedData_v4(address common.Address, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	if address != s.address {
+		return nil, errors.New("unknown account")
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+func (s *service) SignTypedData(address common.Address, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	return s.SignTypedData_v4(address, typedData)
+}
+
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func (s *service) SignTransaction(args apitypes.SendTxArgs) (any, error) {
+	if args.From.Address() != s.address {
+		return nil, errors.New("unknown account")
+	}
+	if args.ChainID == nil {
+		return nil, errors.New("chain id is required")
+	}
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), s.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if s.clef {
+		return signTransactionResult{Raw: raw, Tx: signed}, nil
+	}
+	return hexutil.Bytes(raw), nil
+}
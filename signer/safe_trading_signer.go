@@ -1,6 +1,7 @@
 package signer
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"math/big"
 
@@ -18,6 +19,20 @@ type SafeTradingSigner interface {
 	sender.TransactionSender
 }
 
+// ContextTypedDataSigner is implemented by typed data signers that may wait a long time, such as remote signers
+// waiting for approvers
+type ContextTypedDataSigner interface {
+	SignTypedDataWithContext(ctx context.Context, typedData eip712.TypedData) ([]byte, error)
+}
+
+// SignTypedDataWithContext signs typedData with s, honoring ctx if s implements ContextTypedDataSigner
+func SignTypedDataWithContext(ctx context.Context, s ethsig.TypedDataSigner, typedData eip712.TypedData) ([]byte, error) {
+	if cs, ok := s.(ContextTypedDataSigner); ok {
+		return cs.SignTypedDataWithContext(ctx, typedData)
+	}
+	return s.SignTypedData(typedData)
+}
+
 // SimpleSafeTradingSigner is a generic implementation of SafeTradingSigner
 type SimpleSafeTradingSigner struct {
 	addr            common.Address
@@ -44,11 +59,26 @@ func (s *SimpleSafeTradingSigner) SignTypedData(typedData eip712.TypedData) ([]b
 	return s.typedDataSigner.SignTypedData(typedData)
 }
 
+// SignTypedDataWithContext signs EIP-712 typed data, honoring ctx if the typed data signer supports it
+func (s *SimpleSafeTradingSigner) SignTypedDataWithContext(ctx context.Context, typedData eip712.TypedData) ([]byte, error) {
+	return SignTypedDataWithContext(ctx, s.typedDataSigner, typedData)
+}
+
 // SendEthereumTransaction sends an Ethereum transaction
 func (s *SimpleSafeTradingSigner) SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error) {
 	return s.txSender.SendEthereumTransaction(to, data, value)
 }
 
+// SendEthereumTransactionWithContext sends an Ethereum transaction, honoring ctx and per-call options
+func (s *SimpleSafeTradingSigner) SendEthereumTransactionWithContext(ctx context.Context, to common.Address, data []byte, value *big.Int, opts ...sender.SendOption) (common.Hash, error) {
+	return sender.AsContextTransactionSender(s.txSender).SendEthereumTransactionWithContext(ctx, to, data, value, opts...)
+}
+
+// TransactionSender returns the underlying transaction sender
+func (s *SimpleSafeTradingSigner) TransactionSender() sender.TransactionSender {
+	return s.txSender
+}
+
 // SafeTradingSingleMpcSigner is a SafeTradingSigner implementation using Cobo MPC
 type SafeTradingSingleMpcSigner struct {
 	*SimpleSafeTradingSigner
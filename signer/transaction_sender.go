@@ -5,21 +5,27 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/CoboGlobal/cobo-waas2-go-sdk/cobo_waas2"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	ethclient "github.com/ivanzzeth/ethclient"
 	"github.com/ivanzzeth/ethsig"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/journal"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/retry"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/revert"
 	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
 )
 
-// GetTransactionSenderBySigner creates a TransactionSender based on the signer type
+// GetTransactionSenderBySigner creates a TransactionSender based on the signer type.
+// Custody providers, including any implementing CustodyProvider outside this package, get a CustodyTransactionSender
+// with the default options: sends wait for approvers, bounded by their ctx, and a call given up on before it is
+// broadcast is cancelled if the provider implements CustodyCanceller. Use NewCustodyTransactionSender for other options.
 func GetTransactionSenderBySigner(chainId *big.Int, client ethclient.EthClientInterface, signerInstance any) (sender.TransactionSender, error) {
 	switch s := signerInstance.(type) {
-	case *CoboMpcSigner:
-		return GetTransactionSenderByCoboMpcTransactionSender(client, s)
+	case CustodyProvider:
+		return NewCustodyTransactionSender(client, s), nil
 	case TransactionSignerAndAddrGetter:
 		return GetTransactionSenderByTransactionSignerAndAddrGetter(chainId, client, s)
 	default:
@@ -35,111 +41,236 @@ type TransactionSignerAndAddrGetter interface {
 
 // TransactionSenderByTransactionSigner implements TransactionSender using a transaction signer
 type TransactionSenderByTransactionSigner struct {
-	chainId  *big.Int
-	client   ethclient.EthClientInterface
-	txSigner TransactionSignerAndAddrGetter
+	chainId   *big.Int
+	client    ethclient.EthClientInterface
+	txSigner  TransactionSignerAndAddrGetter
+	nonces    *NonceManager
+	gasPricer sender.GasPricer
+	retry     retry.Policy
+}
+
+// TransactionSenderOption configures optional fields on TransactionSenderByTransactionSigner
+type TransactionSenderOption func(s *TransactionSenderByTransactionSigner)
+
+// WithNonceManager sets the NonceManager used to allocate nonces.
+// By default, all senders on the same chain and client share one NonceManager.
+func WithNonceManager(nm *NonceManager) TransactionSenderOption {
+	return func(s *TransactionSenderByTransactionSigner) {
+		s.nonces = nm
+	}
+}
+
+// WithGasPricer sets the GasPricer choosing fees when none are passed per call.
+// By default the suggested gas price is multiplied by 1x, 1.3x or 2x depending on the urgency.
+func WithGasPricer(pricer sender.GasPricer) TransactionSenderOption {
+	return func(s *TransactionSenderByTransactionSigner) {
+		s.gasPricer = pricer
+	}
+}
+
+// WithRetryPolicy sets how transient RPC errors of gas pricing, gas estimation, nonce lookup and broadcast
+// are retried (default: retry.DefaultPolicy). Reverts are never retried.
+func WithRetryPolicy(policy retry.Policy) TransactionSenderOption {
+	return func(s *TransactionSenderByTransactionSigner) {
+		s.retry = policy
+	}
 }
 
 // GetTransactionSenderByTransactionSignerAndAddrGetter creates a TransactionSender from a transaction signer
-func GetTransactionSenderByTransactionSignerAndAddrGetter(chainId *big.Int, client ethclient.EthClientInterface, txSigner TransactionSignerAndAddrGetter) (sender.TransactionSender, error) {
-	return &TransactionSenderByTransactionSigner{chainId: chainId, client: client, txSigner: txSigner}, nil
+func GetTransactionSenderByTransactionSignerAndAddrGetter(chainId *big.Int, client ethclient.EthClientInterface, txSigner TransactionSignerAndAddrGetter, opts ...TransactionSenderOption) (sender.TransactionSender, error) {
+	return NewTransactionSenderByTransactionSigner(chainId, client, txSigner, opts...), nil
+}
+
+// NewTransactionSenderByTransactionSigner creates a TransactionSenderByTransactionSigner
+func NewTransactionSenderByTransactionSigner(chainId *big.Int, client ethclient.EthClientInterface, txSigner TransactionSignerAndAddrGetter, opts ...TransactionSenderOption) *TransactionSenderByTransactionSigner {
+	s := &TransactionSenderByTransactionSigner{chainId: chainId, client: client, txSigner: txSigner, retry: retry.DefaultPolicy}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.nonces == nil {
+		s.nonces = getDefaultNonceManager(chainId, client)
+	}
+	if s.gasPricer == nil {
+		s.gasPricer = sender.NewDefaultGasPricer(client)
+	}
+	return s
+}
+
+// GetAddress returns the address of the transaction signer
+func (s *TransactionSenderByTransactionSigner) GetAddress() common.Address {
+	return s.txSigner.GetAddress()
+}
+
+// NonceManager returns the NonceManager used by this sender
+func (s *TransactionSenderByTransactionSigner) NonceManager() *NonceManager {
+	return s.nonces
 }
 
 // SendEthereumTransaction sends an Ethereum transaction using the transaction signer
 func (s *TransactionSenderByTransactionSigner) SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error) {
-	ctx := context.Background()
+	return s.SendEthereumTransactionWithContext(context.Background(), to, data, value)
+}
 
-	// Get confirmed nonce (not pending) to avoid stuck nonce gaps
-	nonce, err := s.client.NonceAt(ctx, s.txSigner.GetAddress(), nil)
+// SendEthereumTransactionWithContext sends an Ethereum transaction using the transaction signer, honoring ctx and per-call options
+func (s *TransactionSenderByTransactionSigner) SendEthereumTransactionWithContext(ctx context.Context, to common.Address, data []byte, value *big.Int, opts ...sender.SendOption) (common.Hash, error) {
+	signedTx, err := s.sendTransaction(ctx, to, data, value, sender.ApplySendOptions(opts...))
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to get nonce: %w", err)
+		return common.Hash{}, err
 	}
+	return signedTx.Hash(), nil
+}
 
-	// Get gas price and increase by 30% to improve transaction inclusion speed
-	gasPrice, err := s.client.SuggestGasPrice(ctx)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to get gas price: %w", err)
+// sendTransaction builds, signs and broadcasts a transaction, returning the signed transaction
+func (s *TransactionSenderByTransactionSigner) sendTransaction(ctx context.Context, to common.Address, data []byte, value *big.Int, o *sender.SendOptions) (*types.Transaction, error) {
+	if err := o.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid send options: %w", err)
 	}
-	gasPrice.Mul(gasPrice, big.NewInt(13))
-	gasPrice.Div(gasPrice, big.NewInt(10))
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	from := s.txSigner.GetAddress()
 
-	// Estimate gas limit
-	msg := ethereum.CallMsg{
-		From:     s.txSigner.GetAddress(),
-		To:       &to,
-		Value:    value,
-		Data:     data,
-		GasPrice: gasPrice,
+	gasPrice, gasFeeCap, gasTipCap := o.GasPrice, o.GasFeeCap, o.GasTipCap
+	if gasPrice == nil && gasFeeCap == nil {
+		fees, err := retry.DoValue(ctx, s.retry, func() (*sender.GasFees, error) {
+			return s.gasPricer.GasFees(ctx, o.Urgency)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to price gas: %w", err)
+		}
+		gasPrice, gasFeeCap, gasTipCap = fees.GasPrice, fees.GasFeeCap, fees.GasTipCap
 	}
-	gasLimit, err := s.client.EstimateGas(ctx, msg)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to estimate gas: %w", err)
+	dynamicFee := gasFeeCap != nil
+
+	// Estimate gas limit. With an explicit gas limit the estimate still runs as a revert check unless disabled.
+	gasLimit := o.GasLimit
+	if !o.NoEstimate {
+		msg := ethereum.CallMsg{
+			From:      from,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+			GasPrice:  gasPrice,
+			GasFeeCap: gasFeeCap,
+			GasTipCap: gasTipCap,
+		}
+		estimated, err := retry.DoValue(ctx, s.retry, func() (uint64, error) {
+			return s.client.EstimateGas(ctx, msg)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas: %w", revert.Wrap(&to, err))
+		}
+		if gasLimit == 0 {
+			gasLimit = estimated
+		}
+	}
+
+	// Allocate the nonce locally (pending, not confirmed) so back-to-back sends get consecutive nonces.
+	// An explicit nonce bypasses the NonceManager.
+	var nonce uint64
+	managed := o.Nonce == nil
+	if managed {
+		var err error
+		nonce, err = retry.DoValue(ctx, s.retry, func() (uint64, error) {
+			return s.nonces.Next(ctx, from)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nonce: %w", err)
+		}
+	} else {
+		nonce = *o.Nonce
 	}
 
 	// Create and sign the transaction
-	tx := types.NewTransaction(nonce, to, value, gasLimit, gasPrice, data)
+	var tx *types.Transaction
+	if dynamicFee {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   s.chainId,
+			Nonce:     nonce,
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       gasLimit,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		})
+	} else {
+		tx = types.NewTransaction(nonce, to, value, gasLimit, gasPrice, data)
+	}
 
 	// Sign the transaction
 	signedTx, err := s.txSigner.SignTransactionWithChainID(tx, s.chainId)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to sign transaction: %w", err)
+		if managed {
+			s.nonces.Release(from, nonce)
+		}
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	// Send the signed transaction
-	err = s.client.SendTransaction(ctx, signedTx)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to send transaction: %w", err)
+	// Write-ahead: the signed transaction is journaled before it is broadcast, so it can be rebroadcast after a crash
+	if err := journalSigned(ctx, signedTx); err != nil {
+		if managed {
+			s.nonces.Release(from, nonce)
+		}
+		return nil, err
 	}
 
-	return signedTx.Hash(), nil
-}
-
-// CoboMpcTransactionSender implements TransactionSender using Cobo MPC wallet
-type CoboMpcTransactionSender struct {
-	client bind.ContractBackend
-	signer *CoboMpcSigner
-}
+	// Send the signed transaction. Resending the same signed transaction is safe, and a node that
+	// already has it counts as a successful send.
+	err = retry.Broadcast(ctx, s.retry, s.client, signedTx)
+	if err != nil {
+		if managed {
+			s.nonces.Release(from, nonce)
+		}
+		s.nonces.Invalidate(from)
+		_ = journal.Record(ctx, func(e *journal.Entry) { e.Fail(err) })
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	if managed {
+		s.nonces.Commit(from, nonce)
+	}
+	// The transaction is out: a failure to journal it must not be reported as a send failure.
+	// The entry stays signed and journal.Recover reconciles it.
+	_ = journal.Record(ctx, func(e *journal.Entry) { e.State = journal.StateSent })
 
-// GetTransactionSenderByCoboMpcTransactionSender creates a TransactionSender for Cobo MPC
-func GetTransactionSenderByCoboMpcTransactionSender(client bind.ContractBackend, mpcSigner *CoboMpcSigner) (sender.TransactionSender, error) {
-	return &CoboMpcTransactionSender{client: client, signer: mpcSigner}, nil
+	return signedTx, nil
 }
 
-// SendEthereumTransaction sends an Ethereum transaction using Cobo MPC wallet
-func (s *CoboMpcTransactionSender) SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error) {
-	// Get gas price and increase by 30% to improve transaction inclusion speed
-	gasPrice, err := s.client.SuggestGasPrice(context.Background())
+// journalSigned records a signed transaction on the journal entry carried by ctx, if any
+func journalSigned(ctx context.Context, signedTx *types.Transaction) error {
+	raw, err := signedTx.MarshalBinary()
 	if err != nil {
-		return common.Hash{}, err
+		return fmt.Errorf("failed to encode signed transaction: %w", err)
 	}
-	gasPrice.Mul(gasPrice, big.NewInt(13))
-	gasPrice.Div(gasPrice, big.NewInt(10))
-
-	gasLimit, err := s.client.EstimateGas(context.Background(), ethereum.CallMsg{
-		From:  s.signer.GetAddress(),
-		To:    &to,
-		Data:  data,
-		Value: value,
+	nonce := hexutil.Uint64(signedTx.Nonce())
+	return journal.Record(ctx, func(e *journal.Entry) {
+		e.State = journal.StateSigned
+		e.RawTx = raw
+		e.TxHash = signedTx.Hash()
+		e.Nonce = &nonce
 	})
-	if err != nil {
-		return common.Hash{}, err
-	}
+}
 
-	fee := cobo_waas2.NewTransactionRequestEvmLegacyFee(gasPrice.String(), cobo_waas2.FEETYPE_EVM_LEGACY, s.signer.CoboChainId())
-	fee.SetGasLimit(big.NewInt(int64(gasLimit)).String())
+// CoboMpcTransactionSender implements TransactionSender using Cobo MPC wallet.
+// Cobo assigns nonces; stuck transactions are replaced through Cobo's speed-up and drop endpoints.
+type CoboMpcTransactionSender = CustodyTransactionSender
 
-	paramFee := cobo_waas2.TransactionRequestEvmLegacyFeeAsTransactionRequestFee(fee)
+// CoboMpcTransactionSenderOption configures optional fields on CoboMpcTransactionSender
+type CoboMpcTransactionSenderOption = CustodyTransactionSenderOption
 
-	txResp, err := s.signer.CallContract(to.Hex(), fmt.Sprintf("0x%x", data), value.String(), &paramFee)
-	if err != nil {
-		return common.Hash{}, err
-	}
+// WithCoboGasPricer sets the GasPricer choosing the fees when none are passed per call.
+// Dynamic fees are submitted as Cobo EIP-1559 fees, legacy fees as Cobo legacy fees.
+func WithCoboGasPricer(pricer sender.GasPricer) CoboMpcTransactionSenderOption {
+	return WithCustodyGasPricer(pricer)
+}
 
-	txDetail, err := s.signer.WaitTransactionStatus(txResp.TransactionId, cobo_waas2.TRANSACTIONSTATUS_CONFIRMING, 100)
-	if err != nil {
-		return common.Hash{}, err
-	}
+// WithCoboRetryPolicy sets how transient RPC errors of gas pricing and gas estimation are retried
+// (default: retry.DefaultPolicy)
+func WithCoboRetryPolicy(policy retry.Policy) CoboMpcTransactionSenderOption {
+	return WithCustodyRetryPolicy(policy)
+}
 
-	txHash := common.HexToHash(*txDetail.TransactionHash)
-	return txHash, nil
+// GetTransactionSenderByCoboMpcTransactionSender creates a TransactionSender for Cobo MPC
+func GetTransactionSenderByCoboMpcTransactionSender(client bind.ContractBackend, mpcSigner *CoboMpcSigner, opts ...CoboMpcTransactionSenderOption) (sender.TransactionSender, error) {
+	return NewCustodyTransactionSender(client, mpcSigner, opts...), nil
 }
@@ -0,0 +1,133 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/journal"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+func TestTransactionSender_ExplicitNonceBypassesNonceManager(t *testing.T) {
+	m, client, from := newTestTxManager(t)
+	s := m.sender
+
+	if _, err := s.SendEthereumTransactionWithContext(context.Background(), testTxTo, nil, big.NewInt(0), sender.WithNonce(42)); err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	if got := client.lastSent().Nonce(); got != 42 {
+		t.Errorf("expected nonce 42, got %d", got)
+	}
+	if _, synced := s.NonceManager().Peek(from); synced {
+		t.Error("explicit nonce must not touch the NonceManager")
+	}
+}
+
+func TestTransactionSender_FeeCapsBuildDynamicFeeTx(t *testing.T) {
+	m, client, _ := newTestTxManager(t)
+
+	_, err := m.sender.SendEthereumTransactionWithContext(context.Background(), testTxTo, nil, big.NewInt(0),
+		sender.WithFeeCaps(big.NewInt(300), big.NewInt(30)), sender.WithGasLimit(60000), sender.WithNoEstimate())
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+
+	tx := client.lastSent()
+	if tx.Type() != types.DynamicFeeTxType {
+		t.Fatalf("expected dynamic fee tx, got type %d", tx.Type())
+	}
+	if tx.GasFeeCap().Int64() != 300 || tx.GasTipCap().Int64() != 30 || tx.Gas() != 60000 {
+		t.Errorf("unexpected fees/gas: feeCap=%s tipCap=%s gas=%d", tx.GasFeeCap(), tx.GasTipCap(), tx.Gas())
+	}
+}
+
+func TestTransactionSender_InvalidOptions(t *testing.T) {
+	m, client, _ := newTestTxManager(t)
+
+	if _, err := m.sender.SendEthereumTransactionWithContext(context.Background(), testTxTo, nil, big.NewInt(0), sender.WithNoEstimate()); err == nil {
+		t.Fatal("expected error for no-estimate without gas limit")
+	}
+	if len(client.sent) != 0 {
+		t.Error("nothing should be broadcast with invalid options")
+	}
+}
+
+func TestTxManager_SpeedUpKeepsDynamicFeeType(t *testing.T) {
+	m, client, _ := newTestTxManager(t)
+
+	hash, err := m.SendEthereumTransactionWithContext(context.Background(), testTxTo, nil, big.NewInt(0), sender.WithFeeCaps(big.NewInt(300), big.NewInt(30)))
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	if _, err := m.SpeedUp(context.Background(), hash); err != nil {
+		t.Fatalf("SpeedUp: %v", err)
+	}
+
+	tx := client.lastSent()
+	if tx.Type() != types.DynamicFeeTxType {
+		t.Fatalf("expected dynamic fee replacement, got type %d", tx.Type())
+	}
+	if tx.GasFeeCap().Int64() < 330 || tx.GasTipCap().Int64() < 33 {
+		t.Errorf("replacement fees not bumped by 10%%: feeCap=%s tipCap=%s", tx.GasFeeCap(), tx.GasTipCap())
+	}
+}
+
+func TestTransactionSender_UsesGasPricerWithUrgency(t *testing.T) {
+	m, client, _ := newTestTxManager(t)
+
+	if _, err := m.sender.SendEthereumTransactionWithContext(context.Background(), testTxTo, nil, big.NewInt(0), sender.WithUrgency(sender.UrgencyLow)); err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	if got, want := client.lastSent().GasPrice(), client.gasPrice; got.Cmp(want) != 0 {
+		t.Errorf("expected low urgency to pay the suggested price %s, got %s", want, got)
+	}
+
+	if _, err := m.sender.SendEthereumTransactionWithContext(context.Background(), testTxTo, nil, big.NewInt(0)); err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	want := new(big.Int).Div(new(big.Int).Mul(client.gasPrice, big.NewInt(13)), big.NewInt(10))
+	if got := client.lastSent().GasPrice(); got.Cmp(want) != 0 {
+		t.Errorf("expected normal urgency to pay 1.3x = %s, got %s", want, got)
+	}
+}
+
+func TestTransactionSender_JournalsSignedAndSent(t *testing.T) {
+	m, client, from := newTestTxManager(t)
+	j := journal.NewMemoryJournal()
+	entry := journal.NewEntry(from, common.Address{}, testTxTo, nil, big.NewInt(0))
+	_ = j.Put(entry)
+
+	hash, err := m.sender.SendEthereumTransactionWithContext(journal.NewContext(context.Background(), j, entry.ID), testTxTo, nil, big.NewInt(0))
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+
+	got, _ := j.Get(entry.ID)
+	if got.State != journal.StateSent || got.TxHash != hash || got.Nonce == nil {
+		t.Fatalf("unexpected journal entry: %+v", got)
+	}
+	raw, _ := client.lastSent().MarshalBinary()
+	if string(got.RawTx) != string(raw) {
+		t.Error("expected the signed transaction to be journaled")
+	}
+}
+
+func TestTransactionSender_AlreadyKnownIsSuccess(t *testing.T) {
+	m, client, from := newTestTxManager(t)
+	client.sendErr = errors.New("already known")
+
+	txHash, err := m.sender.SendEthereumTransactionWithContext(context.Background(), testTxTo, nil, big.NewInt(0))
+	if err != nil {
+		t.Fatalf("SendEthereumTransactionWithContext: %v", err)
+	}
+	if txHash == (common.Hash{}) {
+		t.Error("expected the hash of the known transaction")
+	}
+	if next, _ := m.sender.NonceManager().Peek(from); next != 4 {
+		t.Errorf("expected nonce 3 to be committed, next nonce is %d", next)
+	}
+}
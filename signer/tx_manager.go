@@ -0,0 +1,478 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/journal"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+// ErrTransactionCancelled is returned by TxManager.WaitMined when the transaction was replaced by a cancellation
+var ErrTransactionCancelled = errors.New("transaction was cancelled")
+
+// ErrNonceUsedElsewhere is returned when a transaction's nonce was consumed by a transaction not sent through the TxManager
+var ErrNonceUsedElsewhere = errors.New("nonce was used by another transaction")
+
+// ErrTransactionNotTracked is returned when a hash was not sent through the TxManager
+var ErrTransactionNotTracked = errors.New("transaction is not tracked by the tx manager")
+
+// minReplacementBumpPercent is the minimum fee bump nodes accept for a same-nonce replacement
+const minReplacementBumpPercent = 10
+
+// StuckPolicy decides what TxManager does with a transaction that is still pending after the stuck deadline
+type StuckPolicy int
+
+const (
+	// StuckPolicySpeedUp rebroadcasts the same transaction with bumped fees
+	StuckPolicySpeedUp StuckPolicy = iota
+	// StuckPolicyCancel replaces the transaction with a 0-value self-transfer
+	StuckPolicyCancel
+)
+
+// TxManager sends transactions through a TransactionSenderByTransactionSigner and keeps track of them
+// by sender address and nonce until they are mined.
+//
+// A transaction still pending after StuckAfter (or half the time left before the WaitMined deadline,
+// whichever is shorter) is rebroadcast with the same nonce and bumped fees
+// (or cancelled, depending on the StuckPolicy). Every hash broadcast for a nonce stays tracked,
+// so WaitMined reports whichever one was finally mined. A nonce is no longer tracked once WaitMined or Monitor
+// sees it mined, or sees it used by a transaction not sent through the TxManager.
+//
+// TxManager implements sender.TransactionSender and sender.MinedWaiter, so it can be passed anywhere
+// a TransactionSender is accepted, including as the transaction sender of a Safe trading signer.
+type TxManager struct {
+	sender *TransactionSenderByTransactionSigner
+
+	stuckAfter   time.Duration
+	pollInterval time.Duration
+	maxBumps     int
+	bumpPercent  int64
+	stuckPolicy  StuckPolicy
+
+	mu  sync.Mutex
+	txs map[common.Hash]*managedTx
+}
+
+// managedTx is one nonce of one sender, with every transaction broadcast for it
+type managedTx struct {
+	from      common.Address
+	nonce     uint64
+	hashes    []common.Hash
+	current   *types.Transaction
+	lastSent  time.Time
+	bumps     int
+	cancelled bool                 // set by the first cancellation; later bumps replace the self-transfer
+	cancels   map[common.Hash]bool // hashes broadcast as cancellations
+	// replaceErr is why the last replacement failed, reported if WaitMined gives up
+	replaceErr error
+
+	// ctx carries the journal entry of the original send, if any, so replacements are journaled too
+	journalCtx context.Context
+}
+
+// TxManagerOption configures optional fields on TxManager
+type TxManagerOption func(m *TxManager)
+
+// WithStuckAfter sets how long a transaction may stay pending before it is replaced (default: 30 seconds).
+// WaitMined replaces earlier if half the time left before its ctx deadline is shorter.
+func WithStuckAfter(d time.Duration) TxManagerOption {
+	return func(m *TxManager) {
+		m.stuckAfter = d
+	}
+}
+
+// WithPollInterval sets how often receipts are polled (default: 2 seconds)
+func WithPollInterval(d time.Duration) TxManagerOption {
+	return func(m *TxManager) {
+		m.pollInterval = d
+	}
+}
+
+// WithMaxBumps sets how many times a transaction is replaced before WaitMined keeps waiting without bumping (default: 5)
+func WithMaxBumps(n int) TxManagerOption {
+	return func(m *TxManager) {
+		m.maxBumps = n
+	}
+}
+
+// WithBumpPercent sets the fee increase applied to each replacement (default: 15).
+// Values below the 10% required by nodes for replacement are raised to 10.
+func WithBumpPercent(percent int64) TxManagerOption {
+	return func(m *TxManager) {
+		m.bumpPercent = percent
+	}
+}
+
+// WithStuckPolicy sets what happens to a stuck transaction (default: StuckPolicySpeedUp)
+func WithStuckPolicy(policy StuckPolicy) TxManagerOption {
+	return func(m *TxManager) {
+		m.stuckPolicy = policy
+	}
+}
+
+// NewTxManager creates a TxManager on top of the given sender
+func NewTxManager(s *TransactionSenderByTransactionSigner, opts ...TxManagerOption) *TxManager {
+	m := &TxManager{
+		sender:       s,
+		stuckAfter:   30 * time.Second,
+		pollInterval: 2 * time.Second,
+		maxBumps:     5,
+		bumpPercent:  15,
+		stuckPolicy:  StuckPolicySpeedUp,
+		txs:          make(map[common.Hash]*managedTx),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.bumpPercent < minReplacementBumpPercent {
+		m.bumpPercent = minReplacementBumpPercent
+	}
+	return m
+}
+
+// TransactionSender returns the underlying transaction sender
+func (m *TxManager) TransactionSender() sender.TransactionSender {
+	return m.sender
+}
+
+// SendEthereumTransaction sends a transaction and starts tracking it
+func (m *TxManager) SendEthereumTransaction(to common.Address, data []byte, value *big.Int) (common.Hash, error) {
+	return m.SendEthereumTransactionWithContext(context.Background(), to, data, value)
+}
+
+// SendEthereumTransactionWithContext sends a transaction with per-call options and starts tracking it
+func (m *TxManager) SendEthereumTransactionWithContext(ctx context.Context, to common.Address, data []byte, value *big.Int, opts ...sender.SendOption) (common.Hash, error) {
+	signedTx, err := m.sender.sendTransaction(ctx, to, data, value, sender.ApplySendOptions(opts...))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx := &managedTx{
+		from:     m.sender.GetAddress(),
+		nonce:    signedTx.Nonce(),
+		hashes:   []common.Hash{signedTx.Hash()},
+		current:  signedTx,
+		lastSent: time.Now(),
+	}
+	if j, id, ok := journal.FromContext(ctx); ok {
+		tx.journalCtx = journal.NewContext(context.Background(), j, id)
+	}
+	m.txs[signedTx.Hash()] = tx
+	return signedTx.Hash(), nil
+}
+
+// Pending returns the hashes of the latest broadcast transaction for every tracked, not yet mined nonce
+func (m *TxManager) Pending() []common.Hash {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[*managedTx]bool)
+	var hashes []common.Hash
+	for _, tx := range m.txs {
+		if seen[tx] {
+			continue
+		}
+		seen[tx] = true
+		hashes = append(hashes, tx.current.Hash())
+	}
+	return hashes
+}
+
+// SpeedUp rebroadcasts the tracked transaction with the same nonce and bumped fees.
+// txHash may be any hash previously broadcast for that nonce. It returns the new hash.
+// Once the nonce was cancelled, the cancellation is sped up instead: a cancellation is never undone.
+func (m *TxManager) SpeedUp(ctx context.Context, txHash common.Hash) (common.Hash, error) {
+	tx, err := m.lookup(txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return m.replace(ctx, tx, false)
+}
+
+// Cancel replaces the tracked transaction with a 0-value self-transfer using the same nonce and bumped fees.
+// txHash may be any hash previously broadcast for that nonce. It returns the hash of the cancellation.
+func (m *TxManager) Cancel(ctx context.Context, txHash common.Hash) (common.Hash, error) {
+	tx, err := m.lookup(txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return m.replace(ctx, tx, true)
+}
+
+// WaitMined waits until one of the transactions broadcast for txHash's nonce is mined and returns its receipt.
+// Stuck transactions are replaced according to the StuckPolicy, so receipt.TxHash is the final mined hash.
+// If ctx has a deadline, a transaction counts as stuck after half the remaining time at most, so a replacement
+// is still broadcast before the caller gives up.
+// If the mined transaction is a cancellation, the receipt is returned together with ErrTransactionCancelled.
+// Hashes not sent through this TxManager are simply polled until mined.
+func (m *TxManager) WaitMined(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	tx, err := m.lookup(txHash)
+	if errors.Is(err, ErrTransactionNotTracked) {
+		tx = &managedTx{hashes: []common.Hash{txHash}}
+	}
+
+	stuckAfter := m.stuckAfter
+	if deadline, ok := ctx.Deadline(); ok {
+		if half := time.Until(deadline) / 2; half < stuckAfter {
+			stuckAfter = half
+		}
+	}
+
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := m.poll(ctx, tx, stuckAfter)
+		if receipt != nil || err != nil {
+			return receipt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			m.mu.Lock()
+			replaceErr := tx.replaceErr
+			m.mu.Unlock()
+			if replaceErr != nil {
+				return nil, fmt.Errorf("tx %s not mined: %w (last replacement failed: %v)", txHash.Hex(), ctx.Err(), replaceErr)
+			}
+			return nil, fmt.Errorf("tx %s not mined: %w", txHash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Monitor replaces stuck transactions in the background until ctx is done.
+// It is only needed for transactions nobody waits on with WaitMined.
+func (m *TxManager) Monitor(ctx context.Context) {
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		m.mu.Lock()
+		seen := make(map[*managedTx]bool)
+		var txs []*managedTx
+		for _, tx := range m.txs {
+			if !seen[tx] {
+				seen[tx] = true
+				txs = append(txs, tx)
+			}
+		}
+		m.mu.Unlock()
+
+		for _, tx := range txs {
+			_, _ = m.poll(ctx, tx, m.stuckAfter)
+		}
+	}
+}
+
+// poll checks every hash broadcast for the nonce and replaces the transaction if it has been pending for stuckAfter.
+// It returns a nil receipt and nil error while the transaction is still pending: a failed replacement is recorded
+// and retried on the next poll, as the transaction already broadcast may still be mined.
+func (m *TxManager) poll(ctx context.Context, tx *managedTx, stuckAfter time.Duration) (*types.Receipt, error) {
+	if receipt, err := m.mined(ctx, tx); receipt != nil {
+		return receipt, err
+	}
+
+	m.mu.Lock()
+	stuck := tx.current != nil && tx.bumps < m.maxBumps && time.Since(tx.lastSent) >= stuckAfter
+	m.mu.Unlock()
+	if !stuck {
+		return nil, nil
+	}
+
+	_, err := m.replace(ctx, tx, m.stuckPolicy == StuckPolicyCancel)
+	if err != nil && isNonceTooLowErr(err) {
+		// One of the hashes was mined since the receipts were read, or the nonce went to another transaction
+		if receipt, err := m.mined(ctx, tx); receipt != nil {
+			return receipt, err
+		}
+		m.forget(tx)
+		return nil, fmt.Errorf("%w: nonce %d of %s", ErrNonceUsedElsewhere, tx.nonce, tx.from.Hex())
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil && !isAlreadyMinedErr(err) {
+		tx.replaceErr = err
+	} else {
+		tx.replaceErr = nil
+	}
+	return nil, nil
+}
+
+// mined returns the receipt of whichever hash broadcast for tx was mined, if any, and stops tracking tx then
+func (m *TxManager) mined(ctx context.Context, tx *managedTx) (*types.Receipt, error) {
+	m.mu.Lock()
+	hashes := append([]common.Hash(nil), tx.hashes...)
+	m.mu.Unlock()
+
+	for _, h := range hashes {
+		receipt, err := m.sender.client.TransactionReceipt(ctx, h)
+		if err != nil || receipt == nil {
+			continue
+		}
+
+		m.forget(tx)
+
+		m.mu.Lock()
+		cancelled := tx.cancels[receipt.TxHash]
+		m.mu.Unlock()
+		if cancelled {
+			return receipt, ErrTransactionCancelled
+		}
+		return receipt, nil
+	}
+	return nil, nil
+}
+
+// replace signs and broadcasts a same-nonce replacement for tx, either a fee bump or a cancellation
+func (m *TxManager) replace(ctx context.Context, tx *managedTx, cancel bool) (common.Hash, error) {
+	m.mu.Lock()
+	prev := tx.current
+	m.mu.Unlock()
+
+	to, data, value, gas := prev.To(), prev.Data(), prev.Value(), prev.Gas()
+	if cancel {
+		self := tx.from
+		to, data, value, gas = &self, nil, big.NewInt(0), params.TxGas
+	}
+
+	// Replacements are priced as urgent: the bumped fees are raised to the current urgent price if lower
+	fees, err := m.sender.gasPricer.GasFees(ctx, sender.UrgencyHigh)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to price replacement gas: %w", err)
+	}
+
+	var unsigned *types.Transaction
+	switch prev.Type() {
+	case types.DynamicFeeTxType:
+		tipCap := fees.GasTipCap
+		if !fees.IsDynamic() {
+			if tipCap, err = m.sender.client.SuggestGasTipCap(ctx); err != nil {
+				return common.Hash{}, fmt.Errorf("failed to get gas tip cap: %w", err)
+			}
+		}
+		tipCap = maxBig(bumpFee(prev.GasTipCap(), m.bumpPercent), tipCap)
+		feeCap := bumpFee(prev.GasFeeCap(), m.bumpPercent)
+		if fees.IsDynamic() {
+			feeCap = maxBig(feeCap, fees.GasFeeCap)
+		}
+		if feeCap.Cmp(tipCap) < 0 {
+			feeCap = new(big.Int).Set(tipCap)
+		}
+		unsigned = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   m.sender.chainId,
+			Nonce:     tx.nonce,
+			GasTipCap: tipCap,
+			GasFeeCap: feeCap,
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		})
+	default:
+		unsigned = types.NewTx(&types.LegacyTx{
+			Nonce:    tx.nonce,
+			GasPrice: maxBig(bumpFee(prev.GasPrice(), m.bumpPercent), fees.Price()),
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		})
+	}
+
+	signedTx, err := m.sender.txSigner.SignTransactionWithChainID(unsigned, m.sender.chainId)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to sign replacement transaction: %w", err)
+	}
+	if err := m.sender.client.SendTransaction(ctx, signedTx); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send replacement transaction: %w", err)
+	}
+	if tx.journalCtx != nil {
+		// The replacement is out either way; journal.Recover falls back to the nonce check if this fails
+		if err := journalSigned(tx.journalCtx, signedTx); err == nil {
+			_ = journal.Record(tx.journalCtx, func(e *journal.Entry) { e.State = journal.StateSent })
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx.current = signedTx
+	tx.hashes = append(tx.hashes, signedTx.Hash())
+	tx.lastSent = time.Now()
+	tx.bumps++
+	if cancel && !tx.cancelled {
+		tx.cancelled = true
+		tx.cancels = make(map[common.Hash]bool)
+	}
+	if tx.cancelled {
+		// Once cancelled, every replacement rebuilds the self-transfer, so it is a cancellation too
+		tx.cancels[signedTx.Hash()] = true
+	}
+	m.txs[signedTx.Hash()] = tx
+
+	return signedTx.Hash(), nil
+}
+
+func (m *TxManager) lookup(txHash common.Hash) (*managedTx, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx, ok := m.txs[txHash]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotTracked, txHash.Hex())
+	}
+	return tx, nil
+}
+
+func (m *TxManager) forget(tx *managedTx) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, h := range tx.hashes {
+		delete(m.txs, h)
+	}
+}
+
+// bumpFee returns fee increased by percent, rounded up so the result always satisfies the node's replacement threshold
+func bumpFee(fee *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return new(big.Int).Set(b)
+}
+
+// isNonceTooLowErr reports whether a transaction was refused because its nonce was already mined
+func isNonceTooLowErr(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+// isAlreadyMinedErr reports whether a replacement failed because a transaction with the same nonce was already mined
+func isAlreadyMinedErr(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "already known")
+}
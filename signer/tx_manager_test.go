@@ -0,0 +1,358 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	ethclient "github.com/ivanzzeth/ethclient"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+)
+
+// mockTxClient records broadcast transactions and mines them on demand.
+// Methods not overridden panic through the nil embedded interface.
+type mockTxClient struct {
+	ethclient.EthClientInterface
+
+	mu       sync.Mutex
+	gasPrice *big.Int
+	sent     []*types.Transaction
+	mined    map[common.Hash]bool
+	sendErr  error
+}
+
+func newMockTxClient() *mockTxClient {
+	return &mockTxClient{gasPrice: big.NewInt(100), mined: make(map[common.Hash]bool)}
+}
+
+func (c *mockTxClient) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return new(big.Int).Set(c.gasPrice), nil
+}
+
+func (c *mockTxClient) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (c *mockTxClient) EstimateGas(_ context.Context, _ ethereum.CallMsg) (uint64, error) {
+	return 50000, nil
+}
+
+func (c *mockTxClient) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	return 3, nil
+}
+
+func (c *mockTxClient) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sendErr != nil {
+		return c.sendErr
+	}
+	c.sent = append(c.sent, tx)
+	return nil
+}
+
+func (c *mockTxClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.mined[txHash] {
+		return nil, ethereum.NotFound
+	}
+	return &types.Receipt{TxHash: txHash, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(1)}, nil
+}
+
+func (c *mockTxClient) mine(txHash common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mined[txHash] = true
+}
+
+func (c *mockTxClient) lastSent() *types.Transaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sent[len(c.sent)-1]
+}
+
+// testTxSigner signs with a private key through go-ethereum directly
+type testTxSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *testTxSigner) GetAddress() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *testTxSigner) SignTransactionWithChainID(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func newTestTxManager(t *testing.T, opts ...TxManagerOption) (*TxManager, *mockTxClient, common.Address) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	client := newMockTxClient()
+	txSigner := &testTxSigner{key: key}
+	s := NewTransactionSenderByTransactionSigner(big.NewInt(137), client, txSigner, WithNonceManager(NewNonceManager(client)))
+	return NewTxManager(s, opts...), client, txSigner.GetAddress()
+}
+
+var testTxTo = common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+func TestTxManager_SpeedUpBumpsFeeAndKeepsNonce(t *testing.T) {
+	m, client, _ := newTestTxManager(t)
+	ctx := context.Background()
+
+	hash, err := m.SendEthereumTransaction(testTxTo, []byte{0x01}, big.NewInt(0))
+	if err != nil {
+		t.Fatalf("SendEthereumTransaction: %v", err)
+	}
+	original := client.lastSent()
+
+	newHash, err := m.SpeedUp(ctx, hash)
+	if err != nil {
+		t.Fatalf("SpeedUp: %v", err)
+	}
+	replacement := client.lastSent()
+
+	if newHash == hash || replacement.Hash() != newHash {
+		t.Fatalf("expected a new broadcast hash, got %s", newHash.Hex())
+	}
+	if replacement.Nonce() != original.Nonce() {
+		t.Errorf("expected nonce %d, got %d", original.Nonce(), replacement.Nonce())
+	}
+	minPrice := new(big.Int).Mul(original.GasPrice(), big.NewInt(110))
+	minPrice.Div(minPrice, big.NewInt(100))
+	if replacement.GasPrice().Cmp(minPrice) < 0 {
+		t.Errorf("gas price %s below replacement threshold %s", replacement.GasPrice(), minPrice)
+	}
+	if string(replacement.Data()) != string(original.Data()) || *replacement.To() != testTxTo {
+		t.Error("speed-up must keep the original call")
+	}
+}
+
+func TestTxManager_CancelSendsSelfTransfer(t *testing.T) {
+	m, client, from := newTestTxManager(t)
+	ctx := context.Background()
+
+	hash, _ := m.SendEthereumTransaction(testTxTo, []byte{0x01}, big.NewInt(5))
+	original := client.lastSent()
+
+	cancelHash, err := m.Cancel(ctx, hash)
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	cancelTx := client.lastSent()
+
+	if *cancelTx.To() != from || cancelTx.Value().Sign() != 0 || len(cancelTx.Data()) != 0 || cancelTx.Gas() != 21000 {
+		t.Errorf("expected 0-value self-transfer, got to=%s value=%s gas=%d", cancelTx.To().Hex(), cancelTx.Value(), cancelTx.Gas())
+	}
+	if cancelTx.Nonce() != original.Nonce() {
+		t.Errorf("expected nonce %d, got %d", original.Nonce(), cancelTx.Nonce())
+	}
+	if urgent := new(big.Int).Mul(client.gasPrice, big.NewInt(2)); cancelTx.GasPrice().Cmp(urgent) < 0 {
+		t.Errorf("expected the cancellation priced at high urgency (>= %s), got %s", urgent, cancelTx.GasPrice())
+	}
+
+	client.mine(cancelHash)
+	receipt, err := m.WaitMined(ctx, hash)
+	if !errors.Is(err, ErrTransactionCancelled) {
+		t.Fatalf("expected ErrTransactionCancelled, got %v", err)
+	}
+	if receipt == nil || receipt.TxHash != cancelHash {
+		t.Errorf("expected cancellation receipt for %s", cancelHash.Hex())
+	}
+}
+
+func TestTxManager_CancelStaysCancelledAfterBump(t *testing.T) {
+	m, client, from := newTestTxManager(t, WithStuckAfter(0), WithPollInterval(time.Millisecond), WithMaxBumps(2))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hash, _ := m.SendEthereumTransaction(testTxTo, []byte{0x01}, big.NewInt(5))
+	if _, err := m.Cancel(ctx, hash); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	// The speed-up stuck policy bumps the cancellation once more; mine that bump
+	go func() {
+		for {
+			client.mu.Lock()
+			n := len(client.sent)
+			client.mu.Unlock()
+			if n > 2 {
+				client.mine(client.lastSent().Hash())
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}()
+
+	receipt, err := m.WaitMined(ctx, hash)
+	if !errors.Is(err, ErrTransactionCancelled) {
+		t.Fatalf("expected ErrTransactionCancelled, got %v", err)
+	}
+	bumped := client.lastSent()
+	if receipt == nil || receipt.TxHash != bumped.Hash() {
+		t.Fatal("expected the receipt of the bumped cancellation")
+	}
+	if *bumped.To() != from || bumped.Value().Sign() != 0 || len(bumped.Data()) != 0 {
+		t.Errorf("expected the bump to keep the self-transfer, got to=%s value=%s", bumped.To().Hex(), bumped.Value())
+	}
+}
+
+func TestTxManager_WaitMinedReplacesBeforeDeadline(t *testing.T) {
+	// The default StuckAfter is longer than the caller's timeout: the deadline must bring the replacement forward
+	m, client, _ := newTestTxManager(t, WithStuckAfter(time.Hour), WithPollInterval(time.Millisecond), WithMaxBumps(1))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	hash, _ := m.SendEthereumTransaction(testTxTo, nil, big.NewInt(0))
+	_, _ = m.WaitMined(ctx, hash)
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.sent) < 2 {
+		t.Error("expected the transaction to be replaced before the WaitMined deadline")
+	}
+}
+
+func TestTxManager_WaitMinedReplacesStuckTx(t *testing.T) {
+	m, client, _ := newTestTxManager(t, WithStuckAfter(0), WithPollInterval(time.Millisecond), WithMaxBumps(1))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hash, _ := m.SendEthereumTransaction(testTxTo, nil, big.NewInt(0))
+
+	// Mine whichever replacement shows up first
+	go func() {
+		for {
+			client.mu.Lock()
+			n := len(client.sent)
+			client.mu.Unlock()
+			if n > 1 {
+				client.mine(client.lastSent().Hash())
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}()
+
+	receipt, err := m.WaitMined(ctx, hash)
+	if err != nil {
+		t.Fatalf("WaitMined: %v", err)
+	}
+	if receipt.TxHash == hash {
+		t.Error("expected the replacement hash to be reported")
+	}
+	if len(m.Pending()) != 0 {
+		t.Error("mined transaction should no longer be tracked")
+	}
+}
+
+func TestTxManager_WaitMinedReportsOriginalWhenMined(t *testing.T) {
+	m, client, _ := newTestTxManager(t, WithPollInterval(time.Millisecond))
+
+	hash, _ := m.SendEthereumTransaction(testTxTo, nil, big.NewInt(0))
+	client.mine(hash)
+
+	receipt, err := m.WaitMined(context.Background(), hash)
+	if err != nil {
+		t.Fatalf("WaitMined: %v", err)
+	}
+	if receipt.TxHash != hash {
+		t.Errorf("expected %s, got %s", hash.Hex(), receipt.TxHash.Hex())
+	}
+}
+
+func TestTxManager_WaitMinedSurvivesFailedReplacement(t *testing.T) {
+	m, client, _ := newTestTxManager(t, WithStuckAfter(0), WithPollInterval(time.Millisecond))
+
+	hash, _ := m.SendEthereumTransaction(testTxTo, nil, big.NewInt(0))
+	client.mu.Lock()
+	client.sendErr = errors.New("replacement transaction underpriced")
+	client.mu.Unlock()
+
+	// Nothing is mined: WaitMined gives up at the deadline, reporting the failed replacements
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := m.WaitMined(ctx, hash); !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "underpriced") {
+		t.Fatalf("expected the deadline error with the replacement failure, got %v", err)
+	}
+
+	// The original is mined while its replacements keep failing
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		client.mine(hash)
+	}()
+	receipt, err := m.WaitMined(context.Background(), hash)
+	if err != nil {
+		t.Fatalf("WaitMined: %v", err)
+	}
+	if receipt.TxHash != hash {
+		t.Errorf("expected %s, got %s", hash.Hex(), receipt.TxHash.Hex())
+	}
+}
+
+func TestTxManager_ForgetsNonceUsedElsewhere(t *testing.T) {
+	m, client, _ := newTestTxManager(t, WithStuckAfter(0), WithPollInterval(time.Millisecond))
+
+	hash, _ := m.SendEthereumTransaction(testTxTo, nil, big.NewInt(0))
+	// The nonce is mined by a transaction the manager never sent
+	client.mu.Lock()
+	client.sendErr = errors.New("nonce too low")
+	client.mu.Unlock()
+
+	if _, err := m.WaitMined(context.Background(), hash); !errors.Is(err, ErrNonceUsedElsewhere) {
+		t.Fatalf("expected ErrNonceUsedElsewhere, got %v", err)
+	}
+	if len(m.Pending()) != 0 {
+		t.Error("a nonce used elsewhere should no longer be tracked")
+	}
+}
+
+func TestTxManager_SpeedUpUntracked(t *testing.T) {
+	m, _, _ := newTestTxManager(t)
+
+	if _, err := m.SpeedUp(context.Background(), common.HexToHash("0xdead")); !errors.Is(err, ErrTransactionNotTracked) {
+		t.Fatalf("expected ErrTransactionNotTracked, got %v", err)
+	}
+}
+
+func TestAsMinedWaiter_UnwrapsSafeSigner(t *testing.T) {
+	m, _, from := newTestTxManager(t)
+	safeSigner := NewSimpleSafeTradingSigner(from, nil, m)
+
+	waiter, ok := sender.AsMinedWaiter(safeSigner)
+	if !ok || waiter != m {
+		t.Fatal("expected the TxManager to be found behind the Safe signer")
+	}
+}
+
+func TestBumpFee_RoundsUp(t *testing.T) {
+	if got := bumpFee(big.NewInt(1), 10); got.Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("expected 2, got %s", got)
+	}
+	if got := bumpFee(big.NewInt(100), 15); got.Cmp(big.NewInt(115)) != 0 {
+		t.Errorf("expected 115, got %s", got)
+	}
+}
@@ -0,0 +1,202 @@
+package polymarketcontracts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+// PositionMigration is the outcome of moving every position of an account to another account
+type PositionMigration struct {
+	From common.Address
+	To   common.Address
+	// PositionIds and Amounts are the transferred balances
+	PositionIds []*big.Int
+	Amounts     []*big.Int
+	// TxHash is the transfer transaction, zero when there was nothing to transfer
+	TxHash common.Hash
+}
+
+// transferPositions transfers outcome tokens of from to to in one ConditionalTokens transaction.
+// A Safe (safeSigner set) is the sender of its own transfer, so no approval is needed.
+func transferPositions(ctx context.Context, e *txExecutor, ctf common.Address, safeSigner signer.SafeTradingSigner, chainID *big.Int, from, to common.Address, ids, amounts []*big.Int) (common.Hash, error) {
+	if to == (common.Address{}) || to == from {
+		return common.Hash{}, fmt.Errorf("invalid transfer recipient %s", to.Hex())
+	}
+	call, err := buildPositionTransferCall(ctf, from, to, ids, amounts)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if safeSigner == nil {
+		return e.executeEOA(ctx, call)
+	}
+	return e.executeSafe(ctx, safeSigner, chainID, call)
+}
+
+// migratePositions transfers every position account holds of conditions to to in one transaction
+func (r *portfolioReader) migratePositions(ctx context.Context, e *txExecutor, safeSigner signer.SafeTradingSigner, chainID *big.Int, account, to common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*PositionMigration, error) {
+	portfolio, err := r.getPortfolio(ctx, account, conditions, opts...)
+	if err != nil {
+		return nil, err
+	}
+	migration := &PositionMigration{From: account, To: to}
+	for _, holdings := range portfolio.Conditions {
+		for _, outcome := range holdings.Outcomes {
+			if outcome.Balance.Sign() > 0 {
+				migration.PositionIds = append(migration.PositionIds, outcome.PositionId)
+				migration.Amounts = append(migration.Amounts, outcome.Balance)
+			}
+		}
+	}
+	if len(migration.PositionIds) == 0 {
+		return migration, nil
+	}
+	migration.TxHash, err = transferPositions(ctx, e, r.config.ConditionalTokens, safeSigner, chainID, account, to, migration.PositionIds, migration.Amounts)
+	if err != nil {
+		return migration, fmt.Errorf("failed to migrate positions: %w", err)
+	}
+	return migration, nil
+}
+
+// TransferPosition transfers amount of a position from the configured account to to
+func (b *ContractInterface) TransferPosition(ctx context.Context, to common.Address, positionId, amount *big.Int) (common.Hash, error) {
+	return b.TransferPositions(ctx, to, []*big.Int{positionId}, []*big.Int{amount})
+}
+
+// TransferPositions transfers amounts of positions from the configured account to to in one transaction
+func (b *ContractInterface) TransferPositions(ctx context.Context, to common.Address, positionIds, amounts []*big.Int) (common.Hash, error) {
+	switch b.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		return b.TransferPositionsForSafe(ctx, b.getSafeTradingSigner(), b.chainID, to, positionIds, amounts)
+	case SignatureTypeEOA:
+		return b.TransferPositionsForEOA(ctx, b.getEOATradingSigner(), to, positionIds, amounts)
+	default:
+		return common.Hash{}, fmt.Errorf("unsupported signature type: %v", b.signatureType)
+	}
+}
+
+// TransferPositionsForEOA transfers positions of an EOA
+func (b *ContractInterface) TransferPositionsForEOA(ctx context.Context, eoaSigner signer.EOATradingSigner, to common.Address, positionIds, amounts []*big.Int) (common.Hash, error) {
+	owner, err := b.executor.eoaAddress(eoaSigner)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return transferPositions(ctx, b.executor, b.contractConfig.ConditionalTokens, nil, nil, owner, to, positionIds, amounts)
+}
+
+// TransferPositionsForSafe transfers positions of a Safe
+func (b *ContractInterface) TransferPositionsForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, to common.Address, positionIds, amounts []*big.Int) (common.Hash, error) {
+	safeAddr, err := b.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return transferPositions(ctx, b.executor, b.contractConfig.ConditionalTokens, safeSigner, chainID, safeAddr, to, positionIds, amounts)
+}
+
+// MigratePositions transfers every position the configured account holds of conditions to to, e.g. from an old
+// Safe to a new one. Use DiscoverPortfolioConditions to find the conditions.
+func (b *ContractInterface) MigratePositions(ctx context.Context, to common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*PositionMigration, error) {
+	switch b.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		return b.MigratePositionsForSafe(ctx, b.getSafeTradingSigner(), b.chainID, to, conditions, opts...)
+	case SignatureTypeEOA:
+		return b.MigratePositionsForEOA(ctx, b.getEOATradingSigner(), to, conditions, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported signature type: %v", b.signatureType)
+	}
+}
+
+// MigratePositionsForEOA transfers every position an EOA holds of conditions to to
+func (b *ContractInterface) MigratePositionsForEOA(ctx context.Context, eoaSigner signer.EOATradingSigner, to common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*PositionMigration, error) {
+	owner, err := b.executor.eoaAddress(eoaSigner)
+	if err != nil {
+		return nil, err
+	}
+	return b.portfolio.migratePositions(ctx, b.executor, nil, nil, owner, to, conditions, opts...)
+}
+
+// MigratePositionsForSafe transfers every position a Safe holds of conditions to to
+func (b *ContractInterface) MigratePositionsForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, to common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*PositionMigration, error) {
+	safeAddr, err := b.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return b.portfolio.migratePositions(ctx, b.executor, safeSigner, chainID, safeAddr, to, conditions, opts...)
+}
+
+// TransferPosition transfers amount of a position from the configured account to to
+func (v *ContractInterfaceV2) TransferPosition(ctx context.Context, to common.Address, positionId, amount *big.Int) (common.Hash, error) {
+	return v.TransferPositions(ctx, to, []*big.Int{positionId}, []*big.Int{amount})
+}
+
+// TransferPositions transfers amounts of positions from the configured account to to in one transaction
+func (v *ContractInterfaceV2) TransferPositions(ctx context.Context, to common.Address, positionIds, amounts []*big.Int) (common.Hash, error) {
+	switch v.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		s, err := v.getSafeTradingSignerOrErr()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return v.TransferPositionsForSafe(ctx, s, v.chainID, to, positionIds, amounts)
+	case SignatureTypeEOA:
+		return v.TransferPositionsForEOA(ctx, to, positionIds, amounts)
+	default:
+		return common.Hash{}, fmt.Errorf("unsupported signature type: %v", v.signatureType)
+	}
+}
+
+// TransferPositionsForEOA transfers positions of the EOA
+func (v *ContractInterfaceV2) TransferPositionsForEOA(ctx context.Context, to common.Address, positionIds, amounts []*big.Int) (common.Hash, error) {
+	eoa, err := v.getEOAAddress()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return transferPositions(ctx, v.executor, v.config.ConditionalTokens, nil, nil, eoa, to, positionIds, amounts)
+}
+
+// TransferPositionsForSafe transfers positions of a Safe
+func (v *ContractInterfaceV2) TransferPositionsForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, to common.Address, positionIds, amounts []*big.Int) (common.Hash, error) {
+	safeAddr, err := v.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return transferPositions(ctx, v.executor, v.config.ConditionalTokens, safeSigner, chainID, safeAddr, to, positionIds, amounts)
+}
+
+// MigratePositions transfers every position the configured account holds of conditions to to, e.g. from an old
+// Safe to a new one. Use DiscoverPortfolioConditions to find the conditions.
+func (v *ContractInterfaceV2) MigratePositions(ctx context.Context, to common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*PositionMigration, error) {
+	switch v.signatureType {
+	case SignatureTypePolyGnosisSafe:
+		s, err := v.getSafeTradingSignerOrErr()
+		if err != nil {
+			return nil, err
+		}
+		return v.MigratePositionsForSafe(ctx, s, v.chainID, to, conditions, opts...)
+	case SignatureTypeEOA:
+		return v.MigratePositionsForEOA(ctx, to, conditions, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported signature type: %v", v.signatureType)
+	}
+}
+
+// MigratePositionsForEOA transfers every position the EOA holds of conditions to to
+func (v *ContractInterfaceV2) MigratePositionsForEOA(ctx context.Context, to common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*PositionMigration, error) {
+	eoa, err := v.getEOAAddress()
+	if err != nil {
+		return nil, err
+	}
+	return v.portfolio.migratePositions(ctx, v.executor, nil, nil, eoa, to, conditions, opts...)
+}
+
+// MigratePositionsForSafe transfers every position a Safe holds of conditions to to
+func (v *ContractInterfaceV2) MigratePositionsForSafe(ctx context.Context, safeSigner signer.SafeTradingSigner, chainID *big.Int, to common.Address, conditions []PortfolioCondition, opts ...PortfolioOption) (*PositionMigration, error) {
+	safeAddr, err := v.executor.getSafeAddr(safeSigner.GetAddress())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe address: %w", err)
+	}
+	return v.portfolio.migratePositions(ctx, v.executor, safeSigner, chainID, safeAddr, to, conditions, opts...)
+}
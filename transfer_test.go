@@ -0,0 +1,82 @@
+package polymarketcontracts
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/sender"
+	"github.com/ivanzzeth/polymarket-go-contracts/v2/signer"
+)
+
+func TestTransferPositionsForEOA(t *testing.T) {
+	v := newV2TestInstance(nil)
+	eoa := &addrSender{addr: common.HexToAddress("0xE0A")}
+	v.executor.txSender = eoa
+	to := common.HexToAddress("0xBEEF")
+
+	if _, err := v.TransferPositionsForEOA(context.Background(), to, []*big.Int{big.NewInt(7)}, []*big.Int{big.NewInt(5)}); err != nil {
+		t.Fatalf("TransferPositionsForEOA: %v", err)
+	}
+	single, _ := buildPositionTransferCall(MATIC_CONTRACTS.ConditionalTokens, eoa.addr, to, []*big.Int{big.NewInt(7)}, []*big.Int{big.NewInt(5)})
+	if eoa.lastTo != MATIC_CONTRACTS.ConditionalTokens || !bytes.Equal(eoa.lastData, single.Calldata) || !bytes.Equal(eoa.lastData[:4], common.FromHex("0xf242432a")) {
+		t.Errorf("single transfer to %s: %x", eoa.lastTo.Hex(), eoa.lastData)
+	}
+
+	if _, err := v.TransferPositionsForEOA(context.Background(), to, []*big.Int{big.NewInt(7), big.NewInt(8)}, []*big.Int{big.NewInt(5), big.NewInt(6)}); err != nil {
+		t.Fatalf("TransferPositionsForEOA: %v", err)
+	}
+	if !bytes.Equal(eoa.lastData[:4], common.FromHex("0x2eb2c2d6")) {
+		t.Errorf("batch transfer selector %x, want safeBatchTransferFrom", eoa.lastData[:4])
+	}
+
+	for name, recipient := range map[string]common.Address{"zero address": {}, "self": eoa.addr} {
+		if _, err := v.TransferPositionsForEOA(context.Background(), recipient, []*big.Int{big.NewInt(7)}, []*big.Int{big.NewInt(5)}); err == nil {
+			t.Errorf("%s: expected the transfer to fail", name)
+		}
+	}
+	if _, err := v.TransferPositionsForEOA(context.Background(), to, []*big.Int{big.NewInt(7)}, nil); err == nil || eoa.calls != 2 {
+		t.Errorf("got %v after %d transactions, want mismatched amounts rejected", err, eoa.calls)
+	}
+}
+
+func TestMigratePositionsForSafe(t *testing.T) {
+	chain, conditions := newRedeemChain(t)
+	reader, err := newPortfolioReader(MATIC_CONTRACTS, chain)
+	if err != nil {
+		t.Fatalf("newPortfolioReader: %v", err)
+	}
+	var sent []contractCall
+	b := &ContractInterface{
+		contractConfig: MATIC_CONTRACTS,
+		portfolio:      reader,
+		executor: &txExecutor{
+			getSafeAddr: func(common.Address) (common.Address, error) { return testSafe, nil },
+			execSafeTx: func(_ context.Context, _ signer.SafeTradingSigner, _ *big.Int, _, to common.Address, value *big.Int, data []byte, op SafeOperation, _ *big.Int, _ ...sender.SendOption) (common.Hash, error) {
+				sent = append(sent, contractCall{Target: to, Calldata: data, Value: value, Operation: op})
+				return common.HexToHash("0x01"), nil
+			},
+		},
+	}
+	newSafe := common.HexToAddress("0x5AFE2")
+
+	migration, err := b.MigratePositionsForSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), newSafe, conditions)
+	if err != nil {
+		t.Fatalf("MigratePositionsForSafe: %v", err)
+	}
+	// One position of each of the three conditions, resolved or not
+	if migration.From != testSafe || len(migration.PositionIds) != 3 || migration.TxHash != common.HexToHash("0x01") {
+		t.Errorf("migration %+v", migration)
+	}
+	want, _ := buildPositionTransferCall(MATIC_CONTRACTS.ConditionalTokens, testSafe, newSafe, migration.PositionIds, migration.Amounts)
+	if len(sent) != 1 || sent[0].Target != MATIC_CONTRACTS.ConditionalTokens || !bytes.Equal(sent[0].Calldata, want.Calldata) {
+		t.Errorf("sent %+v", sent)
+	}
+
+	chain.balances = map[string]int64{}
+	if migration, err = b.MigratePositionsForSafe(context.Background(), &mockSafeSigner{}, big.NewInt(137), newSafe, conditions); err != nil || migration.TxHash != (common.Hash{}) || len(sent) != 1 {
+		t.Errorf("got %+v, %v with nothing to migrate", migration, err)
+	}
+}